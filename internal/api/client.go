@@ -1,11 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -23,6 +26,9 @@ type Client struct {
 	httpClient *http.Client
 	creds      *secrets.Credentials
 	debug      bool
+	dryRun     *DryRun
+	observer   RequestObserver
+	limiter    *RateLimiter
 }
 
 func NewClient(creds *secrets.Credentials) *Client {
@@ -34,10 +40,26 @@ func NewClient(creds *secrets.Credentials) *Client {
 	}
 }
 
+// BaseURL returns the v1 API base URL the client talks to.
+func (c *Client) BaseURL() string {
+	return c.creds.BaseURL()
+}
+
 func (c *Client) SetDebug(debug bool) {
 	c.debug = debug
 }
 
+// SetRateLimiter makes the client wait for l before each request. Clients
+// sharing a limiter share its budget; nil disables limiting.
+func (c *Client) SetRateLimiter(l *RateLimiter) {
+	c.limiter = l
+}
+
+// RateLimiter returns the limiter set with SetRateLimiter, or nil.
+func (c *Client) RateLimiter() *RateLimiter {
+	return c.limiter
+}
+
 // SetHTTPClient sets a custom HTTP client (useful for testing)
 func (c *Client) SetHTTPClient(httpClient *http.Client) {
 	if httpClient == nil {
@@ -151,6 +173,33 @@ func (c *Client) doV2(ctx context.Context, method, path string, body io.Reader,
 // doRequest executes an HTTP request and decodes the response.
 // Shared by doWithOpts (v1) and doV2 to keep error handling and header logic in one place.
 func (c *Client) doRequest(ctx context.Context, method, url string, body io.Reader, result any) error {
+	mutating := IsMutating(method, url)
+	observe := c.observer != nil && mutating
+	var reqBody []byte
+	if body != nil && (observe || (c.dryRun != nil && mutating)) {
+		var err error
+		if reqBody, err = io.ReadAll(body); err != nil {
+			return err
+		}
+		body = bytes.NewReader(reqBody)
+	}
+
+	if c.dryRun != nil && mutating {
+		c.dryRun.record(method, url, reqBody)
+		return nil
+	}
+
+	if c.limiter != nil {
+		release, waited, err := c.limiter.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer release()
+		if c.debug && waited >= time.Millisecond {
+			_, _ = fmt.Fprintf(os.Stderr, "Debug: rate limiter held %s %s for %s (%s)\n", method, url, waited.Round(time.Millisecond), c.limiter)
+		}
+	}
+
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return err
@@ -162,13 +211,38 @@ func (c *Client) doRequest(ctx context.Context, method, url string, body io.Read
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		if observe {
+			c.observer(RequestEvent{Method: method, URL: url, RequestBody: reqBody, Err: err})
+		}
 		return err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if c.limiter != nil {
+		if pause, reason := c.limiter.Observe(resp.StatusCode, resp.Header); pause > 0 && c.debug {
+			_, _ = fmt.Fprintf(os.Stderr, "Debug: rate limiter pausing requests for %s (%s)\n", pause.Round(time.Millisecond), reason)
+		}
+	}
+
+	if observe {
+		respBody, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		c.observer(RequestEvent{Method: method, URL: url, RequestBody: reqBody, StatusCode: resp.StatusCode, ResponseBody: respBody, Err: err})
+		if err != nil {
+			return err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	}
+
 	if resp.StatusCode >= 400 {
 		respBody, _ := io.ReadAll(resp.Body)
 		apiErr := sanitizeErrorResponse(resp.StatusCode, respBody, c.debug)
+		if d := RetryAfter(resp.Header, time.Now()); d > 0 {
+			var e *APIError
+			if errors.As(apiErr, &e) {
+				e.RetryAfter = int(d.Round(time.Second) / time.Second)
+			}
+		}
 		if c.debug {
 			return fmt.Errorf("%s %s: %w", method, url, apiErr)
 		}
@@ -78,6 +78,8 @@ type UpdateDepartmentInput struct {
 	CompanyCode string `json:"strCompanyCode,omitempty"`
 	Active      *bool  `json:"blnActive,omitempty"`
 	SortOrder   int    `json:"intSortOrder,omitempty"`
+	// ParentId is a pointer so 0 can be sent to clear the parent.
+	ParentId *int `json:"intParentId,omitempty"`
 }
 
 func (s *DepartmentsService) Update(ctx context.Context, id int, input *UpdateDepartmentInput) (*Department, error) {
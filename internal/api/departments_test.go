@@ -384,6 +384,24 @@ func TestDepartmentsService_Update_ActiveStatus(t *testing.T) {
 	assert.False(t, department.Active)
 }
 
+func TestDepartmentsService_Update_ClearParent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"intParentId":0}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Department{Id: 10})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+
+	parent := 0
+	_, err := client.Departments().Update(context.Background(), 10, &UpdateDepartmentInput{ParentId: &parent})
+	require.NoError(t, err)
+}
+
 func TestDepartmentsService_Update_NotFound(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
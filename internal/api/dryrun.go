@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// PlannedRequest is a mutating request captured in dry-run mode instead of
+// being sent to Deputy.
+type PlannedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// DryRun records planned requests. It is safe for concurrent use so bulk
+// commands can share one recorder across workers.
+type DryRun struct {
+	mu       sync.Mutex
+	requests []PlannedRequest
+}
+
+// Requests returns the captured requests in the order they were made.
+func (d *DryRun) Requests() []PlannedRequest {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]PlannedRequest(nil), d.requests...)
+}
+
+func (d *DryRun) record(method, rawURL string, body []byte) {
+	req := PlannedRequest{Method: method, URL: rawURL}
+	if len(body) > 0 {
+		if json.Valid(body) {
+			req.Body = json.RawMessage(body)
+		} else {
+			quoted, _ := json.Marshal(string(body))
+			req.Body = quoted
+		}
+	}
+	d.mu.Lock()
+	d.requests = append(d.requests, req)
+	d.mu.Unlock()
+}
+
+// SetDryRun makes the client capture mutating requests into d instead of
+// sending them. Reads (GET and resource QUERY calls) still go to the API so
+// commands can look up what they need. Pass nil to send requests normally.
+func (c *Client) SetDryRun(d *DryRun) {
+	c.dryRun = d
+}
+
+// IsMutating reports whether a request changes data in Deputy. Resource
+// QUERY endpoints use POST but only read.
+func IsMutating(method, rawURL string) bool {
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return false
+	}
+	path := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	return !strings.HasSuffix(path, "/QUERY")
+}
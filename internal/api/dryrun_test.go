@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DryRunCapturesMutations(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Method+" "+r.URL.Path)
+		_ = json.NewEncoder(w).Encode([]Employee{{Id: 1}})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	plan := &DryRun{}
+	client.SetDryRun(plan)
+
+	_, err := client.Employees().List(context.Background(), nil)
+	require.NoError(t, err)
+	_, err = client.Employees().List(context.Background(), &ListOptions{Limit: 5})
+	require.NoError(t, err)
+	require.NoError(t, client.Employees().Terminate(context.Background(), 12, "2024-03-01"))
+
+	assert.Equal(t, []string{"GET /api/v1/supervise/employee", "POST /api/v1/resource/Employee/QUERY"}, seen)
+
+	requests := plan.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "POST", requests[0].Method)
+	assert.Contains(t, requests[0].URL, "/api/v1/supervise/employee/12/terminate")
+	assert.JSONEq(t, `{"strTerminationDate":"2024-03-01"}`, string(requests[0].Body))
+}
+
+func TestIsMutating(t *testing.T) {
+	assert.False(t, IsMutating("GET", "https://x/api/v1/resource/Employee"))
+	assert.False(t, IsMutating("POST", "https://x/api/v1/resource/Employee/QUERY"))
+	assert.False(t, IsMutating("POST", "https://x/api/v1/resource/Employee/QUERY?max=5"))
+	assert.True(t, IsMutating("POST", "https://x/api/v1/resource/Employee"))
+	assert.True(t, IsMutating("DELETE", "https://x/api/v1/resource/Employee/1"))
+}
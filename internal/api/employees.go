@@ -8,19 +8,27 @@ import (
 )
 
 type Employee struct {
-	Id              int    `json:"Id"`
-	FirstName       string `json:"FirstName"`
-	LastName        string `json:"LastName"`
-	DisplayName     string `json:"DisplayName"`
-	Email           string `json:"Email"`
-	Mobile          string `json:"Mobile"`
-	Active          bool   `json:"Active"`
-	Company         int    `json:"Company"`
-	Role            int    `json:"Role"`
-	MainAddress     int    `json:"MainAddress,omitempty"`
-	Photo           any    `json:"Photo,omitempty"`
-	StartDate       string `json:"StartDate,omitempty"`
-	TerminationDate string `json:"TerminationDate,omitempty"`
+	Id               int    `json:"Id"`
+	FirstName        string `json:"FirstName"`
+	LastName         string `json:"LastName"`
+	DisplayName      string `json:"DisplayName"`
+	Email            string `json:"Email"`
+	Mobile           string `json:"Mobile"`
+	Active           bool   `json:"Active"`
+	Company          int    `json:"Company"`
+	Role             int    `json:"Role"`
+	MainAddress      int    `json:"MainAddress,omitempty"`
+	Photo            any    `json:"Photo,omitempty"`
+	StartDate        string `json:"StartDate,omitempty"`
+	TerminationDate  string `json:"TerminationDate,omitempty"`
+	PayrollID        string `json:"PayrollId,omitempty"`
+	DateOfBirth      string `json:"DateOfBirth,omitempty"`
+	Position         string `json:"Position,omitempty"`
+	Contact          int    `json:"Contact,omitempty"`
+	PostalAddress    int    `json:"PostalAddress,omitempty"`
+	EmergencyAddress int    `json:"EmergencyAddress,omitempty"`
+	CustomFieldData  int    `json:"CustomFieldData,omitempty"`
+	UserID           int    `json:"UserId,omitempty"`
 }
 
 type EmployeesService struct {
@@ -68,6 +76,7 @@ type CreateEmployeeInput struct {
 	StartDate string `json:"strStartDate,omitempty"`
 	Company   int    `json:"intCompany"`
 	Role      int    `json:"intRoleId,omitempty"`
+	PayrollID string `json:"strPayrollId,omitempty"`
 }
 
 func (s *EmployeesService) Create(ctx context.Context, input *CreateEmployeeInput) (*Employee, error) {
@@ -86,6 +95,7 @@ type UpdateEmployeeInput struct {
 	LastName  string `json:"strLastName,omitempty"`
 	Email     string `json:"strEmail,omitempty"`
 	Mobile    string `json:"strMobile,omitempty"`
+	PayrollID string `json:"strPayrollId,omitempty"`
 	Active    *bool  `json:"blnActive,omitempty"`
 }
 
@@ -165,6 +175,12 @@ type Unavailability struct {
 	DateStart string `json:"DateStart"`
 	DateEnd   string `json:"DateEnd"`
 	Comment   string `json:"Comment,omitempty"`
+	// StartTime and EndTime bound the first (or only) period as Unix
+	// timestamps; records added by date only may leave them unset.
+	StartTime int64 `json:"StartTime,omitempty"`
+	EndTime   int64 `json:"EndTime,omitempty"`
+	// Schedule is set on recurring unavailability.
+	Schedule int `json:"Schedule,omitempty"`
 }
 
 type CreateUnavailabilityInput struct {
@@ -184,3 +200,114 @@ func (s *EmployeesService) AddUnavailability(ctx context.Context, input *CreateU
 	err = s.client.do(ctx, "POST", "/resource/EmployeeAvailability", bytes.NewReader(body), &unavail)
 	return &unavail, err
 }
+
+// UnavailabilityTime is a point in time as /supervise/unavail expects it.
+type UnavailabilityTime struct {
+	Timestamp int64 `json:"timestamp"`
+}
+
+// UnavailabilityRecurrence is an iCalendar-style repeat rule, such as
+// FREQ=WEEKLY, INTERVAL=1, BYDAY=TU.
+type UnavailabilityRecurrence struct {
+	Freq     string `json:"FREQ"`
+	Interval int    `json:"INTERVAL"`
+	ByDay    string `json:"BYDAY,omitempty"`
+}
+
+// CreateTimedUnavailabilityInput adds unavailability with start and end
+// times, optionally repeating.
+type CreateTimedUnavailabilityInput struct {
+	Employee   int                       `json:"employee"`
+	Start      UnavailabilityTime        `json:"start"`
+	End        UnavailabilityTime        `json:"end"`
+	Comment    string                    `json:"strComment,omitempty"`
+	Recurrence *UnavailabilityRecurrence `json:"recurrence,omitempty"`
+}
+
+// AddTimedUnavailability adds unavailability between two times. With a
+// Recurrence it repeats, for example every Tuesday after 3pm.
+func (s *EmployeesService) AddTimedUnavailability(ctx context.Context, input *CreateTimedUnavailabilityInput) (*Unavailability, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var unavail Unavailability
+	err = s.client.do(ctx, "POST", "/supervise/unavail", bytes.NewReader(body), &unavail)
+	return &unavail, err
+}
+
+// GetUnavailability returns one unavailability record.
+func (s *EmployeesService) GetUnavailability(ctx context.Context, id int) (*Unavailability, error) {
+	var unavail Unavailability
+	path := fmt.Sprintf("/resource/EmployeeAvailability/%d", id)
+	err := s.client.do(ctx, "GET", path, nil, &unavail)
+	return &unavail, err
+}
+
+type UpdateUnavailabilityInput struct {
+	DateStart string  `json:"strDateStart,omitempty"`
+	DateEnd   string  `json:"strDateEnd,omitempty"`
+	Comment   *string `json:"strComment,omitempty"`
+}
+
+// UpdateUnavailability changes the dates or comment of an unavailability
+// record.
+func (s *EmployeesService) UpdateUnavailability(ctx context.Context, id int, input *UpdateUnavailabilityInput) (*Unavailability, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var unavail Unavailability
+	path := fmt.Sprintf("/resource/EmployeeAvailability/%d", id)
+	err = s.client.do(ctx, "POST", path, bytes.NewReader(body), &unavail)
+	return &unavail, err
+}
+
+// ListUnavailability returns the unavailability records of an employee.
+func (s *EmployeesService) ListUnavailability(ctx context.Context, employeeID int) ([]Unavailability, error) {
+	input := &QueryInput{
+		Search: map[string]interface{}{
+			"s1": map[string]interface{}{"field": "Employee", "type": "eq", "data": employeeID},
+		},
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var unavail []Unavailability
+	err = s.client.do(ctx, "POST", "/resource/EmployeeAvailability/QUERY", bytes.NewReader(body), &unavail)
+	return unavail, err
+}
+
+// DeleteUnavailability removes an unavailability record.
+func (s *EmployeesService) DeleteUnavailability(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/resource/EmployeeAvailability/%d", id)
+	return s.client.do(ctx, "DELETE", path, nil, nil)
+}
+
+// EmployeeWorkplace links an employee to a location they may work at.
+type EmployeeWorkplace struct {
+	Id       int `json:"Id"`
+	Employee int `json:"Employee"`
+	Company  int `json:"Company"`
+}
+
+// ListLocations returns the locations an employee is assigned to.
+func (s *EmployeesService) ListLocations(ctx context.Context, employeeID int) ([]EmployeeWorkplace, error) {
+	input := &QueryInput{
+		Search: map[string]interface{}{
+			"s1": map[string]interface{}{"field": "Employee", "type": "eq", "data": employeeID},
+		},
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var workplaces []EmployeeWorkplace
+	err = s.client.do(ctx, "POST", "/resource/EmployeeWorkplace/QUERY", bytes.NewReader(body), &workplaces)
+	return workplaces, err
+}
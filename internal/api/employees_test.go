@@ -788,3 +788,103 @@ func TestEmployeesService_AddUnavailability_Error(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "API error 400")
 }
+
+func TestEmployeesService_ListUnavailability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/resource/EmployeeAvailability/QUERY", r.URL.Path)
+		var body QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"field": "Employee", "type": "eq", "data": 42.0}, body.Search["s1"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":11,"Employee":42,"DateStart":"2024-04-01","DateEnd":"2024-04-02"}]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	unavail, err := client.Employees().ListUnavailability(context.Background(), 42)
+	require.NoError(t, err)
+	require.Len(t, unavail, 1)
+	assert.Equal(t, 11, unavail[0].Id)
+	assert.Equal(t, "2024-04-01", unavail[0].DateStart)
+}
+
+func TestEmployeesService_DeleteUnavailability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/api/v1/resource/EmployeeAvailability/11", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	require.NoError(t, client.Employees().DeleteUnavailability(context.Background(), 11))
+}
+
+func TestEmployeesService_AddTimedUnavailability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/supervise/unavail", r.URL.Path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, 42.0, body["employee"])
+		assert.Equal(t, map[string]interface{}{"timestamp": 1719896400.0}, body["start"])
+		assert.Equal(t, map[string]interface{}{"FREQ": "WEEKLY", "INTERVAL": 1.0, "BYDAY": "TU"}, body["recurrence"])
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Id":12,"Employee":42,"StartTime":1719896400,"EndTime":1719928740,"Schedule":5}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	unavail, err := client.Employees().AddTimedUnavailability(context.Background(), &CreateTimedUnavailabilityInput{
+		Employee:   42,
+		Start:      UnavailabilityTime{Timestamp: 1719896400},
+		End:        UnavailabilityTime{Timestamp: 1719928740},
+		Recurrence: &UnavailabilityRecurrence{Freq: "WEEKLY", Interval: 1, ByDay: "TU"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 12, unavail.Id)
+	assert.Equal(t, 5, unavail.Schedule)
+}
+
+func TestEmployeesService_GetAndUpdateUnavailability(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/EmployeeAvailability/11", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "POST" {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]interface{}{"strDateEnd": "2024-04-03", "strComment": ""}, body)
+		}
+		_, _ = w.Write([]byte(`{"Id":11,"Employee":42,"DateStart":"2024-04-01","DateEnd":"2024-04-03"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	unavail, err := client.Employees().GetUnavailability(context.Background(), 11)
+	require.NoError(t, err)
+	assert.Equal(t, 42, unavail.Employee)
+
+	empty := ""
+	unavail, err = client.Employees().UpdateUnavailability(context.Background(), 11, &UpdateUnavailabilityInput{DateEnd: "2024-04-03", Comment: &empty})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-04-03", unavail.DateEnd)
+}
+
+func TestEmployeesService_ListLocations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/resource/EmployeeWorkplace/QUERY", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":3,"Employee":42,"Company":1},{"Id":4,"Employee":42,"Company":2}]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	workplaces, err := client.Employees().ListLocations(context.Background(), 42)
+	require.NoError(t, err)
+	require.Len(t, workplaces, 2)
+	assert.Equal(t, 2, workplaces[1].Company)
+}
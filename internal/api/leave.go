@@ -91,6 +91,13 @@ func (s *LeaveService) Decline(ctx context.Context, id int, comment string) erro
 	return err
 }
 
+// Cancel withdraws a leave request.
+func (s *LeaveService) Cancel(ctx context.Context, id int, comment string) error {
+	input := UpdateLeaveInput{Status: 3, Comment: comment}
+	_, err := s.Update(ctx, id, &input)
+	return err
+}
+
 type LeaveQueryInput struct {
 	Search map[string]interface{} `json:"search,omitempty"`
 	Join   []string               `json:"join,omitempty"`
@@ -108,3 +115,27 @@ func (s *LeaveService) Query(ctx context.Context, input *LeaveQueryInput) ([]Lea
 	err = s.client.do(ctx, "POST", "/resource/Leave/QUERY", bytes.NewReader(body), &leaves)
 	return leaves, err
 }
+
+// QueryAll is Query across every page of results. input.Start and input.Max
+// are managed here.
+func (s *LeaveService) QueryAll(ctx context.Context, input *LeaveQueryInput) ([]Leave, error) {
+	query := &QueryInput{}
+	if input != nil {
+		query.Search, query.Join = input.Search, input.Join
+	}
+	results, err := s.client.Resource("Leave").QueryAll(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var leaves []Leave
+	if err := json.Unmarshal(payload, &leaves); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
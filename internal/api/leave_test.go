@@ -486,6 +486,39 @@ func TestLeaveService_Query_WithPagination(t *testing.T) {
 	assert.Empty(t, leaves)
 }
 
+func TestLeaveService_QueryAll(t *testing.T) {
+	var starts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/Leave/QUERY", r.URL.Path)
+		var input QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, QueryPageSize, input.Max)
+		assert.Contains(t, input.Search, "s1")
+		starts = append(starts, input.Start)
+
+		n := QueryPageSize
+		if input.Start > 0 {
+			n = 1
+		}
+		page := make([]Leave, n)
+		for i := range page {
+			page[i] = Leave{Id: input.Start + i + 1}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	leaves, err := client.Leave().QueryAll(context.Background(), &LeaveQueryInput{
+		Search: map[string]interface{}{"s1": map[string]interface{}{"field": "Status", "type": "eq", "data": 1}},
+		Max:    10,
+	})
+	require.NoError(t, err)
+	assert.Len(t, leaves, QueryPageSize+1)
+	assert.Equal(t, []int{0, QueryPageSize}, starts)
+}
+
 func TestLeaveService_Query_Empty(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -628,3 +661,22 @@ func TestLeaveService_Query_Forbidden(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "API error 403")
 }
+
+func TestLeaveService_Cancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/Leave/42", r.URL.Path)
+
+		var input UpdateLeaveInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, 3, input.Status)
+		assert.Equal(t, "Employee offboarded", input.Comment)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Leave{Id: 42, Status: 3})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	require.NoError(t, client.Leave().Cancel(context.Background(), 42, "Employee offboarded"))
+}
@@ -0,0 +1,22 @@
+package api
+
+// RequestEvent describes a mutating request that was sent to Deputy.
+// StatusCode is zero when the request never got a response.
+type RequestEvent struct {
+	Method       string
+	URL          string
+	RequestBody  []byte
+	StatusCode   int
+	ResponseBody []byte
+	Err          error
+}
+
+// RequestObserver is called after each mutating request completes. It may be
+// called from several goroutines at once when commands fan requests out.
+type RequestObserver func(RequestEvent)
+
+// SetRequestObserver registers fn to see every mutating request the client
+// sends. Reads and dry-run captures are not reported. Pass nil to stop.
+func (c *Client) SetRequestObserver(fn RequestObserver) {
+	c.observer = fn
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RequestObserverSeesMutations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode([]Employee{{Id: 1}})
+		default:
+			_ = json.NewEncoder(w).Encode(Employee{Id: 42, FirstName: "Jane"})
+		}
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	var events []RequestEvent
+	client.SetRequestObserver(func(e RequestEvent) { events = append(events, e) })
+
+	_, err := client.Employees().List(context.Background(), nil)
+	require.NoError(t, err)
+	emp, err := client.Employees().Create(context.Background(), &CreateEmployeeInput{FirstName: "Jane", LastName: "Doe", Company: 1})
+	require.NoError(t, err)
+
+	// The response is still decoded for the caller.
+	assert.Equal(t, 42, emp.Id)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, http.MethodPost, events[0].Method)
+	assert.Equal(t, http.StatusOK, events[0].StatusCode)
+	assert.Contains(t, string(events[0].RequestBody), `"Jane"`)
+	assert.Contains(t, string(events[0].ResponseBody), `"Id":42`)
+}
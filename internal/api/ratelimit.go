@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRateLimitPause caps how long a single rate-limit response can stall
+// requests, so a bogus reset header cannot hang the CLI.
+const maxRateLimitPause = time.Minute
+
+// RateLimiter paces the requests of one or more Clients: a token bucket
+// limits requests per second, a semaphore limits requests in flight, and
+// rate-limit response headers pause everyone until Deputy allows more.
+// The zero rate and zero max in flight mean unlimited.
+type RateLimiter struct {
+	rate        float64
+	burst       float64
+	maxInFlight int
+	slots       chan struct{}
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+
+	now func() time.Time
+}
+
+// NewRateLimiter returns a limiter allowing rate requests per second (with a
+// burst of one second's worth) and at most maxInFlight concurrent requests.
+func NewRateLimiter(rate float64, maxInFlight int) *RateLimiter {
+	l := &RateLimiter{rate: rate, maxInFlight: maxInFlight, now: time.Now}
+	if rate > 0 {
+		l.burst = math.Max(1, math.Ceil(rate))
+		l.tokens = l.burst
+	}
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	l.last = l.now()
+	return l
+}
+
+// String describes the configuration for --debug output.
+func (l *RateLimiter) String() string {
+	rate := "unlimited"
+	if l.rate > 0 {
+		rate = strconv.FormatFloat(l.rate, 'f', -1, 64) + "/s"
+	}
+	inFlight := "unlimited"
+	if l.maxInFlight > 0 {
+		inFlight = strconv.Itoa(l.maxInFlight)
+	}
+	return fmt.Sprintf("rate %s, max in flight %s", rate, inFlight)
+}
+
+// Acquire blocks until a request may be sent. The returned release must be
+// called once the response has been read. waited is how long Acquire blocked.
+func (l *RateLimiter) Acquire(ctx context.Context) (release func(), waited time.Duration, err error) {
+	start := l.now()
+	release = func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			release = func() { <-l.slots }
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		}
+	}
+	for {
+		d := l.reserve()
+		if d <= 0 {
+			break
+		}
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, 0, ctx.Err()
+		}
+	}
+	return release, l.now().Sub(start), nil
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before
+// trying again.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Observe adapts to a response. A 429 or 503 with Retry-After, or a
+// X-RateLimit-Remaining (or RateLimit-Remaining) of 0 with a reset header,
+// pauses all requests until then; a bare 429 pauses for a second. It returns
+// the pause applied, if any, and why.
+func (l *RateLimiter) Observe(status int, h http.Header) (time.Duration, string) {
+	now := l.now()
+	var pause time.Duration
+	var reason string
+
+	if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		if d := RetryAfter(h, now); d > 0 {
+			pause, reason = d, "Retry-After"
+		} else if status == http.StatusTooManyRequests {
+			pause, reason = time.Second, "429 without Retry-After"
+		}
+	}
+	if remaining, ok := headerInt(h, "X-RateLimit-Remaining", "RateLimit-Remaining"); ok && remaining <= 0 {
+		if reset, ok := headerInt(h, "X-RateLimit-Reset", "RateLimit-Reset"); ok {
+			// Reset is either seconds from now or a Unix timestamp.
+			d := time.Duration(reset) * time.Second
+			if reset > 1_000_000_000 {
+				d = time.Unix(int64(reset), 0).Sub(now)
+			}
+			if d > pause {
+				pause, reason = d, "rate limit exhausted"
+			}
+		}
+	}
+	if pause <= 0 {
+		return 0, ""
+	}
+	pause = min(pause, maxRateLimitPause)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := now.Add(pause); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	return pause, reason
+}
+
+// RetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func RetryAfter(h http.Header, now time.Time) time.Duration {
+	v := strings.TrimSpace(h.Get("Retry-After"))
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+func headerInt(h http.Header, names ...string) (int, bool) {
+	for _, name := range names {
+		if v := strings.TrimSpace(h.Get(name)); v != "" {
+			n, err := strconv.Atoi(v)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
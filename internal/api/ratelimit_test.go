@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	l := NewRateLimiter(20, 0)
+	ctx := context.Background()
+
+	// A second's worth of requests goes out at once...
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		release, _, err := l.Acquire(ctx)
+		require.NoError(t, err)
+		release()
+	}
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+
+	// ...then requests are spaced 1/rate apart.
+	release, waited, err := l.Acquire(ctx)
+	require.NoError(t, err)
+	release()
+	assert.GreaterOrEqual(t, waited, 30*time.Millisecond)
+}
+
+func TestRateLimiter_MaxInFlight(t *testing.T) {
+	l := NewRateLimiter(0, 1)
+
+	release, _, err := l.Acquire(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err = l.Acquire(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	release()
+	release, _, err = l.Acquire(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestRateLimiter_Observe(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		want    time.Duration
+	}{
+		{"ok without headers", 200, nil, 0},
+		{"retry-after seconds", 429, map[string]string{"Retry-After": "2"}, 2 * time.Second},
+		{"retry-after date", 503, map[string]string{"Retry-After": now.Add(3 * time.Second).Format(http.TimeFormat)}, 3 * time.Second},
+		{"bare 429", 429, nil, time.Second},
+		{"remaining zero, reset delta", 200, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "5"}, 5 * time.Second},
+		{"remaining zero, reset epoch", 200, map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "1709283607"}, 7 * time.Second},
+		{"remaining left", 200, map[string]string{"X-RateLimit-Remaining": "3", "X-RateLimit-Reset": "5"}, 0},
+		{"capped", 429, map[string]string{"Retry-After": "3600"}, maxRateLimitPause},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(0, 0)
+			l.now = func() time.Time { return now }
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			pause, _ := l.Observe(tt.status, h)
+			assert.Equal(t, tt.want, pause)
+			assert.Equal(t, tt.want, l.reserve())
+		})
+	}
+}
+
+func TestClient_RateLimiterAdaptsToRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	limiter := NewRateLimiter(0, 2)
+	client.SetRateLimiter(limiter)
+	assert.Same(t, limiter, client.RateLimiter())
+
+	_, err := client.Resource("Employee").Get(context.Background(), 1)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, 1, apiErr.RetryAfter)
+
+	// The next request must wait out the pause; a short deadline fails it
+	// without reaching the server.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.Resource("Employee").Get(ctx, 1)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -95,6 +95,34 @@ func (s *ResourceService) Query(ctx context.Context, input *QueryInput) ([]map[s
 	return results, err
 }
 
+// QueryPageSize is the most records Deputy returns from one QUERY call.
+const QueryPageSize = 500
+
+// QueryAll runs a query page by page until Deputy returns a short page, so the
+// result holds every matching record. input.Start and input.Max are managed
+// here; the rest of input is sent unchanged on every page.
+func (s *ResourceService) QueryAll(ctx context.Context, input *QueryInput) ([]map[string]interface{}, error) {
+	page := QueryInput{}
+	if input != nil {
+		page = *input
+	}
+	page.Max = QueryPageSize
+	page.Start = 0
+
+	var all []map[string]interface{}
+	for {
+		results, err := s.Query(ctx, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, results...)
+		if len(results) < QueryPageSize {
+			return all, nil
+		}
+		page.Start += len(results)
+	}
+}
+
 func (s *ResourceService) Get(ctx context.Context, id int) (map[string]interface{}, error) {
 	var result map[string]interface{}
 	path := fmt.Sprintf("/resource/%s/%d", s.resourceName, id)
@@ -109,6 +137,38 @@ func (s *ResourceService) List(ctx context.Context) ([]map[string]interface{}, e
 	return results, err
 }
 
+// Create adds a record. Fields are sent as given, keyed by the names
+// reported by Info.
+func (s *ResourceService) Create(ctx context.Context, data map[string]interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	path := fmt.Sprintf("/resource/%s", s.resourceName)
+	err = s.client.do(ctx, "POST", path, bytes.NewReader(body), &result)
+	return result, err
+}
+
+// Update changes the given fields of a record and leaves the rest as they are.
+func (s *ResourceService) Update(ctx context.Context, id int, data map[string]interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[string]interface{}
+	path := fmt.Sprintf("/resource/%s/%d", s.resourceName, id)
+	err = s.client.do(ctx, "POST", path, bytes.NewReader(body), &result)
+	return result, err
+}
+
+func (s *ResourceService) Delete(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/resource/%s/%d", s.resourceName, id)
+	return s.client.do(ctx, "DELETE", path, nil, nil)
+}
+
 // KnownResources returns a list of common Deputy resource names
 func KnownResources() []string {
 	return []string{
@@ -200,6 +200,38 @@ func TestResourceService_Query_Empty(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+func TestResourceService_QueryAll_Paginates(t *testing.T) {
+	var starts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/Employee/QUERY", r.URL.Path)
+		var input QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, QueryPageSize, input.Max)
+		assert.Equal(t, map[string]string{"Id": "asc"}, input.Sort)
+		starts = append(starts, input.Start)
+
+		n := QueryPageSize
+		if input.Start > 0 {
+			n = 3
+		}
+		page := make([]map[string]interface{}, n)
+		for i := range page {
+			page[i] = map[string]interface{}{"Id": input.Start + i + 1}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	results, err := client.Resource("Employee").QueryAll(context.Background(), &QueryInput{
+		Sort: map[string]string{"Id": "asc"},
+		Max:  10,
+	})
+	require.NoError(t, err)
+	assert.Len(t, results, QueryPageSize+3)
+	assert.Equal(t, []int{0, QueryPageSize}, starts)
+}
+
 func TestResourceService_Get(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodGet, r.Method)
@@ -331,6 +363,53 @@ func TestResourceService_DifferentResources(t *testing.T) {
 	}
 }
 
+func TestResourceService_Create(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/Task", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"Question":"Check fridge","Employee":5}`, string(body))
+		_, _ = w.Write([]byte(`{"Id": 77, "Question": "Check fridge"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	result, err := client.Resource("Task").Create(context.Background(), map[string]interface{}{
+		"Question": "Check fridge",
+		"Employee": 5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, float64(77), result["Id"])
+}
+
+func TestResourceService_Update(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/Memo/12", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"Content":"Updated"}`, string(body))
+		_, _ = w.Write([]byte(`{"Id": 12, "Content": "Updated"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	result, err := client.Resource("Memo").Update(context.Background(), 12, map[string]interface{}{"Content": "Updated"})
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", result["Content"])
+}
+
+func TestResourceService_Delete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/resource/Contact/3", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	require.NoError(t, client.Resource("Contact").Delete(context.Background(), 3))
+}
+
 func TestKnownResources(t *testing.T) {
 	resources := KnownResources()
 
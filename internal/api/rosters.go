@@ -8,16 +8,17 @@ import (
 )
 
 type Roster struct {
-	Id              int    `json:"Id"`
-	Date            string `json:"Date"`
-	StartTime       int64  `json:"StartTime"`
-	EndTime         int64  `json:"EndTime"`
-	Mealbreak       string `json:"Mealbreak"`
-	Employee        int    `json:"Employee"`
-	OperationalUnit int    `json:"OperationalUnit"`
-	Open            bool   `json:"Open"`
-	Published       bool   `json:"Published"`
-	Comment         string `json:"Comment,omitempty"`
+	Id              int     `json:"Id"`
+	Date            string  `json:"Date"`
+	StartTime       int64   `json:"StartTime"`
+	EndTime         int64   `json:"EndTime"`
+	Mealbreak       string  `json:"Mealbreak"`
+	Employee        int     `json:"Employee"`
+	OperationalUnit int     `json:"OperationalUnit"`
+	Open            bool    `json:"Open"`
+	Published       bool    `json:"Published"`
+	Comment         string  `json:"Comment,omitempty"`
+	Cost            float64 `json:"Cost"`
 }
 
 type RostersService struct {
@@ -34,6 +35,40 @@ func (s *RostersService) List(ctx context.Context, opts *ListOptions) ([]Roster,
 	return rosters, err
 }
 
+// Query searches rosters through the resource API, which supports date ranges
+// and filters that /supervise/roster does not.
+func (s *RostersService) Query(ctx context.Context, input *QueryInput) ([]Roster, error) {
+	results, err := s.client.Resource("Roster").Query(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRosters(results)
+}
+
+// QueryAll is Query across every page of results, for callers that need the
+// whole date range rather than the first QueryPageSize shifts.
+func (s *RostersService) QueryAll(ctx context.Context, input *QueryInput) ([]Roster, error) {
+	results, err := s.client.Resource("Roster").QueryAll(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRosters(results)
+}
+
+func decodeRosters(results []map[string]interface{}) ([]Roster, error) {
+	payload, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var rosters []Roster
+	if err := json.Unmarshal(payload, &rosters); err != nil {
+		return nil, err
+	}
+
+	return rosters, nil
+}
+
 func (s *RostersService) Get(ctx context.Context, id int) (*Roster, error) {
 	var roster Roster
 	path := fmt.Sprintf("/resource/Roster/%d", id)
@@ -41,6 +76,25 @@ func (s *RostersService) Get(ctx context.Context, id int) (*Roster, error) {
 	return &roster, err
 }
 
+// Delete removes a shift.
+func (s *RostersService) Delete(ctx context.Context, id int) error {
+	path := fmt.Sprintf("/resource/Roster/%d", id)
+	return s.client.do(ctx, "DELETE", path, nil, nil)
+}
+
+// Reopen unassigns a shift and makes it an open shift anyone can claim.
+func (s *RostersService) Reopen(ctx context.Context, id int) (*Roster, error) {
+	body, err := json.Marshal(map[string]interface{}{"Employee": 0, "Open": true})
+	if err != nil {
+		return nil, err
+	}
+
+	var roster Roster
+	path := fmt.Sprintf("/resource/Roster/%d", id)
+	err = s.client.do(ctx, "POST", path, bytes.NewReader(body), &roster)
+	return &roster, err
+}
+
 type CreateRosterInput struct {
 	Employee        int    `json:"intEmployeeId"`
 	OperationalUnit int    `json:"intOpunitId"`
@@ -117,3 +171,92 @@ func (s *RostersService) GetSwappable(ctx context.Context, rosterID int) ([]Swap
 	err := s.client.do(ctx, "GET", path, nil, &rosters)
 	return rosters, err
 }
+
+// Roster swap statuses as stored on the RosterSwap resource.
+const (
+	RosterSwapPending  = 0
+	RosterSwapApproved = 1
+	RosterSwapRejected = 2
+)
+
+// RosterSwap is a request to exchange two rostered shifts between employees.
+type RosterSwap struct {
+	Id           int    `json:"Id"`
+	Roster       int    `json:"Roster"`
+	SwapRoster   int    `json:"SwapRoster"`
+	Employee     int    `json:"Employee,omitempty"`
+	SwapEmployee int    `json:"SwapEmployee,omitempty"`
+	Status       int    `json:"Status"` // 0=pending, 1=approved, 2=rejected
+	Comment      string `json:"Comment,omitempty"`
+	Created      string `json:"Created,omitempty"`
+}
+
+type CreateRosterSwapInput struct {
+	Roster     int    `json:"Roster"`
+	SwapRoster int    `json:"SwapRoster"`
+	Comment    string `json:"Comment,omitempty"`
+}
+
+// RequestSwap creates a pending swap between two rosters.
+func (s *RostersService) RequestSwap(ctx context.Context, input *CreateRosterSwapInput) (*RosterSwap, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var swap RosterSwap
+	err = s.client.do(ctx, "POST", "/resource/RosterSwap", bytes.NewReader(body), &swap)
+	return &swap, err
+}
+
+type UpdateRosterSwapInput struct {
+	Status  int    `json:"Status"`
+	Comment string `json:"Comment,omitempty"`
+}
+
+// UpdateSwap sets the status of a swap request.
+func (s *RostersService) UpdateSwap(ctx context.Context, id int, input *UpdateRosterSwapInput) (*RosterSwap, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var swap RosterSwap
+	path := fmt.Sprintf("/resource/RosterSwap/%d", id)
+	err = s.client.do(ctx, "POST", path, bytes.NewReader(body), &swap)
+	return &swap, err
+}
+
+// ApproveSwap approves a pending swap request.
+func (s *RostersService) ApproveSwap(ctx context.Context, id int) (*RosterSwap, error) {
+	return s.UpdateSwap(ctx, id, &UpdateRosterSwapInput{Status: RosterSwapApproved})
+}
+
+// RejectSwap rejects a pending swap request with an optional reason.
+func (s *RostersService) RejectSwap(ctx context.Context, id int, comment string) (*RosterSwap, error) {
+	return s.UpdateSwap(ctx, id, &UpdateRosterSwapInput{Status: RosterSwapRejected, Comment: comment})
+}
+
+// PendingSwaps lists swap requests awaiting a manager decision, fetching
+// every page.
+func (s *RostersService) PendingSwaps(ctx context.Context) ([]RosterSwap, error) {
+	input := &QueryInput{
+		Search: map[string]interface{}{
+			"s1": map[string]interface{}{"field": "Status", "type": "eq", "data": RosterSwapPending},
+		},
+	}
+	results, err := s.client.Resource("RosterSwap").QueryAll(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var swaps []RosterSwap
+	if err := json.Unmarshal(payload, &swaps); err != nil {
+		return nil, err
+	}
+	return swaps, nil
+}
@@ -584,3 +584,191 @@ func TestRostersService_Discard_NoContent(t *testing.T) {
 	err := client.Rosters().Discard(context.Background(), input)
 	require.NoError(t, err)
 }
+
+func TestRostersService_Query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/Roster/QUERY", r.URL.Path)
+
+		var input QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Contains(t, input.Search, "f1")
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":7,"Date":"2024-01-15","StartTime":1705312800,"EndTime":1705341600,"Employee":100,"OperationalUnit":10}]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+
+	rosters, err := client.Rosters().Query(context.Background(), &QueryInput{
+		Search: map[string]interface{}{
+			"f1": map[string]interface{}{"field": "Date", "type": "ge", "data": "2024-01-15"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, rosters, 1)
+	assert.Equal(t, 7, rosters[0].Id)
+	assert.Equal(t, 100, rosters[0].Employee)
+	assert.Equal(t, 10, rosters[0].OperationalUnit)
+}
+
+func TestRostersService_QueryAll(t *testing.T) {
+	var starts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/Roster/QUERY", r.URL.Path)
+		var input QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, QueryPageSize, input.Max)
+		starts = append(starts, input.Start)
+
+		n := QueryPageSize
+		if input.Start > 0 {
+			n = 2
+		}
+		page := make([]Roster, n)
+		for i := range page {
+			page[i] = Roster{Id: input.Start + i + 1}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	rosters, err := client.Rosters().QueryAll(context.Background(), &QueryInput{})
+	require.NoError(t, err)
+	assert.Len(t, rosters, QueryPageSize+2)
+	assert.Equal(t, QueryPageSize+2, rosters[len(rosters)-1].Id)
+	assert.Equal(t, []int{0, QueryPageSize}, starts)
+}
+
+func TestRostersService_Query_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+
+	_, err := client.Rosters().Query(context.Background(), &QueryInput{})
+	require.Error(t, err)
+	assert.True(t, IsForbidden(err))
+}
+
+func TestRostersService_RequestSwap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/RosterSwap", r.URL.Path)
+
+		var input CreateRosterSwapInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, 10, input.Roster)
+		assert.Equal(t, 11, input.SwapRoster)
+		assert.Equal(t, "Family event", input.Comment)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RosterSwap{Id: 5, Roster: 10, SwapRoster: 11, Status: RosterSwapPending})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+
+	swap, err := client.Rosters().RequestSwap(context.Background(), &CreateRosterSwapInput{Roster: 10, SwapRoster: 11, Comment: "Family event"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, swap.Id)
+	assert.Equal(t, RosterSwapPending, swap.Status)
+}
+
+func TestRostersService_ApproveRejectSwap(t *testing.T) {
+	var got []UpdateRosterSwapInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/RosterSwap/5", r.URL.Path)
+
+		var input UpdateRosterSwapInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		got = append(got, input)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(RosterSwap{Id: 5, Status: input.Status, Comment: input.Comment})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+
+	swap, err := client.Rosters().ApproveSwap(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Equal(t, RosterSwapApproved, swap.Status)
+
+	swap, err = client.Rosters().RejectSwap(context.Background(), 5, "Understaffed")
+	require.NoError(t, err)
+	assert.Equal(t, RosterSwapRejected, swap.Status)
+
+	require.Len(t, got, 2)
+	assert.Equal(t, UpdateRosterSwapInput{Status: RosterSwapApproved}, got[0])
+	assert.Equal(t, UpdateRosterSwapInput{Status: RosterSwapRejected, Comment: "Understaffed"}, got[1])
+}
+
+func TestRostersService_PendingSwaps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/RosterSwap/QUERY", r.URL.Path)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"field":"Status"`)
+		var input QueryInput
+		require.NoError(t, json.Unmarshal(body, &input))
+
+		n := QueryPageSize
+		if input.Start > 0 {
+			n = 2
+		}
+		page := make([]RosterSwap, n)
+		for i := range page {
+			page[i] = RosterSwap{Id: input.Start + i + 1}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+
+	swaps, err := client.Rosters().PendingSwaps(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, swaps, QueryPageSize+2)
+}
+
+func TestRostersService_Delete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/resource/Roster/900", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	require.NoError(t, client.Rosters().Delete(context.Background(), 900))
+}
+
+func TestRostersService_Reopen(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/resource/Roster/900", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"Employee": 0.0, "Open": true}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Id":900,"Employee":0,"Open":true}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	roster, err := client.Rosters().Reopen(context.Background(), 900)
+	require.NoError(t, err)
+	assert.True(t, roster.Open)
+}
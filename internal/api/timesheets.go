@@ -42,7 +42,20 @@ func (s *TimesheetsService) Query(ctx context.Context, input *QueryInput) ([]Tim
 	if err != nil {
 		return nil, err
 	}
+	return decodeTimesheets(results)
+}
 
+// QueryAll is Query across every page of results, for callers that need the
+// whole date range rather than the first QueryPageSize timesheets.
+func (s *TimesheetsService) QueryAll(ctx context.Context, input *QueryInput) ([]Timesheet, error) {
+	results, err := s.client.Resource("Timesheet").QueryAll(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTimesheets(results)
+}
+
+func decodeTimesheets(results []map[string]interface{}) ([]Timesheet, error) {
 	payload, err := json.Marshal(results)
 	if err != nil {
 		return nil, err
@@ -131,6 +144,31 @@ func (s *TimesheetsService) Update(ctx context.Context, id int, input *UpdateTim
 	return &timesheet, err
 }
 
+// SaveTimesheetInput creates a timesheet, or edits the one given by
+// Timesheet, through /supervise/timesheet/update.
+type SaveTimesheetInput struct {
+	Timesheet        int     `json:"intTimesheetId,omitempty"`
+	Employee         int     `json:"intEmployeeId"`
+	StartTime        int64   `json:"intStartTimestamp"`
+	EndTime          int64   `json:"intEndTimestamp,omitempty"`
+	MealbreakMinutes *int    `json:"intMealbreakMinute,omitempty"`
+	OperationalUnit  int     `json:"intOpunitId"`
+	Comment          *string `json:"strComment,omitempty"`
+}
+
+// Save writes a timesheet's times, meal break, area and comment. Without a
+// Timesheet ID a new timesheet is created, as for a missed punch.
+func (s *TimesheetsService) Save(ctx context.Context, input *SaveTimesheetInput) (*Timesheet, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var timesheet Timesheet
+	err = s.client.do(ctx, "POST", "/supervise/timesheet/update", bytes.NewReader(body), &timesheet)
+	return &timesheet, err
+}
+
 // PayRule represents a pay rule in Deputy
 type PayRule struct {
 	Id         int     `json:"Id"`
@@ -148,7 +186,8 @@ type TimesheetPayReturn struct {
 	Overridden bool    `json:"Overridden"`
 }
 
-// ListPayRules returns all pay rules, optionally filtered by hourly rate
+// ListPayRules returns all pay rules, optionally filtered by hourly rate,
+// fetching every page
 func (s *TimesheetsService) ListPayRules(ctx context.Context, hourlyRate *float64) ([]PayRule, error) {
 	input := &QueryInput{}
 	if hourlyRate != nil {
@@ -157,14 +196,20 @@ func (s *TimesheetsService) ListPayRules(ctx context.Context, hourlyRate *float6
 		}
 	}
 
-	body, err := json.Marshal(input)
+	results, err := s.client.Resource("PayRules").QueryAll(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(results)
 	if err != nil {
 		return nil, err
 	}
 
 	var rules []PayRule
-	err = s.client.do(ctx, "POST", "/resource/PayRules/QUERY", bytes.NewReader(body), &rules)
-	return rules, err
+	if err := json.Unmarshal(payload, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
 }
 
 // GetPayReturn gets the pay return record for a timesheet
@@ -198,8 +243,26 @@ type SetPayRuleInput struct {
 	Overridden bool    `json:"Overridden"`
 }
 
+// PayRuleChange is the outcome of selecting a pay rule, together with the
+// pay return and timesheet cost it replaced.
+type PayRuleChange struct {
+	Result       TimesheetPayReturn `json:"result"`
+	Previous     TimesheetPayReturn `json:"previous"`
+	PreviousCost float64            `json:"previousCost"`
+}
+
 // SetPayRule sets the pay rule for a timesheet
 func (s *TimesheetsService) SetPayRule(ctx context.Context, timesheetID int, payRuleID int) (*TimesheetPayReturn, error) {
+	change, err := s.ChangePayRule(ctx, timesheetID, payRuleID)
+	if err != nil {
+		return nil, err
+	}
+	return &change.Result, nil
+}
+
+// ChangePayRule sets the pay rule for a timesheet and reports what it
+// replaced, so callers can show or restore the previous selection.
+func (s *TimesheetsService) ChangePayRule(ctx context.Context, timesheetID int, payRuleID int) (*PayRuleChange, error) {
 	// First get the existing pay return to get its ID
 	existing, err := s.GetPayReturn(ctx, timesheetID)
 	if err != nil {
@@ -239,24 +302,34 @@ func (s *TimesheetsService) SetPayRule(ctx context.Context, timesheetID int, pay
 	cost := rules[0].HourlyRate * timesheet.TotalTime
 
 	// Update the pay return (this sets the pay rule selection)
-	input := &SetPayRuleInput{
+	result, err := s.UpdatePayReturn(ctx, existing.Id, timesheetID, &SetPayRuleInput{
 		PayRule:    payRuleID,
 		Cost:       cost,
 		Overridden: true,
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	return &PayRuleChange{Result: *result, Previous: *existing, PreviousCost: timesheet.Cost}, nil
+}
+
+// UpdatePayReturn writes a pay rule selection to a pay return and copies its
+// cost onto the timesheet, which Deputy requires for the cost to sync.
+func (s *TimesheetsService) UpdatePayReturn(ctx context.Context, payReturnID, timesheetID int, input *SetPayRuleInput) (*TimesheetPayReturn, error) {
 	body, err := json.Marshal(input)
 	if err != nil {
 		return nil, err
 	}
 
 	var result TimesheetPayReturn
-	path := fmt.Sprintf("/resource/TimesheetPayReturn/%d", existing.Id)
+	path := fmt.Sprintf("/resource/TimesheetPayReturn/%d", payReturnID)
 	if err := s.client.do(ctx, "POST", path, bytes.NewReader(body), &result); err != nil {
 		return nil, err
 	}
 
 	// Also update the timesheet's cost directly (required for cost to sync)
+	cost := input.Cost
 	tsInput := &UpdateTimesheetInput{Cost: &cost}
 	tsBody, err := json.Marshal(tsInput)
 	if err != nil {
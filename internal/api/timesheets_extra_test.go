@@ -45,6 +45,64 @@ func TestTimesheetsService_Query(t *testing.T) {
 	assert.Equal(t, "2024-01-01", results[0].Date)
 }
 
+func TestTimesheetsService_QueryAll(t *testing.T) {
+	var starts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/Timesheet/QUERY", r.URL.Path)
+		var input QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, QueryPageSize, input.Max)
+		starts = append(starts, input.Start)
+
+		n := QueryPageSize
+		if input.Start > 0 {
+			n = 4
+		}
+		page := make([]Timesheet, n)
+		for i := range page {
+			page[i] = Timesheet{Id: input.Start + i + 1}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	timesheets, err := client.Timesheets().QueryAll(context.Background(), &QueryInput{})
+	require.NoError(t, err)
+	assert.Len(t, timesheets, QueryPageSize+4)
+	assert.Equal(t, []int{0, QueryPageSize}, starts)
+}
+
+func TestTimesheetsService_ListPayRules_Pages(t *testing.T) {
+	var starts []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/PayRules/QUERY", r.URL.Path)
+		var input QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, QueryPageSize, input.Max)
+		starts = append(starts, input.Start)
+
+		n := QueryPageSize
+		if input.Start > 0 {
+			n = 2
+		}
+		page := make([]PayRule, n)
+		for i := range page {
+			page[i] = PayRule{Id: input.Start + i + 1}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	rules, err := client.Timesheets().ListPayRules(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, rules, QueryPageSize+2)
+	assert.Equal(t, []int{0, QueryPageSize}, starts)
+}
+
 func TestTimesheetsService_Query_InvalidTypes(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -97,6 +155,39 @@ func TestTimesheetsService_Update(t *testing.T) {
 	assert.Equal(t, 123.45, updated.Cost)
 }
 
+func TestTimesheetsService_Save(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/supervise/timesheet/update", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"intEmployeeId":      42.0,
+			"intStartTimestamp":  1719788400.0,
+			"intEndTimestamp":    1719817200.0,
+			"intMealbreakMinute": 0.0,
+			"intOpunitId":        7.0,
+		}, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Id":600,"Employee":42,"StartTime":1719788400,"EndTime":1719817200}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	noBreak := 0
+	timesheet, err := client.Timesheets().Save(context.Background(), &SaveTimesheetInput{
+		Employee:         42,
+		StartTime:        1719788400,
+		EndTime:          1719817200,
+		MealbreakMinutes: &noBreak,
+		OperationalUnit:  7,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 600, timesheet.Id)
+}
+
 func TestTimesheetsService_ListPayRules(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method)
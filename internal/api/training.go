@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+type TrainingModule struct {
+	Id     int    `json:"Id"`
+	Title  string `json:"Title"`
+	Type   int    `json:"Type,omitempty"`
+	Active bool   `json:"Active"`
+}
+
+type TrainingRecord struct {
+	Id           int    `json:"Id"`
+	Employee     int    `json:"Employee"`
+	Module       int    `json:"Module"`
+	TrainingDate string `json:"TrainingDate"`
+	ExpiryDate   string `json:"ExpiryDate,omitempty"`
+	Active       bool   `json:"Active"`
+	Comment      string `json:"Comment,omitempty"`
+}
+
+type TrainingService struct {
+	client *Client
+}
+
+func (c *Client) Training() *TrainingService {
+	return &TrainingService{client: c}
+}
+
+func (s *TrainingService) ListModules(ctx context.Context) ([]TrainingModule, error) {
+	var modules []TrainingModule
+	err := s.client.do(ctx, "GET", "/resource/TrainingModule", nil, &modules)
+	return modules, err
+}
+
+// QueryRecords returns every training record matching input, reading all
+// pages.
+func (s *TrainingService) QueryRecords(ctx context.Context, input *QueryInput) ([]TrainingRecord, error) {
+	results, err := s.client.Resource("TrainingRecord").QueryAll(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []TrainingRecord
+	if err := json.Unmarshal(payload, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+type CreateTrainingRecordInput struct {
+	Employee     int    `json:"Employee"`
+	Module       int    `json:"Module"`
+	TrainingDate string `json:"TrainingDate"`
+	ExpiryDate   string `json:"ExpiryDate,omitempty"`
+	Comment      string `json:"Comment,omitempty"`
+	Active       bool   `json:"Active"`
+}
+
+// AddRecord records that an employee completed a training module.
+func (s *TrainingService) AddRecord(ctx context.Context, input *CreateTrainingRecordInput) (*TrainingRecord, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var record TrainingRecord
+	err = s.client.do(ctx, "POST", "/resource/TrainingRecord", bytes.NewReader(body), &record)
+	return &record, err
+}
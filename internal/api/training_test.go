@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrainingService_ListModules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/api/v1/resource/TrainingModule", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":1,"Title":"First Aid","Active":true}]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	modules, err := client.Training().ListModules(context.Background())
+	require.NoError(t, err)
+	require.Len(t, modules, 1)
+	assert.Equal(t, "First Aid", modules[0].Title)
+}
+
+func TestTrainingService_QueryRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/resource/TrainingRecord/QUERY", r.URL.Path)
+		var body QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{"field": "Employee", "type": "eq", "data": 42.0}, body.Search["s1"])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Id":5,"Employee":42,"Module":1,"TrainingDate":"2024-01-10","ExpiryDate":"2025-01-10","Active":true}]`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	records, err := client.Training().QueryRecords(context.Background(), &QueryInput{Search: map[string]interface{}{
+		"s1": map[string]interface{}{"field": "Employee", "type": "eq", "data": 42},
+	}})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "2025-01-10", records[0].ExpiryDate)
+}
+
+func TestTrainingService_AddRecord(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v1/resource/TrainingRecord", r.URL.Path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"Employee": 42.0, "Module": 1.0, "TrainingDate": "2024-07-01", "ExpiryDate": "2025-07-01", "Active": true,
+		}, body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Id":9,"Employee":42,"Module":1}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server.URL, "test-token")
+	record, err := client.Training().AddRecord(context.Background(), &CreateTrainingRecordInput{
+		Employee: 42, Module: 1, TrainingDate: "2024-07-01", ExpiryDate: "2025-07-01", Active: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 9, record.Id)
+}
@@ -0,0 +1,129 @@
+// Package audit keeps a local, append-only log of the changes the CLI makes
+// in Deputy. Each line of the log is one JSON-encoded Entry.
+package audit
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/deputy-cli/internal/config"
+)
+
+// Entry is one mutating request sent to Deputy.
+type Entry struct {
+	ID       string          `json:"id"`
+	Time     time.Time       `json:"time"`
+	Profile  string          `json:"profile"`
+	User     string          `json:"user"`
+	Command  string          `json:"command"`
+	Method   string          `json:"method"`
+	Endpoint string          `json:"endpoint"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	Status   int             `json:"status"`
+	ResultID int             `json:"resultId,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// writeMu serializes appends from concurrent workers in one process so lines
+// never interleave.
+var writeMu sync.Mutex
+
+// Path returns the location of the audit log.
+func Path() string {
+	return config.AuditLogPath()
+}
+
+// Append writes e as a new line at the end of the log, creating it if needed.
+func Append(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read returns every entry in the log, oldest first. A missing log is not an
+// error; it simply has no entries.
+func Read() ([]Entry, error) {
+	f, err := os.Open(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", Path(), line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Find returns the entry whose ID is id or starts with id.
+func Find(entries []Entry, id string) (Entry, error) {
+	var matches []Entry
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+		if strings.HasPrefix(e.ID, id) {
+			matches = append(matches, e)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Entry{}, fmt.Errorf("no audit entry %q", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return Entry{}, fmt.Errorf("audit entry prefix %q is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// NewID returns a short random identifier for an entry.
+func NewID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%012x", time.Now().UnixNano()&0xffffffffffff)
+	}
+	return hex.EncodeToString(b)
+}
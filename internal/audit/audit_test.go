@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := []byte(`{"strFirstName":"Jane","strPassword":"hunter2","nested":{"apiToken":"abc","list":[{"client_secret":"x","ok":1}]}}`)
+	got := RedactBody(body)
+	assert.JSONEq(t, `{"strFirstName":"Jane","strPassword":"[REDACTED]","nested":{"apiToken":"[REDACTED]","list":[{"client_secret":"[REDACTED]","ok":1}]}}`, string(got))
+
+	assert.JSONEq(t, `"not json"`, string(RedactBody([]byte("not json"))))
+	assert.Nil(t, RedactBody(nil))
+}
+
+func TestResultID(t *testing.T) {
+	assert.Equal(t, 42, ResultID([]byte(`{"Id":42,"Name":"x"}`)))
+	assert.Equal(t, 7, ResultID([]byte(`{"id":"7"}`)))
+	assert.Equal(t, 0, ResultID([]byte(`[{"Id":1}]`)))
+	assert.Equal(t, 0, ResultID(nil))
+}
+
+func TestRecorderAppendsAndReads(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+
+	entries, err := Read()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	rec := &Recorder{Command: "deputy webhooks add", User: "ops"}
+	observe := rec.Observer("acme.au.deputy.com")
+	observe(api.RequestEvent{
+		Method:       "POST",
+		URL:          "https://acme.au.deputy.com/api/v1/resource/Webhook?x=1",
+		RequestBody:  []byte(`{"Url":"https://example.com","Token":"t"}`),
+		StatusCode:   200,
+		ResponseBody: []byte(`{"Id":9}`),
+	})
+	observe(api.RequestEvent{Method: "DELETE", URL: "https://acme.au.deputy.com/api/v1/resource/Webhook/9", StatusCode: 404, ResponseBody: []byte(`{"Id":9}`)})
+
+	entries, err = Read()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	first := entries[0]
+	assert.Equal(t, "acme.au.deputy.com", first.Profile)
+	assert.Equal(t, "ops", first.User)
+	assert.Equal(t, "/api/v1/resource/Webhook?x=1", first.Endpoint)
+	assert.Equal(t, 9, first.ResultID)
+	assert.WithinDuration(t, time.Now(), first.Time, time.Minute)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(first.Body, &body))
+	assert.Equal(t, Redacted, body["Token"])
+
+	assert.Equal(t, 404, entries[1].Status)
+	assert.Zero(t, entries[1].ResultID)
+
+	found, err := Find(entries, first.ID[:6])
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	_, err = Find(entries, "zzz")
+	assert.Error(t, err)
+}
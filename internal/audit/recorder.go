@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+// Recorder turns the requests one command sends into audit entries.
+type Recorder struct {
+	// Command is the command line that caused the requests, with secret
+	// flag values already redacted.
+	Command string
+	// User is the OS user running the CLI.
+	User string
+	// Warn receives a single warning if the log cannot be written. Failing
+	// to audit never fails the command itself.
+	Warn io.Writer
+
+	warnOnce sync.Once
+}
+
+// NewRecorder returns a Recorder for command run by the current OS user.
+func NewRecorder(command string, warn io.Writer) *Recorder {
+	return &Recorder{Command: command, User: currentUser(), Warn: warn}
+}
+
+// Observer returns an api.RequestObserver that logs requests made against
+// the install identified by profile.
+func (r *Recorder) Observer(profile string) api.RequestObserver {
+	return func(ev api.RequestEvent) {
+		e := Entry{
+			ID:       NewID(),
+			Time:     time.Now().UTC(),
+			Profile:  profile,
+			User:     r.User,
+			Command:  r.Command,
+			Method:   ev.Method,
+			Endpoint: endpointPath(ev.URL),
+			Body:     RedactBody(ev.RequestBody),
+			Status:   ev.StatusCode,
+		}
+		if ev.Err != nil {
+			e.Error = ev.Err.Error()
+		}
+		if ev.StatusCode < 400 {
+			e.ResultID = ResultID(ev.ResponseBody)
+		}
+		if err := Append(e); err != nil && r.Warn != nil {
+			r.warnOnce.Do(func() {
+				_, _ = fmt.Fprintf(r.Warn, "warning: could not write audit log %s: %v\n", Path(), err)
+			})
+		}
+	}
+}
+
+// ProfileFromBaseURL names an install by its API host, e.g.
+// "acme.au.deputy.com".
+func ProfileFromBaseURL(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return baseURL
+}
+
+func endpointPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.RawQuery != "" {
+		return u.Path + "?" + u.RawQuery
+	}
+	return u.Path
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
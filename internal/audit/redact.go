@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Redacted replaces the value of any field that looks like a secret.
+const Redacted = "[REDACTED]"
+
+// secretKeyParts are matched case-insensitively against JSON keys and flag
+// names. Deputy field names carry type prefixes (strPassword), so a
+// substring match is used.
+var secretKeyParts = []string{"password", "passwd", "token", "secret", "authorization", "apikey", "api_key", "credential"}
+
+// IsSecretKey reports whether a field or flag name should have its value
+// hidden.
+func IsSecretKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, part := range secretKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactBody returns body with secret-looking fields replaced, at any depth.
+// Bodies that are not JSON are stored as a JSON string.
+func RedactBody(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		quoted, _ := json.Marshal(string(body))
+		return quoted
+	}
+	out, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if IsSecretKey(k) {
+				t[k] = Redacted
+			} else {
+				t[k] = redactValue(val)
+			}
+		}
+	case []any:
+		for i, val := range t {
+			t[i] = redactValue(val)
+		}
+	}
+	return v
+}
+
+// ResultID extracts the ID of the object a request created or changed from
+// its JSON response. Deputy returns the object with an "Id" field.
+func ResultID(response []byte) int {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(response, &obj); err != nil {
+		return 0
+	}
+	for _, key := range []string{"Id", "id", "ID"} {
+		raw, ok := obj[key]
+		if !ok {
+			continue
+		}
+		var id int
+		if err := json.Unmarshal(raw, &id); err == nil {
+			return id
+		}
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			var n int
+			if _, err := fmt.Sscanf(s, "%d", &n); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/salmonumbrella/deputy-cli/internal/audit"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+func newAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the local log of changes made through this CLI",
+		Long: "Every create, update, delete, approve or publish request the CLI sends is appended to\n" +
+			"a local JSONL audit log (audit.jsonl in the config directory). Secret-looking fields\n" +
+			"are redacted before they are written.",
+	}
+
+	cmd.AddCommand(newAuditListCmd())
+	cmd.AddCommand(newAuditShowCmd())
+	cmd.AddCommand(newAuditExportCmd())
+
+	return cmd
+}
+
+// auditFilter selects entries for the audit subcommands.
+type auditFilter struct {
+	Since    string
+	Method   string
+	Endpoint string
+	Failed   bool
+}
+
+func (af *auditFilter) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&af.Since, "since", "", "Only entries since a date (YYYY-MM-DD) or a duration ago (e.g. 24h, 7d)")
+	fs.StringVar(&af.Method, "method", "", "Only entries with this HTTP method (POST, PUT, DELETE)")
+	fs.StringVar(&af.Endpoint, "endpoint", "", "Only entries whose endpoint contains this text")
+	fs.BoolVar(&af.Failed, "failed", false, "Only requests that failed")
+}
+
+func (af *auditFilter) apply(entries []audit.Entry) ([]audit.Entry, error) {
+	var since time.Time
+	if af.Since != "" {
+		var err error
+		if since, err = parseSinceFlag(af.Since, "--since"); err != nil {
+			return nil, err
+		}
+	}
+	var out []audit.Entry
+	for _, e := range entries {
+		switch {
+		case !since.IsZero() && e.Time.Before(since):
+			continue
+		case af.Method != "" && !strings.EqualFold(e.Method, af.Method):
+			continue
+		case af.Endpoint != "" && !strings.Contains(strings.ToLower(e.Endpoint), strings.ToLower(af.Endpoint)):
+			continue
+		case af.Failed && e.Error == "" && e.Status < 400:
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func newAuditListCmd() *cobra.Command {
+	var filter auditFilter
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List audit entries, newest first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if limit < 0 {
+				return fmt.Errorf("invalid --limit %d: must not be negative", limit)
+			}
+			entries, err := audit.Read()
+			if err != nil {
+				return err
+			}
+			entries, err = filter.apply(entries)
+			if err != nil {
+				return err
+			}
+			for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+				entries[i], entries[j] = entries[j], entries[i]
+			}
+			if limit > 0 && len(entries) > limit {
+				entries = entries[:limit]
+			}
+
+			if outfmt.GetFormat(cmd.Context()) == "json" {
+				return outfmt.New(cmd.Context()).OutputList(entries)
+			}
+
+			f := outfmt.New(cmd.Context())
+			f.StartTable([]string{"ID", "TIME", "USER", "METHOD", "ENDPOINT", "STATUS", "RESULT"})
+			for _, e := range entries {
+				f.Row(
+					e.ID,
+					e.Time.Local().Format("2006-01-02 15:04:05"),
+					e.User,
+					e.Method,
+					e.Endpoint,
+					auditStatus(e),
+					auditResultID(e),
+				)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	filter.addFlags(cmd.Flags())
+	cmd.Flags().IntVar(&limit, "limit", 50, "Maximum number of entries (0 = all)")
+
+	return cmd
+}
+
+func newAuditShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <entry-id>",
+		Short: "Show one audit entry (an ID prefix is enough)",
+		Args:  RequireArg("entry-id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := audit.Read()
+			if err != nil {
+				return err
+			}
+			e, err := audit.Find(entries, args[0])
+			if err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(cmd.Context()) == "json" {
+				return outfmt.New(cmd.Context()).Output(e)
+			}
+
+			out := iocontext.FromContext(cmd.Context()).Out
+			_, _ = fmt.Fprintf(out, "ID:       %s\n", e.ID)
+			_, _ = fmt.Fprintf(out, "Time:     %s\n", e.Time.Local().Format(time.RFC3339))
+			_, _ = fmt.Fprintf(out, "Profile:  %s\n", e.Profile)
+			_, _ = fmt.Fprintf(out, "User:     %s\n", e.User)
+			_, _ = fmt.Fprintf(out, "Command:  %s\n", e.Command)
+			_, _ = fmt.Fprintf(out, "Request:  %s %s\n", e.Method, e.Endpoint)
+			_, _ = fmt.Fprintf(out, "Status:   %s\n", auditStatus(e))
+			if e.ResultID != 0 {
+				_, _ = fmt.Fprintf(out, "Result:   %d\n", e.ResultID)
+			}
+			if e.Error != "" {
+				_, _ = fmt.Fprintf(out, "Error:    %s\n", e.Error)
+			}
+			if len(e.Body) > 0 {
+				body, err := json.MarshalIndent(e.Body, "", "  ")
+				if err != nil {
+					body = e.Body
+				}
+				_, _ = fmt.Fprintf(out, "Body:\n%s\n", body)
+			}
+			return nil
+		},
+	}
+}
+
+func newAuditExportCmd() *cobra.Command {
+	var filter auditFilter
+	var format, outPath string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export audit entries as JSONL, JSON or CSV",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format = strings.ToLower(format)
+			if format != "jsonl" && format != "json" && format != "csv" {
+				return fmt.Errorf("invalid --format %q (expected jsonl, json or csv)", format)
+			}
+			entries, err := audit.Read()
+			if err != nil {
+				return err
+			}
+			entries, err = filter.apply(entries)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = iocontext.FromContext(cmd.Context()).Out
+			if outPath != "" && outPath != "-" {
+				file, err := os.OpenFile(outPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = file.Close() }()
+				w = file
+			}
+			return writeAuditEntries(w, format, entries)
+		},
+	}
+
+	filter.addFlags(cmd.Flags())
+	cmd.Flags().StringVar(&format, "format", "jsonl", "Export format: jsonl, json or csv")
+	cmd.Flags().StringVar(&outPath, "out", "", "Write to a file instead of stdout")
+
+	return cmd
+}
+
+func writeAuditEntries(w io.Writer, format string, entries []audit.Entry) error {
+	switch format {
+	case "json":
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case "csv":
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			rows = append(rows, []string{
+				e.ID,
+				e.Time.UTC().Format(time.RFC3339),
+				e.Profile,
+				e.User,
+				e.Command,
+				e.Method,
+				e.Endpoint,
+				strconv.Itoa(e.Status),
+				auditResultID(e),
+				e.Error,
+				string(e.Body),
+			})
+		}
+		return writeCSV(w, []string{"id", "time", "profile", "user", "command", "method", "endpoint", "status", "result_id", "error", "body"}, rows)
+	default:
+		enc := json.NewEncoder(w)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func auditStatus(e audit.Entry) string {
+	if e.Status == 0 {
+		return "error"
+	}
+	return strconv.Itoa(e.Status)
+}
+
+func auditResultID(e audit.Entry) string {
+	if e.ResultID == 0 {
+		return "-"
+	}
+	return strconv.Itoa(e.ResultID)
+}
+
+// auditCommandLine rebuilds the command line that is running, from the parsed
+// command, the flags that were set and the positional args. Values of
+// secret-looking flags are redacted.
+func auditCommandLine(cmd *cobra.Command, args []string) string {
+	parts := []string{cmd.CommandPath()}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		value := f.Value.String()
+		switch {
+		case audit.IsSecretKey(f.Name):
+			parts = append(parts, "--"+f.Name+"="+audit.Redacted)
+		case f.Value.Type() == "bool" && value == "true":
+			parts = append(parts, "--"+f.Name)
+		default:
+			parts = append(parts, "--"+f.Name+"="+shellQuote(value))
+		}
+	})
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote quotes s when it would not survive being pasted into a shell.
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`|&;<>()*?[]{}!#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/audit"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+)
+
+func runAuditRoot(t *testing.T, client *api.Client, args ...string) (string, error) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: client})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{In: &bytes.Buffer{}, Out: buf, ErrOut: &bytes.Buffer{}})
+
+	root := NewRootCmd()
+	root.SetArgs(args)
+	err := executeRoot(ctx, root)
+	return buf.String(), err
+}
+
+func TestAudit_RecordsMutations(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Webhook{Id: 77, Topic: "Timesheet.Insert", Url: "https://example.com/hook"})
+	}))
+	defer server.Close()
+	client := newTestClient(server.URL, "test-token")
+
+	_, err := runAuditRoot(t, client, "webhooks", "add", "--topic", "Timesheet.Insert", "--url", "https://example.com/hook", "-o", "json")
+	require.NoError(t, err)
+	// Reads are not audited.
+	_, err = runAuditRoot(t, client, "webhooks", "get", "77", "-o", "json")
+	require.NoError(t, err)
+	// Neither are dry runs, since nothing is sent.
+	_, err = runAuditRoot(t, client, "webhooks", "delete", "77", "--dry-run", "-o", "json")
+	require.NoError(t, err)
+
+	entries, err := audit.Read()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	e := entries[0]
+	assert.Equal(t, "POST", e.Method)
+	assert.Equal(t, "/api/v1/resource/Webhook", e.Endpoint)
+	assert.Equal(t, "test.au.deputy.com", e.Profile)
+	assert.Equal(t, 200, e.Status)
+	assert.Equal(t, 77, e.ResultID)
+	assert.Contains(t, e.Command, "deputy webhooks add")
+	assert.Contains(t, e.Command, "--topic=Timesheet.Insert")
+
+	out, err := runAuditRoot(t, client, "audit", "list", "-o", "json")
+	require.NoError(t, err)
+	var list struct {
+		Items []audit.Entry `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &list), out)
+	require.Len(t, list.Items, 1)
+
+	out, err = runAuditRoot(t, client, "audit", "show", e.ID[:4], "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "POST /api/v1/resource/Webhook")
+	assert.Contains(t, out, "Result:   77")
+
+	out, err = runAuditRoot(t, client, "audit", "export", "--format", "csv")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "id,time,profile"))
+	assert.Contains(t, lines[1], e.ID)
+
+	out, err = runAuditRoot(t, client, "audit", "list", "--method", "DELETE", "-o", "json")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"items": []`)
+}
+
+func TestAuditCommandLine_RedactsSecrets(t *testing.T) {
+	root := NewRootCmd()
+	cmd, _, err := root.Find([]string{"auth", "add"})
+	require.NoError(t, err)
+	require.NoError(t, cmd.ParseFlags([]string{"--token", "s3cret", "--install", "acme corp"}))
+
+	line := auditCommandLine(cmd, nil)
+	assert.Contains(t, line, "--token="+audit.Redacted)
+	assert.Contains(t, line, "--install='acme corp'")
+	assert.NotContains(t, line, "s3cret")
+}
+
+func TestParseSinceFlag(t *testing.T) {
+	d, err := parseRelativeDuration("7d")
+	require.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, d)
+	d, err = parseRelativeDuration("2w")
+	require.NoError(t, err)
+	assert.Equal(t, 14*24*time.Hour, d)
+	_, err = parseRelativeDuration("soon")
+	assert.Error(t, err)
+
+	since, err := parseSinceFlag("2024-03-01", "--since")
+	require.NoError(t, err)
+	assert.Equal(t, 2024, since.Year())
+	_, err = parseSinceFlag("yesterday", "--since")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --since")
+}
@@ -0,0 +1,709 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// allDay marks a day an employee is unavailable from start to finish.
+const allDay = "all day"
+
+// weekdayNames maps the accepted spellings of each weekday.
+var weekdayNames = map[string]time.Weekday{
+	"mo": time.Monday, "mon": time.Monday, "monday": time.Monday,
+	"tu": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"we": time.Wednesday, "wed": time.Wednesday, "wednesday": time.Wednesday,
+	"th": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fr": time.Friday, "fri": time.Friday, "friday": time.Friday,
+	"sa": time.Saturday, "sat": time.Saturday, "saturday": time.Saturday,
+	"su": time.Sunday, "sun": time.Sunday, "sunday": time.Sunday,
+}
+
+// byDayCode returns the iCalendar BYDAY code for a weekday (MO, TU, ...).
+func byDayCode(d time.Weekday) string {
+	return strings.ToUpper(d.String()[:2])
+}
+
+// parseWeekdays parses a comma-separated list such as "tue,thu".
+func parseWeekdays(s string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	seen := map[time.Weekday]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+		d, ok := weekdayNames[part]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q (expected mon, tue, wed, thu, fri, sat or sun)", part)
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	if len(days) == 0 {
+		return nil, errors.New("--weekly needs at least one weekday")
+	}
+	return days, nil
+}
+
+// parseClock parses a HH:MM time of day into minutes after midnight. 24:00
+// is accepted as the end of the day.
+func parseClock(s, flagName string) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	hours, herr := strconv.Atoi(h)
+	minutes, merr := strconv.Atoi(m)
+	if !ok || herr != nil || merr != nil || hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid %s %q: expected HH:MM", flagName, s)
+	}
+	return hours*60 + minutes, nil
+}
+
+// atClock returns date (YYYY-MM-DD) at the given minutes after midnight in tz.
+func atClock(date string, minutes int, tz *time.Location) time.Time {
+	day, _ := time.ParseInLocation("2006-01-02", date, tz)
+	return day.Add(time.Duration(minutes) * time.Minute)
+}
+
+func clockLabel(t time.Time) string {
+	return t.Format("15:04")
+}
+
+// unavailableBlocks returns what u makes unavailable on day (midnight in tz):
+// nothing, "all day" or a HH:MM-HH:MM span. Recurring records repeat weekly
+// from their first period; records added by date only cover whole days.
+func unavailableBlocks(u api.Unavailability, day time.Time, tz *time.Location) []string {
+	date := day.Format("2006-01-02")
+	if u.StartTime == 0 || u.EndTime == 0 {
+		if datePart(u.DateStart) <= date && date <= datePart(u.DateEnd) {
+			return []string{allDay}
+		}
+		return nil
+	}
+
+	start, end := time.Unix(u.StartTime, 0).In(tz), time.Unix(u.EndTime, 0).In(tz)
+	if u.Schedule != 0 {
+		first := start.Format("2006-01-02")
+		if day.Weekday() != start.Weekday() || date < first {
+			return nil
+		}
+		// Move the first period forward to this week's occurrence.
+		shift := day.Sub(atClock(first, 0, tz))
+		start, end = start.Add(shift), end.Add(shift)
+	}
+
+	dayEnd := day.AddDate(0, 0, 1)
+	if !start.Before(dayEnd) || !end.After(day) {
+		return nil
+	}
+	from, to := start, end
+	if from.Before(day) {
+		from = day
+	}
+	if to.After(dayEnd) {
+		to = dayEnd
+	}
+	if from.Equal(day) && to.Add(time.Minute).After(dayEnd) {
+		return []string{allDay}
+	}
+	toLabel := clockLabel(to)
+	if to.Equal(dayEnd) {
+		toLabel = "24:00"
+	}
+	return []string{clockLabel(from) + "-" + toLabel}
+}
+
+// describeUnavailability summarises when a record applies, in tz.
+func describeUnavailability(u api.Unavailability, tz *time.Location) string {
+	if u.StartTime == 0 || u.EndTime == 0 {
+		return fmt.Sprintf("%s to %s", datePart(u.DateStart), datePart(u.DateEnd))
+	}
+	start, end := time.Unix(u.StartTime, 0).In(tz), time.Unix(u.EndTime, 0).In(tz)
+	if u.Schedule != 0 {
+		return fmt.Sprintf("every %s %s-%s from %s", start.Format("Mon"), clockLabel(start), clockLabel(end), start.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%s to %s", start.Format("2006-01-02 15:04"), end.Format("2006-01-02 15:04"))
+}
+
+// availabilityTimezone returns the timezone of locationID, or local time
+// when no location is given.
+func availabilityTimezone(ctx context.Context, client *api.Client, locationID int) (*time.Location, error) {
+	if locationID == 0 {
+		return time.Local, nil
+	}
+	return locationTimezone(ctx, client, locationID)
+}
+
+// locationEmployees returns the active employees whose main location is
+// locationID, ordered by name.
+func locationEmployees(ctx context.Context, client *api.Client, locationID int) ([]api.Employee, error) {
+	employees, err := client.Employees().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	var out []api.Employee
+	for _, e := range employees {
+		if e.Active && e.Company == locationID {
+			out = append(out, e)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].DisplayName < out[j].DisplayName })
+	return out, nil
+}
+
+// listUnavailabilityFor reads the unavailability of each employee, at most
+// concurrency at a time. Results are returned in employee order.
+func listUnavailabilityFor(ctx context.Context, client *api.Client, employeeIDs []int, concurrency int) ([][]api.Unavailability, error) {
+	results := make([][]api.Unavailability, len(employeeIDs))
+	errs := make([]error, len(employeeIDs))
+	forEachConcurrent(len(employeeIDs), concurrency, func(i int) {
+		results[i], errs[i] = client.Employees().ListUnavailability(ctx, employeeIDs[i])
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("employee %d: %w", employeeIDs[i], err)
+		}
+	}
+	return results, nil
+}
+
+func newAvailabilityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "availability",
+		Aliases: []string{"unavailability", "unavail", "avail"},
+		Short:   "Manage employee unavailability",
+		Long: `Manage when employees are unavailable to work: one-off date or time ranges
+and recurring weekly patterns. Use "availability grid" to see a location's
+team for a week before building rosters.`,
+	}
+
+	cmd.AddCommand(newAvailabilityListCmd())
+	cmd.AddCommand(newAvailabilityGetCmd())
+	cmd.AddCommand(newAvailabilityAddCmd())
+	cmd.AddCommand(newAvailabilityUpdateCmd())
+	cmd.AddCommand(newAvailabilityDeleteCmd())
+	cmd.AddCommand(newAvailabilityGridCmd())
+
+	return cmd
+}
+
+func newAvailabilityListCmd() *cobra.Command {
+	var employeeID, locationID, concurrency int
+	var fromDate, toDate string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List unavailability for an employee or a location's team",
+		Example: `  deputy availability list --employee 42
+  deputy availability list --location code:CBD --from 2024-07-01 --to 2024-07-07`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if employeeID == 0 && locationID == 0 {
+				return errors.New("--employee or --location is required")
+			}
+			for _, d := range []string{fromDate, toDate} {
+				if d == "" {
+					continue
+				}
+				if err := validateDateFormat(d); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			tz, err := availabilityTimezone(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+
+			names := map[int]string{}
+			employeeIDs := []int{employeeID}
+			if employeeID == 0 {
+				employees, err := locationEmployees(ctx, client, locationID)
+				if err != nil {
+					return err
+				}
+				employeeIDs = employeeIDs[:0]
+				for _, e := range employees {
+					employeeIDs = append(employeeIDs, e.Id)
+					names[e.Id] = e.DisplayName
+				}
+			}
+			perEmployee, err := listUnavailabilityFor(ctx, client, employeeIDs, concurrency)
+			if err != nil {
+				return err
+			}
+
+			records := []api.Unavailability{}
+			for _, list := range perEmployee {
+				for _, u := range list {
+					if unavailabilityOverlaps(u, fromDate, toDate, tz) {
+						records = append(records, u)
+					}
+				}
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputList(records)
+			}
+			f := outfmt.New(ctx)
+			f.StartTable([]string{"ID", "EMPLOYEE", "WHEN", "COMMENT"})
+			for _, u := range records {
+				f.Row(strconv.Itoa(u.Id), employeeLabel(u.Employee, names[u.Employee]), describeUnavailability(u, tz), u.Comment)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&employeeID, "employee", 0, "Employee ID")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Location ID (lists its active employees and sets the timezone)")
+	cmd.Flags().StringVar(&fromDate, "from", "", "Only unavailability on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&toDate, "to", "", "Only unavailability on or before this date (YYYY-MM-DD)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Employees read at once")
+
+	return cmd
+}
+
+// unavailabilityOverlaps reports whether u applies on any day within
+// [from, to] (either may be empty).
+func unavailabilityOverlaps(u api.Unavailability, from, to string, tz *time.Location) bool {
+	first, last := datePart(u.DateStart), datePart(u.DateEnd)
+	if u.StartTime != 0 && u.EndTime != 0 {
+		first = time.Unix(u.StartTime, 0).In(tz).Format("2006-01-02")
+		last = time.Unix(u.EndTime, 0).In(tz).Format("2006-01-02")
+	}
+	if u.Schedule != 0 {
+		// Recurring records have no end.
+		return to == "" || first <= to
+	}
+	return (to == "" || first <= to) && (from == "" || last >= from)
+}
+
+func newAvailabilityGetCmd() *cobra.Command {
+	var locationID int
+
+	cmd := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get an unavailability record",
+		Args:  RequireArg("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid unavailability ID: %s", args[0])
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			u, err := client.Employees().GetUnavailability(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).Output(u)
+			}
+			tz, err := availabilityTimezone(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+			io := iocontext.FromContext(ctx)
+			_, _ = fmt.Fprintf(io.Out, "ID:       %d\n", u.Id)
+			_, _ = fmt.Fprintf(io.Out, "Employee: %d\n", u.Employee)
+			_, _ = fmt.Fprintf(io.Out, "When:     %s\n", describeUnavailability(*u, tz))
+			if u.Comment != "" {
+				_, _ = fmt.Fprintf(io.Out, "Comment:  %s\n", u.Comment)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&locationID, "location", 0, "Show times in this location's timezone")
+
+	return cmd
+}
+
+func newAvailabilityAddCmd() *cobra.Command {
+	var employeeID, locationID int
+	var startDate, endDate, fromTime, toTime, weekly, comment string
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a one-off or weekly unavailability",
+		Long: `Add unavailability for an employee.
+
+Without --weekly, the employee is unavailable from --start to --end (whole
+days, or from --from on the first day to --to on the last). With --weekly,
+they are unavailable between --from and --to on each listed weekday, every
+week from --start (default today). Times are in the --location's timezone, or
+local time.`,
+		Example: `  deputy availability add --employee 42 --start 2024-07-10 --end 2024-07-12 --comment Exams
+  deputy availability add --employee 42 --start 2024-07-10 --from 09:00 --to 13:00
+  deputy availability add --employee 42 --weekly tue,thu --from 15:00 --location code:CBD`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if employeeID == 0 {
+				return errors.New("--employee is required")
+			}
+			var days []time.Weekday
+			if weekly != "" {
+				var err error
+				if days, err = parseWeekdays(weekly); err != nil {
+					return err
+				}
+				if endDate != "" {
+					return errors.New("--end cannot be used with --weekly")
+				}
+			} else if startDate == "" {
+				return errors.New("--start is required")
+			}
+			for _, d := range []string{startDate, endDate} {
+				if d == "" {
+					continue
+				}
+				if err := validateDateFormat(d); err != nil {
+					return err
+				}
+			}
+			if endDate == "" {
+				endDate = startDate
+			}
+			if endDate < startDate {
+				return errors.New("--end must not be before --start")
+			}
+			timed := weekly != "" || fromTime != "" || toTime != ""
+			if fromTime == "" {
+				fromTime = "00:00"
+			}
+			if toTime == "" {
+				toTime = "23:59"
+			}
+			fromMin, err := parseClock(fromTime, "--from")
+			if err != nil {
+				return err
+			}
+			toMin, err := parseClock(toTime, "--to")
+			if err != nil {
+				return err
+			}
+			if (weekly != "" || startDate == endDate) && toMin <= fromMin {
+				return errors.New("--to must be after --from")
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			var created []api.Unavailability
+			if !timed {
+				u, err := client.Employees().AddUnavailability(ctx, &api.CreateUnavailabilityInput{
+					Employee:  employeeID,
+					DateStart: startDate,
+					DateEnd:   endDate,
+					Comment:   comment,
+				})
+				if err != nil {
+					return err
+				}
+				created = append(created, *u)
+			} else {
+				tz, err := availabilityTimezone(ctx, client, locationID)
+				if err != nil {
+					return err
+				}
+				if weekly == "" {
+					u, err := client.Employees().AddTimedUnavailability(ctx, &api.CreateTimedUnavailabilityInput{
+						Employee: employeeID,
+						Start:    api.UnavailabilityTime{Timestamp: atClock(startDate, fromMin, tz).Unix()},
+						End:      api.UnavailabilityTime{Timestamp: atClock(endDate, toMin, tz).Unix()},
+						Comment:  comment,
+					})
+					if err != nil {
+						return err
+					}
+					created = append(created, *u)
+				} else {
+					if startDate == "" {
+						startDate = time.Now().In(tz).Format("2006-01-02")
+					}
+					// Each weekday gets its own weekly record starting on its
+					// first occurrence on or after --start.
+					for _, d := range days {
+						first := atClock(startDate, 0, tz)
+						first = first.AddDate(0, 0, (int(d)-int(first.Weekday())+7)%7)
+						date := first.Format("2006-01-02")
+						u, err := client.Employees().AddTimedUnavailability(ctx, &api.CreateTimedUnavailabilityInput{
+							Employee:   employeeID,
+							Start:      api.UnavailabilityTime{Timestamp: atClock(date, fromMin, tz).Unix()},
+							End:        api.UnavailabilityTime{Timestamp: atClock(date, toMin, tz).Unix()},
+							Comment:    comment,
+							Recurrence: &api.UnavailabilityRecurrence{Freq: "WEEKLY", Interval: 1, ByDay: byDayCode(d)},
+						})
+						if err != nil {
+							return fmt.Errorf("%s: %w", d, err)
+						}
+						created = append(created, *u)
+					}
+				}
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputList(created)
+			}
+			io := iocontext.FromContext(ctx)
+			for _, u := range created {
+				_, _ = fmt.Fprintf(io.Out, "Added unavailability %d for employee %d\n", u.Id, employeeID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&employeeID, "employee", 0, "Employee ID (required)")
+	cmd.Flags().StringVar(&startDate, "start", "", "First day YYYY-MM-DD (required unless --weekly)")
+	cmd.Flags().StringVar(&endDate, "end", "", "Last day YYYY-MM-DD (default: --start)")
+	cmd.Flags().StringVar(&fromTime, "from", "", "Start time HH:MM (default 00:00)")
+	cmd.Flags().StringVar(&toTime, "to", "", "End time HH:MM (default 23:59)")
+	cmd.Flags().StringVar(&weekly, "weekly", "", "Repeat weekly on these days (e.g. tue,thu)")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Location whose timezone the times are in")
+	cmd.Flags().StringVar(&comment, "comment", "", "Comment")
+
+	return cmd
+}
+
+func newAvailabilityUpdateCmd() *cobra.Command {
+	var startDate, endDate, comment string
+
+	cmd := &cobra.Command{
+		Use:   "update <id>",
+		Short: "Change the dates or comment of an unavailability",
+		Example: `  deputy availability update 11 --end 2024-07-14
+  deputy availability update 11 --comment "Exams (moved)"`,
+		Args: RequireArg("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid unavailability ID: %s", args[0])
+			}
+			input := &api.UpdateUnavailabilityInput{DateStart: startDate, DateEnd: endDate}
+			if cmd.Flags().Changed("comment") {
+				input.Comment = &comment
+			}
+			if startDate == "" && endDate == "" && input.Comment == nil {
+				return errors.New("nothing to update: set --start, --end or --comment")
+			}
+			for _, d := range []string{startDate, endDate} {
+				if d == "" {
+					continue
+				}
+				if err := validateDateFormat(d); err != nil {
+					return err
+				}
+			}
+			if startDate != "" && endDate != "" && endDate < startDate {
+				return errors.New("--end must not be before --start")
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			u, err := client.Employees().UpdateUnavailability(ctx, id, input)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).Output(u)
+			}
+			io := iocontext.FromContext(ctx)
+			_, _ = fmt.Fprintf(io.Out, "Updated unavailability %d\n", id)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&startDate, "start", "", "New first day YYYY-MM-DD")
+	cmd.Flags().StringVar(&endDate, "end", "", "New last day YYYY-MM-DD")
+	cmd.Flags().StringVar(&comment, "comment", "", "New comment (empty to clear)")
+
+	return cmd
+}
+
+func newAvailabilityDeleteCmd() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Delete an unavailability record",
+		Args:  RequireArg("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid unavailability ID: %s", args[0])
+			}
+
+			if err := confirmDestructive(cmd.Context(), yes, fmt.Sprintf("Are you sure you want to delete unavailability %d?", id)); err != nil {
+				return err
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if err := client.Employees().DeleteUnavailability(cmd.Context(), id); err != nil {
+				return err
+			}
+
+			io := iocontext.FromContext(cmd.Context())
+			_, _ = fmt.Fprintf(io.Out, "Deleted unavailability %d\n", id)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+// AvailabilityDay is one employee's unavailability on one day of the grid.
+type AvailabilityDay struct {
+	Date string `json:"date"`
+	// Unavailable lists "all day" or HH:MM-HH:MM spans; empty means available.
+	Unavailable []string `json:"unavailable"`
+}
+
+// AvailabilityRow is one employee's week in the grid.
+type AvailabilityRow struct {
+	Employee     int               `json:"employee"`
+	EmployeeName string            `json:"employeeName,omitempty"`
+	Days         []AvailabilityDay `json:"days"`
+}
+
+// buildAvailabilityGrid lays out each employee's unavailability over the
+// seven days from weekStart (midnight in tz).
+func buildAvailabilityGrid(employees []api.Employee, records [][]api.Unavailability, weekStart time.Time, tz *time.Location) []AvailabilityRow {
+	rows := make([]AvailabilityRow, 0, len(employees))
+	for i, e := range employees {
+		row := AvailabilityRow{Employee: e.Id, EmployeeName: e.DisplayName}
+		for d := 0; d < 7; d++ {
+			day := weekStart.AddDate(0, 0, d)
+			cell := AvailabilityDay{Date: day.Format("2006-01-02"), Unavailable: []string{}}
+			for _, u := range records[i] {
+				cell.Unavailable = append(cell.Unavailable, unavailableBlocks(u, day, tz)...)
+			}
+			sort.Strings(cell.Unavailable)
+			row.Days = append(row.Days, cell)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func newAvailabilityGridCmd() *cobra.Command {
+	var locationID, concurrency int
+	var week string
+
+	cmd := &cobra.Command{
+		Use:   "grid",
+		Short: "Show a location's team availability for a week",
+		Long: `Show when each active employee at a location is unavailable over a week.
+Each cell is blank when the employee is available all day, "all day" when
+they are not, or the times they are unavailable.`,
+		Example: `  deputy availability grid --location code:CBD
+  deputy availability grid --location 3 --week 2024-07-01 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if locationID == 0 {
+				return errors.New("--location is required")
+			}
+			if week != "" {
+				if err := validateDateFormat(week); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			tz, err := locationTimezone(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+			var weekStart time.Time
+			if week != "" {
+				weekStart = atClock(week, 0, tz)
+			} else {
+				// Default to this week, starting Monday.
+				today := atClock(time.Now().In(tz).Format("2006-01-02"), 0, tz)
+				weekStart = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+			}
+
+			employees, err := locationEmployees(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+			ids := make([]int, len(employees))
+			for i, e := range employees {
+				ids[i] = e.Id
+			}
+			records, err := listUnavailabilityFor(ctx, client, ids, concurrency)
+			if err != nil {
+				return err
+			}
+			rows := buildAvailabilityGrid(employees, records, weekStart, tz)
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputWithMeta(rows, map[string]interface{}{
+					"count":    len(rows),
+					"location": locationID,
+					"week":     weekStart.Format("2006-01-02"),
+				})
+			}
+			headers := []string{"EMPLOYEE"}
+			for d := 0; d < 7; d++ {
+				headers = append(headers, strings.ToUpper(weekStart.AddDate(0, 0, d).Format("Mon 01-02")))
+			}
+			f := outfmt.New(ctx)
+			f.StartTable(headers)
+			for _, r := range rows {
+				values := []string{employeeLabel(r.Employee, r.EmployeeName)}
+				for _, d := range r.Days {
+					values = append(values, strings.Join(d.Unavailable, ", "))
+				}
+				f.Row(values...)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&locationID, "location", 0, "Location ID (required)")
+	cmd.Flags().StringVar(&week, "week", "", "First day of the week YYYY-MM-DD (default: this Monday)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Employees read at once")
+
+	return cmd
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+func TestUnavailableBlocks(t *testing.T) {
+	tz, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	at := func(s string) int64 {
+		ts, err := time.ParseInLocation("2006-01-02 15:04", s, tz)
+		require.NoError(t, err)
+		return ts.Unix()
+	}
+	day := func(s string) time.Time { return atClock(s, 0, tz) }
+
+	// Every Tuesday from 2 July, 15:00 to 23:59.
+	weekly := api.Unavailability{StartTime: at("2024-07-02 15:00"), EndTime: at("2024-07-02 23:59"), Schedule: 5}
+	assert.Equal(t, []string{"15:00-23:59"}, unavailableBlocks(weekly, day("2024-07-16"), tz))
+	assert.Nil(t, unavailableBlocks(weekly, day("2024-07-17"), tz))
+	assert.Nil(t, unavailableBlocks(weekly, day("2024-06-25"), tz))
+	assert.Equal(t, "every Tue 15:00-23:59 from 2024-07-02", describeUnavailability(weekly, tz))
+
+	// A one-off range spanning midnight covers the end of one day and the
+	// start of the next.
+	overnight := api.Unavailability{StartTime: at("2024-07-10 20:00"), EndTime: at("2024-07-12 09:00")}
+	assert.Equal(t, []string{"20:00-24:00"}, unavailableBlocks(overnight, day("2024-07-10"), tz))
+	assert.Equal(t, []string{allDay}, unavailableBlocks(overnight, day("2024-07-11"), tz))
+	assert.Equal(t, []string{"00:00-09:00"}, unavailableBlocks(overnight, day("2024-07-12"), tz))
+
+	byDate := api.Unavailability{DateStart: "2024-07-10T00:00:00+10:00", DateEnd: "2024-07-11"}
+	assert.Equal(t, []string{allDay}, unavailableBlocks(byDate, day("2024-07-11"), tz))
+	assert.Nil(t, unavailableBlocks(byDate, day("2024-07-12"), tz))
+}
+
+func TestAvailabilityAdd_Weekly(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []api.CreateTimedUnavailabilityInput
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/resource/Company/3":
+			_, _ = w.Write([]byte(`{"Id":3,"Timezone":"Australia/Sydney"}`))
+		case "POST /api/v1/supervise/unavail":
+			var body api.CreateTimedUnavailabilityInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			mu.Lock()
+			bodies = append(bodies, body)
+			n := len(bodies)
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(api.Unavailability{Id: 100 + n, Employee: 42})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "availability", "add", "--employee", "42", "--weekly", "thu,tue",
+		"--start", "2024-07-01", "--from", "15:00", "--location", "3", "--comment", "Uni", "-o", "text")
+	require.NoError(t, err)
+	assert.Equal(t, "Added unavailability 101 for employee 42\nAdded unavailability 102 for employee 42\n", out)
+
+	tz, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	require.Len(t, bodies, 2)
+	assert.Equal(t, "TH", bodies[0].Recurrence.ByDay)
+	assert.Equal(t, time.Date(2024, 7, 4, 15, 0, 0, 0, tz).Unix(), bodies[0].Start.Timestamp)
+	assert.Equal(t, time.Date(2024, 7, 4, 23, 59, 0, 0, tz).Unix(), bodies[0].End.Timestamp)
+	assert.Equal(t, "TU", bodies[1].Recurrence.ByDay)
+	assert.Equal(t, time.Date(2024, 7, 2, 15, 0, 0, 0, tz).Unix(), bodies[1].Start.Timestamp)
+	assert.Equal(t, "Uni", bodies[1].Comment)
+}
+
+func TestAvailabilityAdd_DateRangeUsesResource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/EmployeeAvailability", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Id":7,"Employee":42,"DateStart":"2024-07-10","DateEnd":"2024-07-12"}`))
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "availability", "add", "--employee", "42", "--start", "2024-07-10", "--end", "2024-07-12", "-o", "json")
+	require.NoError(t, err)
+	var parsed struct {
+		Items []api.Unavailability `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 1)
+	assert.Equal(t, 7, parsed.Items[0].Id)
+}
+
+func TestAvailabilityAdd_ValidatesFlags(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"add", "--start", "2024-07-01"}, "--employee is required"},
+		{[]string{"add", "--employee", "42"}, "--start is required"},
+		{[]string{"add", "--employee", "42", "--weekly", "tue", "--end", "2024-07-01"}, "--end cannot be used with --weekly"},
+		{[]string{"add", "--employee", "42", "--weekly", "tue,funday"}, `invalid weekday "funday" (expected mon, tue, wed, thu, fri, sat or sun)`},
+		{[]string{"add", "--employee", "42", "--weekly", "tue", "--from", "3pm"}, `invalid --from "3pm": expected HH:MM`},
+		{[]string{"add", "--employee", "42", "--start", "2024-07-01", "--from", "17:00", "--to", "09:00"}, "--to must be after --from"},
+		{[]string{"update", "11"}, "nothing to update: set --start, --end or --comment"},
+	} {
+		_, _, err := runRoot(t, server, "", append([]string{"availability"}, tc.args...)...)
+		assert.EqualError(t, err, tc.want, strings.Join(tc.args, " "))
+	}
+}
+
+func TestAvailabilityGrid(t *testing.T) {
+	tz, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	tuesday := time.Date(2024, 7, 2, 15, 0, 0, 0, tz)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/resource/Company/3":
+			_, _ = w.Write([]byte(`{"Id":3,"Timezone":"Australia/Sydney"}`))
+		case "/api/v1/supervise/employee":
+			_, _ = w.Write([]byte(`[{"Id":42,"DisplayName":"Jane Doe","Company":3,"Active":true},{"Id":43,"DisplayName":"Old Timer","Company":3,"Active":false},{"Id":44,"DisplayName":"Al Bee","Company":3,"Active":true}]`))
+		case "/api/v1/resource/EmployeeAvailability/QUERY":
+			var body api.QueryInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body.Search["s1"].(map[string]interface{})["data"] == 42.0 {
+				_ = json.NewEncoder(w).Encode([]api.Unavailability{
+					{Id: 1, Employee: 42, StartTime: tuesday.Unix(), EndTime: tuesday.Add(8*time.Hour + 59*time.Minute).Unix(), Schedule: 5},
+					{Id: 2, Employee: 42, DateStart: "2024-07-12", DateEnd: "2024-07-20"},
+				})
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "availability", "grid", "--location", "3", "--week", "2024-07-08", "-o", "json")
+	require.NoError(t, err)
+	var parsed struct {
+		Items []AvailabilityRow      `json:"items"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 2)
+	assert.Equal(t, "Al Bee", parsed.Items[0].EmployeeName)
+	jane := parsed.Items[1]
+	assert.Equal(t, "2024-07-09", jane.Days[1].Date)
+	assert.Equal(t, []string{"15:00-23:59"}, jane.Days[1].Unavailable)
+	assert.Empty(t, jane.Days[3].Unavailable)
+	assert.Equal(t, []string{allDay}, jane.Days[4].Unavailable)
+	assert.Equal(t, "2024-07-08", parsed.Meta["week"])
+
+	out, _, err = runRoot(t, server, "", "availability", "grid", "--location", "3", "--week", "2024-07-08", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "TUE 07-09")
+	assert.Contains(t, out, "Jane Doe (42)")
+	assert.NotContains(t, out, "Old Timer")
+}
+
+func TestAvailabilityDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "DELETE", r.Method)
+		assert.Equal(t, "/api/v1/resource/EmployeeAvailability/11", r.URL.Path)
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "availability", "delete", "11", "--yes", "-o", "text")
+	require.NoError(t, err)
+	assert.Equal(t, "Deleted unavailability 11\n", out)
+}
@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+)
+
+// batchOp is one line of `deputy batch` input. Which fields are used depends
+// on Op; unknown fields are rejected so typos fail loudly.
+type batchOp struct {
+	Op       string                 `json:"op"`
+	ID       int                    `json:"id,omitempty"`
+	Name     string                 `json:"name,omitempty"`
+	Data     map[string]interface{} `json:"data,omitempty"`
+	Comment  string                 `json:"comment,omitempty"`
+	Date     string                 `json:"date,omitempty"`
+	Location int                    `json:"location,omitempty"`
+	Rule     int                    `json:"rule,omitempty"`
+	Cost     *float64               `json:"cost,omitempty"`
+	// Ref is echoed back unchanged so callers can correlate results.
+	Ref json.RawMessage `json:"ref,omitempty"`
+}
+
+// batchResult is one line of `deputy batch` output.
+type batchResult struct {
+	Line   int              `json:"line"`
+	Op     string           `json:"op,omitempty"`
+	ID     int              `json:"id,omitempty"`
+	Ref    json.RawMessage  `json:"ref,omitempty"`
+	OK     bool             `json:"ok"`
+	Result interface{}      `json:"result,omitempty"`
+	Error  *JSONErrorDetail `json:"error,omitempty"`
+}
+
+// batchHandler runs one operation. It returns what to report as "result".
+type batchHandler struct {
+	usage string
+	run   func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error)
+}
+
+func requireBatchID(op *batchOp) error {
+	if op.ID <= 0 {
+		return errors.New("id is required")
+	}
+	return nil
+}
+
+func requireBatchName(op *batchOp) error {
+	if op.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+var batchHandlers = map[string]batchHandler{
+	"leave.approve": {`{"op":"leave.approve","id":12}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		return nil, b.client.Leave().Approve(ctx, op.ID)
+	}},
+	"leave.decline": {`{"op":"leave.decline","id":12,"comment":"..."}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		return nil, b.client.Leave().Decline(ctx, op.ID, op.Comment)
+	}},
+	"employee.terminate": {`{"op":"employee.terminate","id":5,"date":"2024-03-31"}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		if err := validateDateFormat(op.Date); err != nil {
+			return nil, fmt.Errorf("date: %w", err)
+		}
+		return nil, b.client.Employees().Terminate(ctx, op.ID, op.Date)
+	}},
+	"employee.reactivate": {`{"op":"employee.reactivate","id":5}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		return nil, b.client.Employees().Reactivate(ctx, op.ID)
+	}},
+	"employee.invite": {`{"op":"employee.invite","id":5}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		return nil, b.client.Employees().Invite(ctx, op.ID)
+	}},
+	"employee.assign-location": {`{"op":"employee.assign-location","id":5,"location":1}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		if op.Location <= 0 {
+			return nil, errors.New("location is required")
+		}
+		return nil, b.client.Employees().AssignLocation(ctx, op.ID, op.Location)
+	}},
+	"employee.remove-location": {`{"op":"employee.remove-location","id":5,"location":1}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		if op.Location <= 0 {
+			return nil, errors.New("location is required")
+		}
+		return nil, b.client.Employees().RemoveLocation(ctx, op.ID, op.Location)
+	}},
+	"timesheet.update": {`{"op":"timesheet.update","id":7,"cost":120.5}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		if op.Cost == nil {
+			return nil, errors.New("cost is required")
+		}
+		return b.client.Timesheets().Update(ctx, op.ID, &api.UpdateTimesheetInput{Cost: op.Cost})
+	}},
+	"timesheet.select-pay-rule": {`{"op":"timesheet.select-pay-rule","id":7,"rule":3}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		if op.Rule <= 0 {
+			return nil, errors.New("rule is required")
+		}
+		return b.client.Timesheets().ChangePayRule(ctx, op.ID, op.Rule)
+	}},
+	"resource.get": {`{"op":"resource.get","name":"Task","id":3}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchName(op); err != nil {
+			return nil, err
+		}
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		return b.client.Resource(op.Name).Get(ctx, op.ID)
+	}},
+	"resource.create": {`{"op":"resource.create","name":"Task","data":{...}}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := b.checkResourceData(ctx, op); err != nil {
+			return nil, err
+		}
+		return b.client.Resource(op.Name).Create(ctx, op.Data)
+	}},
+	"resource.update": {`{"op":"resource.update","name":"Employee","id":5,"data":{...}}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		if err := b.checkResourceData(ctx, op); err != nil {
+			return nil, err
+		}
+		return b.client.Resource(op.Name).Update(ctx, op.ID, op.Data)
+	}},
+	"resource.delete": {`{"op":"resource.delete","name":"Memo","id":3}`, func(ctx context.Context, b *batchRunner, op *batchOp) (interface{}, error) {
+		if err := requireBatchName(op); err != nil {
+			return nil, err
+		}
+		if err := requireBatchID(op); err != nil {
+			return nil, err
+		}
+		return nil, b.client.Resource(op.Name).Delete(ctx, op.ID)
+	}},
+}
+
+// batchRunner holds state shared by the workers of one batch.
+type batchRunner struct {
+	client *api.Client
+
+	infoMu sync.Mutex
+	infos  map[string]*api.ResourceInfo
+}
+
+// checkResourceData validates op.Data against the resource schema, fetching
+// each resource's INFO once per batch.
+func (b *batchRunner) checkResourceData(ctx context.Context, op *batchOp) error {
+	if err := requireBatchName(op); err != nil {
+		return err
+	}
+	if len(op.Data) == 0 {
+		return errors.New("data is required")
+	}
+	// Held across the fetch so concurrent workers wait for the first one
+	// instead of all fetching the same schema.
+	b.infoMu.Lock()
+	info, ok := b.infos[op.Name]
+	if !ok {
+		var err error
+		info, err = b.client.Resource(op.Name).Info(ctx)
+		if err != nil {
+			b.infoMu.Unlock()
+			return err
+		}
+		b.infos[op.Name] = info
+	}
+	b.infoMu.Unlock()
+	return validateResourcePayload(info, op.Data)
+}
+
+// parseBatchLine decodes one input line.
+func parseBatchLine(line []byte) (*batchOp, error) {
+	var op batchOp
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&op); err != nil {
+		return nil, fmt.Errorf("invalid operation: %w", err)
+	}
+	if op.Op == "" {
+		return &op, errors.New("op is required")
+	}
+	if _, ok := batchHandlers[op.Op]; !ok {
+		return &op, fmt.Errorf("unknown op %q (see 'deputy batch --help')", op.Op)
+	}
+	return &op, nil
+}
+
+func batchOpsHelp() string {
+	names := make([]string, 0, len(batchHandlers))
+	for name := range batchHandlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for _, name := range names {
+		_, _ = fmt.Fprintf(&sb, "  %s\n", batchHandlers[name].usage)
+	}
+	return sb.String()
+}
+
+func newBatchCmd() *cobra.Command {
+	var file string
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Run operations from JSON Lines on stdin",
+		Long: `Run one operation per input line (JSON Lines) and print one JSON result line
+per operation as it finishes. Results carry the input line number, the op,
+its id and any "ref" given in the input; failures carry the same error object
+as JSON error output. Lines are processed by --concurrency workers; the
+global --rate and --max-in-flight limits apply to the API requests they send.
+
+Operations:
+` + batchOpsHelp() + `
+Exits 1 if any operation failed.`,
+		Example: `  deputy batch < ops.jsonl
+  deputy leave list --raw | jq -c 'select(.Status == 0) | {op:"leave.approve",id:.Id}' | deputy batch --concurrency 4 --rate 5
+  deputy batch -f ops.jsonl --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+			ctx := cmd.Context()
+			ioCtx := iocontext.FromContext(ctx)
+
+			var in io.Reader = ioCtx.In
+			if file != "" && file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = f.Close() }()
+				in = f
+			}
+
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			runner := &batchRunner{client: client, infos: map[string]*api.ResourceInfo{}}
+
+			type job struct {
+				line int
+				raw  []byte
+			}
+			jobs := make(chan job)
+			var outMu sync.Mutex
+			var total, failed int
+			emit := func(r batchResult) {
+				data, _ := json.Marshal(r)
+				outMu.Lock()
+				defer outMu.Unlock()
+				total++
+				if !r.OK {
+					failed++
+				}
+				_, _ = fmt.Fprintln(ioCtx.Out, string(data))
+			}
+
+			var wg sync.WaitGroup
+			for w := 0; w < concurrency; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for j := range jobs {
+						emit(runBatchLine(ctx, runner, j.line, j.raw))
+					}
+				}()
+			}
+
+			scanner := bufio.NewScanner(in)
+			scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+			lineNo := 0
+			for scanner.Scan() {
+				lineNo++
+				raw := bytes.TrimSpace(scanner.Bytes())
+				if len(raw) == 0 {
+					continue
+				}
+				jobs <- job{line: lineNo, raw: append([]byte(nil), raw...)}
+			}
+			close(jobs)
+			wg.Wait()
+			if err := scanner.Err(); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(ioCtx.ErrOut, "batch: %d succeeded, %d failed\n", total-failed, failed)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d operations failed", failed, total)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Read operations from a file instead of stdin")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Operations to run at once")
+
+	return cmd
+}
+
+func runBatchLine(ctx context.Context, runner *batchRunner, line int, raw []byte) batchResult {
+	result := batchResult{Line: line}
+	op, err := parseBatchLine(raw)
+	if op != nil {
+		result.Op, result.ID, result.Ref = op.Op, op.ID, op.Ref
+	}
+	if err == nil {
+		var out interface{}
+		out, err = batchHandlers[op.Op].run(ctx, runner, op)
+		result.Result = out
+	}
+	if err != nil {
+		detail := errorDetail(err)
+		result.Error = &detail
+		result.Result = nil
+		return result
+	}
+	result.OK = true
+	return result
+}
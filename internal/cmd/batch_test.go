@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+)
+
+func runBatch(t *testing.T, server *httptest.Server, stdin string, args ...string) ([]batchResult, string, error) {
+	t.Helper()
+	out, errOut, err := runRoot(t, server, stdin, append([]string{"batch"}, args...)...)
+
+	var results []batchResult
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if line == "" {
+			continue
+		}
+		var r batchResult
+		require.NoError(t, json.Unmarshal([]byte(line), &r), line)
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Line < results[j].Line })
+	return results, errOut, err
+}
+
+func TestBatch_RunsOperationsAndReportsEachLine(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/resource/Leave/12":
+			_, _ = w.Write([]byte(`{"Id":12,"Status":1}`))
+		case "/api/v1/resource/Leave/13":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"message":"Not allowed"}}`))
+		case "/api/v1/resource/Employee/INFO":
+			_, _ = w.Write([]byte(`{"name":"Employee","fields":{"Id":"Integer","FirstName":"VarChar","Active":"Bit"}}`))
+		case "/api/v1/resource/Employee/5":
+			_, _ = w.Write([]byte(`{"Id":5,"FirstName":"Ann"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	input := strings.Join([]string{
+		`{"op":"leave.approve","id":12,"ref":"a"}`,
+		``,
+		`{"op":"leave.approve","id":13}`,
+		`{"op":"resource.update","name":"Employee","id":5,"data":{"FirstName":"Ann"}}`,
+		`{"op":"resource.update","name":"Employee","id":6,"data":{"FirstName":7}}`,
+		`{"op":"leave.approve"}`,
+		`{"op":"roster.explode","id":1}`,
+		`{"op":"leave.approve","id":1,"idd":2}`,
+	}, "\n")
+
+	results, stderr, err := runBatch(t, server, input, "--concurrency", "3")
+	require.EqualError(t, err, "5 of 7 operations failed")
+	assert.Contains(t, stderr, "batch: 2 succeeded, 5 failed")
+	require.Len(t, results, 7)
+
+	assert.Equal(t, 1, results[0].Line)
+	assert.True(t, results[0].OK)
+	assert.Equal(t, "leave.approve", results[0].Op)
+	assert.JSONEq(t, `"a"`, string(results[0].Ref))
+
+	assert.Equal(t, 3, results[1].Line)
+	assert.False(t, results[1].OK)
+	require.NotNil(t, results[1].Error)
+	assert.Equal(t, http.StatusForbidden, results[1].Error.Status)
+
+	assert.True(t, results[2].OK)
+	assert.Equal(t, map[string]interface{}{"Id": 5.0, "FirstName": "Ann"}, results[2].Result)
+
+	assert.Contains(t, results[3].Error.Message, "FirstName must be a string")
+	assert.Equal(t, "id is required", results[4].Error.Message)
+	assert.Contains(t, results[5].Error.Message, `unknown op "roster.explode"`)
+	assert.Contains(t, results[6].Error.Message, `unknown field "idd"`)
+
+	// INFO is fetched once for both Employee updates; the invalid one is
+	// never sent.
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(requests)
+	assert.Equal(t, []string{
+		"GET /api/v1/resource/Employee/INFO",
+		"POST /api/v1/resource/Employee/5",
+		"POST /api/v1/resource/Leave/12",
+		"POST /api/v1/resource/Leave/13",
+	}, requests)
+}
+
+func TestBatch_DryRunPrintsPlan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{In: strings.NewReader(`{"op":"employee.reactivate","id":5}`), Out: buf, ErrOut: &bytes.Buffer{}})
+
+	root := NewRootCmd()
+	root.SetArgs([]string{"batch", "--dry-run", "-o", "json"})
+	require.NoError(t, executeRoot(ctx, root))
+
+	var out struct {
+		Items []api.PlannedRequest `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+	require.Len(t, out.Items, 1)
+	assert.Contains(t, out.Items[0].URL, "/resource/Employee/5")
+}
+
+func TestBatch_RejectsBadFlags(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, _, err := runBatch(t, server, "", "--concurrency", "0")
+	require.EqualError(t, err, "--concurrency must be at least 1")
+}
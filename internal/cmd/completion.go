@@ -34,6 +34,10 @@ PowerShell:
   PS> deputy completion powershell | Out-String | Invoke-Expression
   # To load completions for every new session, run:
   PS> deputy completion powershell >> $PROFILE
+
+Employee, location, department and pay rule IDs, resource names and resource
+field names are completed from the API and cached for five minutes under the
+config directory.
 `,
 		DisableFlagsInUseLine: true,
 		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
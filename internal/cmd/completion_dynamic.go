@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/config"
+)
+
+// completionCacheTTL bounds how stale completion candidates can be. It is
+// short on purpose: completion should be fast, not authoritative.
+const completionCacheTTL = 5 * time.Minute
+
+type completionCacheEntry struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Items     []string  `json:"items"`
+}
+
+// completionFunc is the signature shared by ValidArgsFunction and
+// RegisterFlagCompletionFunc.
+type completionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// entityCompletions maps each entity kind to its completion. Flags and
+// positional args are matched to kinds by entityFlagKinds and entityArgKinds,
+// the same tables used for name-based resolution.
+var entityCompletions = map[entityKind]completionFunc{
+	entityEmployee:   completeEmployees,
+	entityLocation:   completeLocations,
+	entityDepartment: completeDepartments,
+	entityPayRule:    completePayRules,
+}
+
+// registerDynamicCompletions walks the command tree and attaches completion
+// functions by flag name and positional placeholder, so individual commands
+// don't need to wire completions themselves.
+func registerDynamicCompletions(root *cobra.Command) {
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for name, kind := range entityFlagKinds {
+			if c.Flags().Lookup(name) != nil {
+				_ = c.RegisterFlagCompletionFunc(name, entityCompletions[kind])
+			}
+		}
+		if c.ValidArgsFunction == nil && len(c.ValidArgs) == 0 {
+			if kind, ok := entityArgKind(c); ok {
+				c.ValidArgsFunction = onlyFirstArg(entityCompletions[kind])
+			} else if fields := strings.Fields(c.Use); len(fields) > 1 && fields[1] == "<ResourceName>" {
+				c.ValidArgsFunction = onlyFirstArg(completeResourceNames)
+			}
+		}
+		if c.Name() == "query" && c.Parent() != nil && c.Parent().Name() == "resource" {
+			_ = c.RegisterFlagCompletionFunc("filter", completeResourceFilter)
+			_ = c.RegisterFlagCompletionFunc("sort", completeResourceFields)
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+func onlyFirstArg(fn completionFunc) completionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return fn(cmd, args, toComplete)
+	}
+}
+
+func completeEmployees(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return cachedCompletions(cmd, "employees", toComplete, func(ctx context.Context, client *api.Client) ([]string, error) {
+		employees, err := client.Employees().List(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]string, 0, len(employees))
+		for _, e := range employees {
+			if e.Active {
+				items = append(items, completionItem(e.Id, e.DisplayName))
+			}
+		}
+		return items, nil
+	})
+}
+
+func completeLocations(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return cachedCompletions(cmd, "locations", toComplete, func(ctx context.Context, client *api.Client) ([]string, error) {
+		locations, err := client.Locations().List(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]string, 0, len(locations))
+		for _, l := range locations {
+			items = append(items, completionItem(l.Id, l.CompanyName))
+		}
+		return items, nil
+	})
+}
+
+func completeDepartments(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return cachedCompletions(cmd, "departments", toComplete, func(ctx context.Context, client *api.Client) ([]string, error) {
+		departments, err := client.Departments().List(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]string, 0, len(departments))
+		for _, d := range departments {
+			items = append(items, completionItem(d.Id, d.CompanyName))
+		}
+		return items, nil
+	})
+}
+
+func completePayRules(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return cachedCompletions(cmd, "payrules", toComplete, func(ctx context.Context, client *api.Client) ([]string, error) {
+		rules, err := client.Timesheets().ListPayRules(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]string, 0, len(rules))
+		for _, r := range rules {
+			items = append(items, completionItem(r.Id, fmt.Sprintf("%s (%.2f/hr)", r.PayTitle, r.HourlyRate)))
+		}
+		return items, nil
+	})
+}
+
+func completeResourceNames(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return filterCompletions(api.KnownResources(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeResourceFields completes field names of the resource named by the
+// first positional argument, using the cached INFO schema.
+func completeResourceFields(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	resource := args[0]
+	return cachedCompletions(cmd, "info-"+resource, toComplete, func(ctx context.Context, client *api.Client) ([]string, error) {
+		info, err := client.Resource(resource).Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]string, 0, len(info.Fields))
+		for name, typ := range info.Fields {
+			items = append(items, fmt.Sprintf("%s\t%v", name, typ))
+		}
+		sort.Strings(items)
+		return items, nil
+	})
+}
+
+// completeResourceFilter completes the field part of a --filter expression.
+// No space is appended so the operator and value can be typed directly.
+func completeResourceFilter(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	items, directive := completeResourceFields(cmd, args, toComplete)
+	return items, directive | cobra.ShellCompDirectiveNoSpace
+}
+
+func completionItem(id int, description string) string {
+	if description == "" {
+		return strconv.Itoa(id)
+	}
+	return strconv.Itoa(id) + "\t" + description
+}
+
+// filterCompletions keeps items whose value starts with toComplete,
+// ignoring case.
+func filterCompletions(items []string, toComplete string) []string {
+	if toComplete == "" {
+		return items
+	}
+	prefix := strings.ToLower(toComplete)
+	var out []string
+	for _, item := range items {
+		if strings.HasPrefix(strings.ToLower(item), prefix) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// cachedCompletions returns completion items for key, fetching them through
+// the API when the cache is missing or older than completionCacheTTL. Errors
+// are swallowed: a failed lookup simply offers no candidates.
+func cachedCompletions(cmd *cobra.Command, key, toComplete string, fetch func(context.Context, *api.Client) ([]string, error)) ([]string, cobra.ShellCompDirective) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	config.LoadDotenv()
+
+	client, err := getClientFromContext(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	path := completionCachePath(client.BaseURL(), key)
+	if items, ok := readCompletionCache(path); ok {
+		return filterCompletions(items, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	items, err := fetch(ctx, client)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	writeCompletionCache(path, items)
+	return filterCompletions(items, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionCachePath scopes cache files by install so switching accounts
+// never offers another install's IDs.
+func completionCachePath(baseURL, key string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(baseURL))
+	name := strings.NewReplacer("/", "_", string(os.PathSeparator), "_").Replace(key) + ".json"
+	return filepath.Join(config.CacheDir(), "completion", fmt.Sprintf("%08x", h.Sum32()), name)
+}
+
+func readCompletionCache(path string) ([]string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry completionCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if time.Since(entry.FetchedAt) > completionCacheTTL {
+		return nil, false
+	}
+	return entry.Items, true
+}
+
+func writeCompletionCache(path string, items []string) {
+	data, err := json.Marshal(completionCacheEntry{FetchedAt: time.Now(), Items: items})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0o600)
+}
@@ -3,11 +3,17 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/salmonumbrella/deputy-cli/internal/api"
 	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
 )
 
@@ -101,3 +107,71 @@ func TestCompletionCommand_ViaRootCmd(t *testing.T) {
 	}
 	assert.True(t, found, "completion command should be registered")
 }
+
+func runCompletion(t *testing.T, ctx context.Context, args ...string) []string {
+	t.Helper()
+	root := NewRootCmd()
+	buf := &bytes.Buffer{}
+	root.SetOut(buf)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"__complete"}, args...))
+	require.NoError(t, root.ExecuteContext(ctx))
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.HasPrefix(line, ":") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func TestDynamicCompletion_EmployeesCached(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/supervise/employee", r.URL.Path)
+		hits.Add(1)
+		_ = json.NewEncoder(w).Encode([]api.Employee{
+			{Id: 12, DisplayName: "Jane Doe", Active: true},
+			{Id: 34, DisplayName: "John Roe", Active: true},
+			{Id: 56, DisplayName: "Former Staff", Active: false},
+		})
+	}))
+	defer server.Close()
+
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+
+	got := runCompletion(t, ctx, "employees", "get", "")
+	assert.Equal(t, []string{"12\tJane Doe", "34\tJohn Roe"}, got)
+
+	got = runCompletion(t, ctx, "timesheets", "list", "--employee", "3")
+	assert.Equal(t, []string{"34\tJohn Roe"}, got)
+	assert.Equal(t, int32(1), hits.Load(), "second completion should come from cache")
+}
+
+func TestDynamicCompletion_ResourceNamesAndFields(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/Roster/INFO", r.URL.Path)
+		_ = json.NewEncoder(w).Encode(api.ResourceInfo{Name: "Roster", Fields: map[string]interface{}{"Employee": "Integer", "Date": "Date"}})
+	}))
+	defer server.Close()
+
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+
+	got := runCompletion(t, ctx, "resource", "info", "Ros")
+	assert.Equal(t, []string{"Roster"}, got)
+
+	got = runCompletion(t, ctx, "resource", "query", "Roster", "--sort", "")
+	assert.Equal(t, []string{"Date\tDate", "Employee\tInteger"}, got)
+}
+
+func TestDynamicCompletion_NoCredentials(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{err: assert.AnError})
+
+	assert.Empty(t, runCompletion(t, ctx, "employees", "get", ""))
+}
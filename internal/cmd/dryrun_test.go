@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+)
+
+func TestDryRun_TerminatePrintsPlan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request in dry-run: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+		ctx = iocontext.WithIO(ctx, &iocontext.IO{In: &bytes.Buffer{}, Out: buf, ErrOut: &bytes.Buffer{}})
+
+		root := NewRootCmd()
+		root.SetArgs([]string{"employees", "terminate", "12", "--date", "2024-03-01", "--dry-run", "-o", "json"})
+		require.NoError(t, executeRoot(ctx, root))
+
+		var out struct {
+			Items []api.PlannedRequest `json:"items"`
+			Meta  map[string]any       `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+		require.Len(t, out.Items, 1)
+		assert.Equal(t, "POST", out.Items[0].Method)
+		assert.Contains(t, out.Items[0].URL, "/supervise/employee/12/terminate")
+		assert.Equal(t, true, out.Meta["dryRun"])
+	})
+
+	t.Run("text skips confirmation", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+		ctx = iocontext.WithIO(ctx, &iocontext.IO{In: &bytes.Buffer{}, Out: buf, ErrOut: &bytes.Buffer{}})
+
+		root := NewRootCmd()
+		root.SetArgs([]string{"employees", "terminate", "12", "--date", "2024-03-01", "--dry-run", "-o", "text"})
+		require.NoError(t, executeRoot(ctx, root))
+
+		assert.Contains(t, buf.String(), "Dry run: 1 request(s) would be sent")
+		assert.Contains(t, buf.String(), "strTerminationDate")
+		assert.NotContains(t, buf.String(), "terminated")
+	})
+}
+
+func TestDryRun_PrintsPlanWhenCommandFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request in dry-run: %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":{"message":"Not allowed"}}`))
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	input := `{"op":"employee.reactivate","id":5}` + "\n" + `{"op":"resource.get","name":"Task","id":3}`
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{In: bytes.NewBufferString(input), Out: buf, ErrOut: &bytes.Buffer{}})
+
+	root := NewRootCmd()
+	root.SetArgs([]string{"batch", "--concurrency", "1", "--dry-run", "-o", "text"})
+	require.EqualError(t, executeRoot(ctx, root), "1 of 2 operations failed")
+
+	assert.Contains(t, buf.String(), "Dry run: 1 request(s) would be sent")
+	assert.Contains(t, buf.String(), "/resource/Employee/5")
+}
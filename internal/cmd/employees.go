@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -30,6 +31,12 @@ func newEmployeesCmd() *cobra.Command {
 	cmd.AddCommand(newEmployeesReactivateCmd())
 	cmd.AddCommand(newEmployeesDeleteCmd())
 	cmd.AddCommand(newEmployeesAddUnavailabilityCmd())
+	cmd.AddCommand(newEmployeesOnboardCmd())
+	cmd.AddCommand(newEmployeesExportCmd())
+	cmd.AddCommand(newEmployeesImportCmd())
+	cmd.AddCommand(newEmployeesOffboardCmd())
+	cmd.AddCommand(newEmployeesHistoryCmd())
+	cmd.AddCommand(newEmployeesChangesCmd())
 
 	return cmd
 }
@@ -108,11 +115,12 @@ func newEmployeesGetCmd() *cobra.Command {
 			if err != nil {
 				return err
 			}
+			profile := loadEmployeeProfile(cmd.Context(), client, employee)
 
 			format := outfmt.GetFormat(cmd.Context())
 			if format == "json" {
 				f := outfmt.New(cmd.Context())
-				return f.Output(employee)
+				return f.Output(profile)
 			}
 
 			io := iocontext.FromContext(cmd.Context())
@@ -124,6 +132,15 @@ func newEmployeesGetCmd() *cobra.Command {
 			_, _ = fmt.Fprintf(io.Out, "Mobile:     %s\n", employee.Mobile)
 			_, _ = fmt.Fprintf(io.Out, "Active:     %t\n", employee.Active)
 			_, _ = fmt.Fprintf(io.Out, "Company:    %d\n", employee.Company)
+			_, _ = fmt.Fprintf(io.Out, "Role:       %d\n", employee.Role)
+			if employee.PayrollID != "" {
+				_, _ = fmt.Fprintf(io.Out, "Payroll ID: %s\n", employee.PayrollID)
+			}
+			if employee.DateOfBirth != "" {
+				_, _ = fmt.Fprintf(io.Out, "Born:       %s\n", datePart(employee.DateOfBirth))
+			}
+			printJoinedRecord(io.Out, "Contact", profile.ContactObject)
+			printJoinedRecord(io.Out, "Address", profile.MainAddressObject)
 			return nil
 		},
 	}
@@ -193,42 +210,100 @@ func newEmployeesAddCmd() *cobra.Command {
 }
 
 func newEmployeesUpdateCmd() *cobra.Command {
-	var firstName, lastName, email, mobile string
+	var firstName, lastName, email, mobile, payrollID string
+	var sets, customs []string
 
 	cmd := &cobra.Command{
 		Use:   "update <id>",
 		Short: "Update an employee",
-		Args:  RequireArg("id"),
+		Long: `Update an employee.
+
+Any writable field listed by 'deputy resource info Employee' can be set with
+--set Field=value (e.g. Role, DateOfBirth, Position, EmergencyAddress); names
+and value types are checked before anything is sent. Custom fields are set
+with --custom key=value, where key is the field's API name or name.`,
+		Example: `  deputy employees update 42 --mobile 0400123456
+  deputy employees update 42 --payroll-id P-1042
+  deputy employees update 42 --set Role=50 --set DateOfBirth=1990-04-01
+  deputy employees update 42 --custom shirt_size=M --custom "Locker number=12"`,
+		Args: RequireArg("id"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
 				return fmt.Errorf("invalid employee ID: %s", args[0])
 			}
 
-			client, err := getClientFromContext(cmd.Context())
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
 			if err != nil {
 				return err
 			}
 
+			// Check everything before the first write.
+			var payload, custom map[string]interface{}
+			if len(sets) > 0 {
+				info, err := client.Resource("Employee").Info(ctx)
+				if err != nil {
+					return err
+				}
+				payload = make(map[string]interface{}, len(sets))
+				if err := applyResourceSets(info, payload, sets); err != nil {
+					return err
+				}
+				if err := validateResourcePayload(info, payload); err != nil {
+					return err
+				}
+			}
+			if len(customs) > 0 {
+				if custom, err = employeeCustomFields(ctx, client, customs); err != nil {
+					return err
+				}
+			}
+
 			input := &api.UpdateEmployeeInput{
 				FirstName: firstName,
 				LastName:  lastName,
 				Email:     email,
 				Mobile:    mobile,
+				PayrollID: payrollID,
 			}
-
-			employee, err := client.Employees().Update(cmd.Context(), id, input)
-			if err != nil {
-				return err
+			var employee *api.Employee
+			named := firstName != "" || lastName != "" || email != "" || mobile != "" || payrollID != ""
+			if named || (payload == nil && custom == nil) {
+				if employee, err = client.Employees().Update(ctx, id, input); err != nil {
+					return err
+				}
+			}
+			if custom != nil {
+				created, err := setEmployeeCustomFields(ctx, client, id, custom)
+				if err != nil {
+					return err
+				}
+				if created != 0 {
+					if payload == nil {
+						payload = map[string]interface{}{}
+					}
+					payload["CustomFieldData"] = created
+				}
+			}
+			if payload != nil {
+				if _, err := client.Resource("Employee").Update(ctx, id, payload); err != nil {
+					return err
+				}
+			}
+			if payload != nil || custom != nil {
+				if employee, err = client.Employees().Get(ctx, id); err != nil {
+					return err
+				}
 			}
 
-			format := outfmt.GetFormat(cmd.Context())
+			format := outfmt.GetFormat(ctx)
 			if format == "json" {
-				f := outfmt.New(cmd.Context())
+				f := outfmt.New(ctx)
 				return f.Output(employee)
 			}
 
-			io := iocontext.FromContext(cmd.Context())
+			io := iocontext.FromContext(ctx)
 			_, _ = fmt.Fprintf(io.Out, "Updated employee %d: %s\n", employee.Id, employee.DisplayName)
 			return nil
 		},
@@ -238,6 +313,9 @@ func newEmployeesUpdateCmd() *cobra.Command {
 	cmd.Flags().StringVar(&lastName, "last-name", "", "Last name")
 	cmd.Flags().StringVar(&email, "email", "", "Email address")
 	cmd.Flags().StringVar(&mobile, "mobile", "", "Mobile phone")
+	cmd.Flags().StringVar(&payrollID, "payroll-id", "", "Payroll ID")
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "Employee field as Field=value (can be repeated)")
+	cmd.Flags().StringArrayVar(&customs, "custom", nil, "Custom field as key=value (can be repeated)")
 
 	return cmd
 }
@@ -272,9 +350,16 @@ func newEmployeesTerminateCmd() *cobra.Command {
 				return err
 			}
 
+			before, snapped := snapshotForUndo(cmd.Context(), fmt.Sprintf("employee %d", id), func() (*api.Employee, error) {
+				return client.Employees().Get(cmd.Context(), id)
+			})
+
 			if err := client.Employees().Terminate(cmd.Context(), id, date); err != nil {
 				return err
 			}
+			if snapped && before.Active {
+				recordUndo(cmd.Context(), client, undoEmployeeTerminate, id, fmt.Sprintf("terminate employee %d as of %s", id, date), employeeStatusSnapshot{Active: true})
+			}
 
 			io := iocontext.FromContext(cmd.Context())
 			_, _ = fmt.Fprintf(io.Out, "Employee %d terminated as of %s\n", id, date)
@@ -336,9 +421,18 @@ func newEmployeesAssignLocationCmd() *cobra.Command {
 				return err
 			}
 
+			wasAssigned, err := employeeAtLocation(cmd.Context(), client, employeeID, locationID)
+			if err != nil {
+				return err
+			}
 			if err := client.Employees().AssignLocation(cmd.Context(), employeeID, locationID); err != nil {
 				return err
 			}
+			if !wasAssigned {
+				recordUndo(cmd.Context(), client, undoEmployeeAssignLocation, employeeID,
+					fmt.Sprintf("assign employee %d to location %d", employeeID, locationID),
+					employeeLocationSnapshot{Location: locationID, Assigned: false})
+			}
 
 			io := iocontext.FromContext(cmd.Context())
 			_, _ = fmt.Fprintf(io.Out, "Employee %d assigned to location %d\n", employeeID, locationID)
@@ -372,9 +466,18 @@ func newEmployeesRemoveLocationCmd() *cobra.Command {
 				return err
 			}
 
+			wasAssigned, err := employeeAtLocation(cmd.Context(), client, employeeID, locationID)
+			if err != nil {
+				return err
+			}
 			if err := client.Employees().RemoveLocation(cmd.Context(), employeeID, locationID); err != nil {
 				return err
 			}
+			if wasAssigned {
+				recordUndo(cmd.Context(), client, undoEmployeeRemoveLocation, employeeID,
+					fmt.Sprintf("remove employee %d from location %d", employeeID, locationID),
+					employeeLocationSnapshot{Location: locationID, Assigned: true})
+			}
 
 			io := iocontext.FromContext(cmd.Context())
 			_, _ = fmt.Fprintf(io.Out, "Employee %d removed from location %d\n", employeeID, locationID)
@@ -387,6 +490,21 @@ func newEmployeesRemoveLocationCmd() *cobra.Command {
 	return cmd
 }
 
+// employeeAtLocation reports whether the employee is currently assigned to
+// the location, so undo only records location changes that actually happen.
+func employeeAtLocation(ctx context.Context, client *api.Client, employeeID, locationID int) (bool, error) {
+	workplaces, err := client.Employees().ListLocations(ctx, employeeID)
+	if err != nil {
+		return false, err
+	}
+	for _, w := range workplaces {
+		if w.Company == locationID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func newEmployeesReactivateCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "reactivate <id>",
@@ -403,9 +521,17 @@ func newEmployeesReactivateCmd() *cobra.Command {
 				return err
 			}
 
+			before, snapped := snapshotForUndo(cmd.Context(), fmt.Sprintf("employee %d", id), func() (*api.Employee, error) {
+				return client.Employees().Get(cmd.Context(), id)
+			})
+
 			if err := client.Employees().Reactivate(cmd.Context(), id); err != nil {
 				return err
 			}
+			if snapped && !before.Active {
+				recordUndo(cmd.Context(), client, undoEmployeeReactivate, id, fmt.Sprintf("reactivate employee %d", id),
+					employeeStatusSnapshot{Active: false, TerminationDate: before.TerminationDate})
+			}
 
 			io := iocontext.FromContext(cmd.Context())
 			_, _ = fmt.Fprintf(io.Out, "Employee %d reactivated\n", id)
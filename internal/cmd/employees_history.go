@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// EmployeeChange is one field that differs between two consecutive
+// EmployeeHistory snapshots of an employee.
+type EmployeeChange struct {
+	Date         string      `json:"date"`
+	Employee     int         `json:"employee"`
+	EmployeeName string      `json:"employeeName,omitempty"`
+	Field        string      `json:"field"`
+	Before       interface{} `json:"before"`
+	After        interface{} `json:"after"`
+	// History is the ID of the snapshot that recorded the new value.
+	History int `json:"history"`
+}
+
+// historyIgnoredFields are snapshot fields that change with every record and
+// say nothing about the employee.
+var historyIgnoredFields = map[string]bool{
+	"Id":          true,
+	"Employee":    true,
+	"Date":        true,
+	"Creator":     true,
+	"Created":     true,
+	"Modified":    true,
+	"_DPMetaData": true,
+}
+
+// historySnapshotDate returns when a snapshot took effect: its Date, or when
+// it was created if it has none.
+func historySnapshotDate(record map[string]interface{}) string {
+	if date, _ := record["Date"].(string); date != "" {
+		return datePart(date)
+	}
+	created, _ := record["Created"].(string)
+	return datePart(created)
+}
+
+// fetchEmployeeHistory returns every EmployeeHistory snapshot for an employee.
+func fetchEmployeeHistory(ctx context.Context, client *api.Client, employeeID int) ([]map[string]interface{}, error) {
+	return client.Resource("EmployeeHistory").QueryAll(ctx, &api.QueryInput{Search: map[string]interface{}{
+		"s1": map[string]interface{}{"field": "Employee", "type": "eq", "data": employeeID},
+	}})
+}
+
+// diffEmployeeHistory orders an employee's snapshots by date and reports
+// each field that differs from the snapshot before it.
+func diffEmployeeHistory(employeeID int, snapshots []map[string]interface{}) []EmployeeChange {
+	sorted := append([]map[string]interface{}(nil), snapshots...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		di, dj := historySnapshotDate(sorted[i]), historySnapshotDate(sorted[j])
+		if di != dj {
+			return di < dj
+		}
+		return recordID(sorted[i]) < recordID(sorted[j])
+	})
+
+	var changes []EmployeeChange
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		fields := make(map[string]bool, len(cur))
+		for k := range prev {
+			fields[k] = true
+		}
+		for k := range cur {
+			fields[k] = true
+		}
+		names := make([]string, 0, len(fields))
+		for k := range fields {
+			if !historyIgnoredFields[k] {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+		for _, field := range names {
+			if formatHistoryValue(prev[field]) == formatHistoryValue(cur[field]) {
+				continue
+			}
+			changes = append(changes, EmployeeChange{
+				Date:     historySnapshotDate(cur),
+				Employee: employeeID,
+				Field:    field,
+				Before:   prev[field],
+				After:    cur[field],
+				History:  recordID(cur),
+			})
+		}
+	}
+	return changes
+}
+
+func recordID(record map[string]interface{}) int {
+	id, _ := record["Id"].(float64)
+	return int(id)
+}
+
+// formatHistoryValue renders a snapshot value for display and comparison;
+// missing and empty values both show as "-".
+func formatHistoryValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// filterEmployeeChanges keeps changes dated within [from, to] (either may be
+// empty) to one of the given fields (any field if none are given).
+func filterEmployeeChanges(changes []EmployeeChange, from, to string, fields []string) []EmployeeChange {
+	want := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		want[strings.ToLower(strings.TrimSpace(f))] = true
+	}
+	out := []EmployeeChange{}
+	for _, c := range changes {
+		switch {
+		case from != "" && c.Date < from:
+			continue
+		case to != "" && c.Date > to:
+			continue
+		case len(want) > 0 && !want[strings.ToLower(c.Field)]:
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func printEmployeeChanges(ctx context.Context, changes []EmployeeChange, withEmployee bool) {
+	f := outfmt.New(ctx)
+	if withEmployee {
+		f.StartTable([]string{"DATE", "EMPLOYEE", "FIELD", "BEFORE", "AFTER"})
+	} else {
+		f.StartTable([]string{"DATE", "FIELD", "BEFORE", "AFTER"})
+	}
+	for _, c := range changes {
+		before, after := formatHistoryValue(c.Before), formatHistoryValue(c.After)
+		if withEmployee {
+			f.Row(c.Date, employeeLabel(c.Employee, c.EmployeeName), c.Field, before, after)
+		} else {
+			f.Row(c.Date, c.Field, before, after)
+		}
+	}
+	f.EndTable()
+}
+
+func newEmployeesHistoryCmd() *cobra.Command {
+	var from, to string
+	var fields []string
+
+	cmd := &cobra.Command{
+		Use:   "history <id>",
+		Short: "Show an employee's change history",
+		Long: `Show a timeline of changes to an employee (role, pay, location, status and
+every other field Deputy records), built by comparing consecutive
+EmployeeHistory snapshots. Each row shows a field's value before and after
+the change.`,
+		Example: `  deputy employees history 42
+  deputy employees history 42 --from 2024-01-01 --to 2024-06-30
+  deputy employees history email:jane@example.com --field Role --field Company`,
+		Args: RequireArg("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid employee ID: %s", args[0])
+			}
+			for _, d := range []string{from, to} {
+				if d == "" {
+					continue
+				}
+				if err := validateDateFormat(d); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			snapshots, err := fetchEmployeeHistory(ctx, client, id)
+			if err != nil {
+				return err
+			}
+			changes := filterEmployeeChanges(diffEmployeeHistory(id, snapshots), from, to, fields)
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputList(changes)
+			}
+			printEmployeeChanges(ctx, changes, false)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Only changes on or after this date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&to, "to", "", "Only changes on or before this date (YYYY-MM-DD)")
+	cmd.Flags().StringSliceVar(&fields, "field", nil, "Only changes to these fields (repeatable)")
+
+	return cmd
+}
+
+func newEmployeesChangesCmd() *cobra.Command {
+	var since string
+	var fields []string
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "changes",
+		Short: "Show changes to all employees over a recent period",
+		Long: `Show every employee change recorded in EmployeeHistory since a date or a
+duration ago. Employees with a snapshot in the period are compared against
+their earlier history so each change shows its before and after values.`,
+		Example: `  deputy employees changes --since 7d
+  deputy employees changes --since 2024-07-01 --field Role -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start, err := parseSinceFlag(since, "--since")
+			if err != nil {
+				return err
+			}
+			sinceDate := start.Format("2006-01-02")
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			search, err := parseFilters([]string{"Date>=" + sinceDate})
+			if err != nil {
+				return err
+			}
+			recent, err := client.Resource("EmployeeHistory").QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+			seen := map[int]bool{}
+			var employeeIDs []int
+			for _, r := range recent {
+				id, _ := r["Employee"].(float64)
+				if id > 0 && !seen[int(id)] {
+					seen[int(id)] = true
+					employeeIDs = append(employeeIDs, int(id))
+				}
+			}
+			sort.Ints(employeeIDs)
+
+			// The snapshot before the period is needed for each employee's
+			// first change, so their full history is read.
+			perEmployee := make([][]EmployeeChange, len(employeeIDs))
+			var mu sync.Mutex
+			var firstErr error
+			forEachConcurrent(len(employeeIDs), concurrency, func(i int) {
+				snapshots, err := fetchEmployeeHistory(ctx, client, employeeIDs[i])
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("employee %d: %w", employeeIDs[i], err)
+					}
+					mu.Unlock()
+					return
+				}
+				perEmployee[i] = diffEmployeeHistory(employeeIDs[i], snapshots)
+			})
+			if firstErr != nil {
+				return firstErr
+			}
+
+			names, err := employeeDisplayNames(ctx, client)
+			if err != nil {
+				return err
+			}
+			var all []EmployeeChange
+			for _, changes := range perEmployee {
+				all = append(all, changes...)
+			}
+			changes := filterEmployeeChanges(all, sinceDate, "", fields)
+			for i := range changes {
+				changes[i].EmployeeName = names[changes[i].Employee]
+			}
+			sort.SliceStable(changes, func(i, j int) bool {
+				return changes[i].Date < changes[j].Date
+			})
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputWithMeta(changes, map[string]interface{}{
+					"count":     len(changes),
+					"since":     sinceDate,
+					"employees": len(employeeIDs),
+				})
+			}
+			printEmployeeChanges(ctx, changes, true)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "Changes since a date (YYYY-MM-DD) or a duration ago (e.g. 24h, 7d)")
+	cmd.Flags().StringSliceVar(&fields, "field", nil, "Only changes to these fields (repeatable)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Employees whose history is read at once")
+
+	return cmd
+}
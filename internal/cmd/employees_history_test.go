@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Snapshots are deliberately out of order; Id 3 has no Date and falls back
+// to Created.
+const employeeHistory42 = `[
+  {"Id":2,"Employee":42,"Date":"2024-03-01T00:00:00+11:00","Role":60,"Company":1,"Active":true,"PayRate":28.5,"Modified":"2024-03-01"},
+  {"Id":1,"Employee":42,"Date":"2024-01-01","Role":50,"Company":1,"Active":true,"PayRate":27,"Modified":"2024-01-01"},
+  {"Id":3,"Employee":42,"Created":"2024-06-15T09:00:00+10:00","Role":60,"Company":3,"Active":false,"PayRate":28.5,"Modified":"2024-06-15"}
+]`
+
+func TestDiffEmployeeHistory(t *testing.T) {
+	var snapshots []map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(employeeHistory42), &snapshots))
+
+	changes := diffEmployeeHistory(42, snapshots)
+	require.Len(t, changes, 4)
+	assert.Equal(t, EmployeeChange{Date: "2024-03-01", Employee: 42, Field: "PayRate", Before: 27.0, After: 28.5, History: 2}, changes[0])
+	assert.Equal(t, "Role", changes[1].Field)
+	assert.Equal(t, "2024-06-15", changes[2].Date)
+	assert.Equal(t, "Active", changes[2].Field)
+	assert.Equal(t, "Company", changes[3].Field)
+
+	filtered := filterEmployeeChanges(changes, "2024-02-01", "2024-03-31", []string{"role"})
+	require.Len(t, filtered, 1)
+	assert.Equal(t, 60.0, filtered[0].After)
+}
+
+func TestEmployeesHistory_Text(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/EmployeeHistory/QUERY", r.URL.Path)
+		var body struct {
+			Search map[string]map[string]interface{} `json:"search"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, 42.0, body.Search["s1"]["data"])
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(employeeHistory42))
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "employees", "history", "42", "--from", "2024-06-01", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "DATE")
+	assert.Regexp(t, `2024-06-15\s+Active\s+true\s+false`, out)
+	assert.Regexp(t, `2024-06-15\s+Company\s+1\s+3`, out)
+	assert.NotContains(t, out, "PayRate")
+
+	_, _, err = runRoot(t, server, "", "employees", "history", "42", "--to", "30/06/2024")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid date format")
+}
+
+func TestEmployeesChanges_ComparesAgainstEarlierHistory(t *testing.T) {
+	var queries []map[string]map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/supervise/employee":
+			_, _ = w.Write([]byte(`[{"Id":42,"DisplayName":"Jane Doe"}]`))
+			return
+		case "/api/v1/resource/EmployeeHistory/QUERY":
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			return
+		}
+		var body struct {
+			Search map[string]map[string]interface{} `json:"search"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, body.Search)
+		if body.Search["f1"] != nil {
+			// The period query only returns the latest snapshot.
+			_, _ = w.Write([]byte(`[{"Id":3,"Employee":42}]`))
+			return
+		}
+		_, _ = w.Write([]byte(employeeHistory42))
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "employees", "changes", "--since", "2024-06-01", "--field", "Company", "-o", "json")
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	assert.Equal(t, map[string]interface{}{"field": "Date", "type": "ge", "data": "2024-06-01"}, queries[0]["f1"])
+
+	var parsed struct {
+		Items []EmployeeChange       `json:"items"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 1)
+	assert.Equal(t, "Jane Doe", parsed.Items[0].EmployeeName)
+	assert.Equal(t, 1.0, parsed.Items[0].Before)
+	assert.Equal(t, 3.0, parsed.Items[0].After)
+	assert.Equal(t, "2024-06-01", parsed.Meta["since"])
+}
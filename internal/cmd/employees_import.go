@@ -0,0 +1,476 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// employeeCSVColumns are written by `employees export --format csv`. Import
+// reads the same header, ignoring the columns it cannot set.
+var employeeCSVColumns = []string{"id", "first_name", "last_name", "display_name", "email", "mobile", "payroll_id", "start_date", "company", "role", "active"}
+
+// Employee import row statuses.
+const (
+	employeeImportCreated   = "created"
+	employeeImportUpdated   = "updated"
+	employeeImportUnchanged = "unchanged"
+	employeeImportFailed    = "failed"
+)
+
+// Import match keys.
+const (
+	matchOnEmail     = "email"
+	matchOnPayrollID = "payroll-id"
+)
+
+// employeeImportRow is one CSV row and what import did with it. Empty cells
+// leave the employee's current value alone.
+type employeeImportRow struct {
+	Line       int      `json:"line"`
+	FirstName  string   `json:"firstName,omitempty"`
+	LastName   string   `json:"lastName,omitempty"`
+	Email      string   `json:"email,omitempty"`
+	Mobile     string   `json:"mobile,omitempty"`
+	PayrollID  string   `json:"payrollId,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	Company    string   `json:"company,omitempty"`
+	Role       int      `json:"role,omitempty"`
+	EmployeeID int      `json:"employeeId,omitempty"`
+	Status     string   `json:"status"`
+	Changes    []string `json:"changes,omitempty"`
+	Error      string   `json:"error,omitempty"`
+
+	current   *api.Employee
+	companyID int
+}
+
+func (r *employeeImportRow) fail(err error) {
+	r.Status = employeeImportFailed
+	r.Error = err.Error()
+}
+
+func (r *employeeImportRow) key(matchOn string) string {
+	if matchOn == matchOnPayrollID {
+		return r.PayrollID
+	}
+	return strings.ToLower(r.Email)
+}
+
+func employeeKey(e api.Employee, matchOn string) string {
+	if matchOn == matchOnPayrollID {
+		return e.PayrollID
+	}
+	return strings.ToLower(e.Email)
+}
+
+// readEmployeeCSV parses an import file. Structural problems (missing or
+// unknown columns) fail the whole file; bad values fail just their row.
+func readEmployeeCSV(r io.Reader, matchOn string) ([]employeeImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, err
+	}
+	known := make(map[string]bool, len(employeeCSVColumns))
+	for _, c := range employeeCSVColumns {
+		known[c] = true
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown CSV column %q (expected: %s)", name, strings.Join(employeeCSVColumns, ", "))
+		}
+		index[name] = i
+	}
+	matchColumn := strings.ReplaceAll(matchOn, "-", "_")
+	if _, ok := index[matchColumn]; !ok {
+		return nil, fmt.Errorf("CSV has no %q column to match on", matchColumn)
+	}
+
+	var rows []employeeImportRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		cell := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row := employeeImportRow{
+			Line:      line,
+			FirstName: cell("first_name"),
+			LastName:  cell("last_name"),
+			Email:     cell("email"),
+			Mobile:    cell("mobile"),
+			PayrollID: cell("payroll_id"),
+			StartDate: cell("start_date"),
+			Company:   cell("company"),
+		}
+		if role := cell("role"); role != "" {
+			if row.Role, err = strconv.Atoi(role); err != nil {
+				row.fail(fmt.Errorf("invalid role %q", role))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// validateEmployeeRows checks every row before anything is sent and links
+// rows to the employees they match.
+func validateEmployeeRows(rows []employeeImportRow, live []api.Employee, matchOn string) {
+	byKey := make(map[string][]api.Employee, len(live))
+	for _, e := range live {
+		if k := employeeKey(e, matchOn); k != "" {
+			byKey[k] = append(byKey[k], e)
+		}
+	}
+	firstLine := map[string]int{}
+
+	for i := range rows {
+		row := &rows[i]
+		if row.Status == employeeImportFailed {
+			continue
+		}
+		key := row.key(matchOn)
+		if key == "" {
+			row.fail(fmt.Errorf("%s is required to match on", strings.ReplaceAll(matchOn, "-", "_")))
+			continue
+		}
+		if prev, ok := firstLine[key]; ok {
+			row.fail(fmt.Errorf("duplicate of line %d", prev))
+			continue
+		}
+		firstLine[key] = row.Line
+		if row.Email != "" && !strings.Contains(row.Email, "@") {
+			row.fail(fmt.Errorf("invalid email %q", row.Email))
+			continue
+		}
+		if row.StartDate != "" {
+			if err := validateDateFormat(row.StartDate); err != nil {
+				row.fail(err)
+				continue
+			}
+		}
+		if row.Company != "" {
+			if _, err := strconv.Atoi(row.Company); err != nil && !isEntityRef(row.Company) {
+				row.fail(fmt.Errorf("company %q must be an ID or a code:/name: reference", row.Company))
+				continue
+			}
+		}
+
+		switch matches := byKey[key]; len(matches) {
+		case 0:
+			if row.FirstName == "" || row.LastName == "" || row.Company == "" {
+				row.fail(errors.New("first_name, last_name and company are required for new employees"))
+			}
+		case 1:
+			row.current = &matches[0]
+			row.EmployeeID = matches[0].Id
+		default:
+			ids := make([]string, 0, len(matches))
+			for _, m := range matches {
+				ids = append(ids, strconv.Itoa(m.Id))
+			}
+			row.fail(fmt.Errorf("matches more than one employee (%s)", strings.Join(ids, ", ")))
+		}
+	}
+}
+
+// employeeUpdate returns the changes a row makes to its employee; fields
+// that cannot be updated (start date, company, role) are ignored.
+func employeeUpdate(row *employeeImportRow) (*api.UpdateEmployeeInput, []string) {
+	input := &api.UpdateEmployeeInput{}
+	var changes []string
+	set := func(field, want, have string, dst *string) {
+		if want != "" && want != have {
+			*dst = want
+			changes = append(changes, fmt.Sprintf("%s: %q -> %q", field, have, want))
+		}
+	}
+	e := row.current
+	set("first_name", row.FirstName, e.FirstName, &input.FirstName)
+	set("last_name", row.LastName, e.LastName, &input.LastName)
+	if !strings.EqualFold(row.Email, e.Email) {
+		set("email", row.Email, e.Email, &input.Email)
+	}
+	set("mobile", row.Mobile, e.Mobile, &input.Mobile)
+	set("payroll_id", row.PayrollID, e.PayrollID, &input.PayrollID)
+	return input, changes
+}
+
+func newEmployeesExportCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export employees",
+		Long: `Export all employees. The CSV columns are the ones 'deputy employees import'
+reads, so an export can be edited and imported back.`,
+		Example: `  deputy employees export --format csv > employees.csv
+  deputy employees export -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			format, err := reportFormat(ctx, format)
+			if err != nil {
+				return err
+			}
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			employees, err := client.Employees().List(ctx, nil)
+			if err != nil {
+				return err
+			}
+			sort.Slice(employees, func(i, j int) bool { return employees[i].Id < employees[j].Id })
+
+			switch format {
+			case "json":
+				return outfmt.New(ctx).OutputList(employees)
+			case "csv":
+				rows := make([][]string, 0, len(employees))
+				for _, e := range employees {
+					rows = append(rows, []string{
+						strconv.Itoa(e.Id),
+						e.FirstName,
+						e.LastName,
+						e.DisplayName,
+						e.Email,
+						e.Mobile,
+						e.PayrollID,
+						datePart(e.StartDate),
+						strconv.Itoa(e.Company),
+						strconv.Itoa(e.Role),
+						strconv.FormatBool(e.Active),
+					})
+				}
+				return writeCSV(iocontext.FromContext(ctx).Out, employeeCSVColumns, rows)
+			}
+
+			f := outfmt.New(ctx)
+			f.StartTable([]string{"ID", "NAME", "EMAIL", "PAYROLL ID", "COMPANY", "ACTIVE"})
+			for _, e := range employees {
+				f.Row(strconv.Itoa(e.Id), e.DisplayName, e.Email, e.PayrollID, strconv.Itoa(e.Company), strconv.FormatBool(e.Active))
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "Output format: text, json or csv (defaults to --output)")
+
+	return cmd
+}
+
+func newEmployeesImportCmd() *cobra.Command {
+	var matchOn string
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "import <file.csv>",
+		Short: "Create or update employees from a CSV file",
+		Long: `Create or update employees from a CSV file, such as an HRIS export.
+
+Columns (any order, header required): first_name, last_name, email, mobile,
+payroll_id, start_date, company, role. The id, display_name and active
+columns written by 'deputy employees export' are accepted and ignored.
+
+Each row is matched to an employee by --match-on (email or payroll-id). A
+matched employee is updated where a non-empty cell differs, otherwise left
+unchanged; start_date, company and role only apply to new employees. An
+unmatched row creates an employee and needs first_name, last_name and company
+(an ID, code: or name: reference). Every row is validated before anything is
+sent; invalid rows are reported as failed and the rest are imported.
+
+Use --dry-run to see the requests without sending them, and "-" as the file
+to read from stdin.`,
+		Example: `  deputy employees import staff.csv
+  deputy employees import hris.csv --match-on payroll-id --dry-run`,
+		Args: RequireArg("file"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if matchOn != matchOnEmail && matchOn != matchOnPayrollID {
+				return fmt.Errorf("invalid --match-on %q (expected email or payroll-id)", matchOn)
+			}
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+
+			ctx := cmd.Context()
+			streams := iocontext.FromContext(ctx)
+			var in io.Reader = streams.In
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer func() { _ = file.Close() }()
+				in = file
+			}
+			rows, err := readEmployeeCSV(in, matchOn)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			live, err := client.Employees().List(ctx, nil)
+			if err != nil {
+				return err
+			}
+			validateEmployeeRows(rows, live, matchOn)
+
+			// Resolve companies up front so workers don't share the resolver.
+			resolver := newEntityResolver()
+			resolver.client = client
+			for i := range rows {
+				row := &rows[i]
+				if row.Status == employeeImportFailed || row.current != nil {
+					continue
+				}
+				if row.companyID, err = strconv.Atoi(row.Company); err != nil {
+					if row.companyID, err = resolver.resolve(ctx, entityLocation, row.Company); err != nil {
+						row.fail(err)
+					}
+				}
+			}
+
+			forEachConcurrent(len(rows), concurrency, func(i int) {
+				row := &rows[i]
+				if row.Status == employeeImportFailed {
+					return
+				}
+				importEmployeeRow(ctx, client, row)
+			})
+
+			counts := map[string]int{}
+			for _, row := range rows {
+				counts[row.Status]++
+			}
+
+			if realIO := dryRunIO(ctx); realIO != nil {
+				// --dry-run hides the command's output, but which rows would
+				// create or update is still worth seeing. It goes ahead of the
+				// plan, or to stderr in JSON mode so stdout stays the plan.
+				w := realIO.Out
+				if outfmt.GetFormat(ctx) == "json" {
+					w = realIO.ErrOut
+				}
+				printEmployeeImportReport(w, w, rows, counts, true)
+			} else if outfmt.GetFormat(ctx) == "json" {
+				if err := outfmt.New(ctx).OutputWithMeta(rows, map[string]any{
+					"count":     len(rows),
+					"created":   counts[employeeImportCreated],
+					"updated":   counts[employeeImportUpdated],
+					"unchanged": counts[employeeImportUnchanged],
+					"failed":    counts[employeeImportFailed],
+				}); err != nil {
+					return err
+				}
+			} else {
+				printEmployeeImportReport(streams.Out, streams.ErrOut, rows, counts, false)
+			}
+
+			if counts[employeeImportFailed] > 0 {
+				return fmt.Errorf("%d of %d rows failed", counts[employeeImportFailed], len(rows))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&matchOn, "match-on", matchOnEmail, "Match rows to employees by email or payroll-id")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Maximum concurrent requests")
+
+	return cmd
+}
+
+// printEmployeeImportReport writes one line per created, updated or failed
+// row and a summary. In a dry run nothing was sent, so it says what would
+// happen instead.
+func printEmployeeImportReport(out, errOut io.Writer, rows []employeeImportRow, counts map[string]int, dryRun bool) {
+	for _, row := range rows {
+		switch {
+		case row.Status == employeeImportFailed:
+			_, _ = fmt.Fprintf(errOut, "line %d: %s\n", row.Line, row.Error)
+		case row.Status == employeeImportCreated && dryRun:
+			_, _ = fmt.Fprintf(out, "line %d: would create employee\n", row.Line)
+		case row.Status == employeeImportCreated:
+			_, _ = fmt.Fprintf(out, "line %d: created employee %d\n", row.Line, row.EmployeeID)
+		case row.Status == employeeImportUpdated && dryRun:
+			_, _ = fmt.Fprintf(out, "line %d: would update employee %d (%s)\n", row.Line, row.EmployeeID, strings.Join(row.Changes, ", "))
+		case row.Status == employeeImportUpdated:
+			_, _ = fmt.Fprintf(out, "line %d: updated employee %d (%s)\n", row.Line, row.EmployeeID, strings.Join(row.Changes, ", "))
+		}
+	}
+	if dryRun {
+		_, _ = fmt.Fprintf(out, "Would import %d rows: %d to create, %d to update, %d unchanged, %d failed\n",
+			len(rows), counts[employeeImportCreated], counts[employeeImportUpdated], counts[employeeImportUnchanged], counts[employeeImportFailed])
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Imported %d rows: %d created, %d updated, %d unchanged, %d failed\n",
+		len(rows), counts[employeeImportCreated], counts[employeeImportUpdated], counts[employeeImportUnchanged], counts[employeeImportFailed])
+}
+
+// importEmployeeRow creates or updates the employee for a validated row.
+func importEmployeeRow(ctx context.Context, client *api.Client, row *employeeImportRow) {
+	if row.current != nil {
+		input, changes := employeeUpdate(row)
+		if len(changes) == 0 {
+			row.Status = employeeImportUnchanged
+			return
+		}
+		if _, err := client.Employees().Update(ctx, row.current.Id, input); err != nil {
+			row.fail(err)
+			return
+		}
+		row.Status = employeeImportUpdated
+		row.Changes = changes
+		return
+	}
+
+	created, err := client.Employees().Create(ctx, &api.CreateEmployeeInput{
+		FirstName: row.FirstName,
+		LastName:  row.LastName,
+		Email:     row.Email,
+		Mobile:    row.Mobile,
+		StartDate: row.StartDate,
+		Company:   row.companyID,
+		Role:      row.Role,
+		PayrollID: row.PayrollID,
+	})
+	if err != nil {
+		row.fail(err)
+		return
+	}
+	row.Status = employeeImportCreated
+	row.EmployeeID = created.Id
+}
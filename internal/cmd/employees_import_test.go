@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+const importLiveEmployees = `[
+  {"Id":1,"FirstName":"Ann","LastName":"Lee","DisplayName":"Ann Lee","Email":"ann@example.com","Mobile":"0400","PayrollId":"P1","Company":1,"Active":true,"StartDate":"2023-01-02T00:00:00+11:00"},
+  {"Id":2,"FirstName":"Bob","LastName":"Ray","DisplayName":"Bob Ray","Email":"bob@example.com","PayrollId":"P2","Company":2,"Active":true}
+]`
+
+func TestEmployeesExport_CSV(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/supervise/employee", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(importLiveEmployees))
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "employees", "export", "--format", "csv")
+	require.NoError(t, err)
+	assert.Equal(t, "id,first_name,last_name,display_name,email,mobile,payroll_id,start_date,company,role,active\n"+
+		"1,Ann,Lee,Ann Lee,ann@example.com,0400,P1,2023-01-02,1,0,true\n"+
+		"2,Bob,Ray,Bob Ray,bob@example.com,,P2,,2,0,true\n", out)
+}
+
+func TestEmployeesImport_UpsertsByEmail(t *testing.T) {
+	var mu sync.Mutex
+	var writes []string
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		call := r.Method + " " + r.URL.Path
+		switch call {
+		case "GET /api/v1/supervise/employee":
+			_, _ = w.Write([]byte(importLiveEmployees))
+			return
+		case "GET /api/v1/supervise/location/simplified":
+			_, _ = w.Write([]byte(`[{"Id":3,"CompanyName":"City","CompanyCode":"CBD"}]`))
+			return
+		}
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		writes = append(writes, call)
+		bodies[call] = body
+		mu.Unlock()
+		switch call {
+		case "POST /api/v1/resource/Employee/2":
+			_, _ = w.Write([]byte(`{"Id":2}`))
+		case "POST /api/v1/supervise/employee":
+			if body["strFirstName"] == "Eve" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"message":"Email in use"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Id":9}`))
+		default:
+			t.Errorf("unexpected request %s", call)
+		}
+	}))
+	defer server.Close()
+
+	input := strings.Join([]string{
+		"Email,First_Name,last_name,mobile,payroll_id,company,start_date",
+		"ann@example.com,Ann,Lee,0400,,,",                 // unchanged
+		"BOB@example.com,Bob,Ray,0411,P2b,,",              // updated
+		"cat@example.com,Cat,Kim,,P3,code:CBD,2024-07-01", // created
+		"eve@example.com,Eve,Fox,,,3,",                    // rejected by the API
+		"dan@example.com,Dan,,,,3,",                       // missing last name
+		"ann@example.com,Ann,Lee,,,,",                     // duplicate
+		"bad-email,X,Y,,,3,",                              // invalid email
+		"fay@example.com,Fay,Orr,,,3,01/07/2024",          // invalid date
+	}, "\n")
+
+	out, errOut, err := runRoot(t, server, input, "employees", "import", "-", "-o", "text", "--concurrency", "2")
+	require.EqualError(t, err, "5 of 8 rows failed")
+	assert.Contains(t, out, "line 3: updated employee 2 (mobile: \"\" -> \"0411\", payroll_id: \"P2\" -> \"P2b\")\n")
+	assert.Contains(t, out, "line 4: created employee 9\n")
+	assert.Contains(t, out, "Imported 8 rows: 1 created, 1 updated, 1 unchanged, 5 failed\n")
+	assert.Contains(t, errOut, "line 5: API error 400")
+	assert.Contains(t, errOut, "line 6: first_name, last_name and company are required for new employees\n")
+	assert.Contains(t, errOut, "line 7: duplicate of line 2\n")
+	assert.Contains(t, errOut, "line 8: invalid email \"bad-email\"\n")
+	assert.Contains(t, errOut, "line 9: invalid date format")
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(writes)
+	assert.Equal(t, []string{
+		"POST /api/v1/resource/Employee/2",
+		"POST /api/v1/supervise/employee",
+		"POST /api/v1/supervise/employee",
+	}, writes)
+	assert.Equal(t, map[string]interface{}{"strMobile": "0411", "strPayrollId": "P2b"}, bodies["POST /api/v1/resource/Employee/2"])
+}
+
+func TestEmployeesImport_MatchOnPayrollIDDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(importLiveEmployees))
+	}))
+	defer server.Close()
+
+	input := "payroll_id,email\nP1,ann.lee@example.com\nP2,bob@example.com\n"
+	out, errOut, err := runRoot(t, server, input, "employees", "import", "-", "--match-on", "payroll-id", "--dry-run", "-o", "json")
+	require.NoError(t, err)
+
+	var parsed struct {
+		Items []api.PlannedRequest `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 1)
+	assert.Contains(t, parsed.Items[0].URL, "/resource/Employee/1")
+	assert.Contains(t, errOut, "Would import 2 rows: 0 to create, 1 to update, 1 unchanged, 0 failed\n")
+
+	out, _, err = runRoot(t, server, input, "employees", "import", "-", "--match-on", "payroll-id", "--dry-run", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, `line 2: would update employee 1 (email: "ann@example.com" -> "ann.lee@example.com")`)
+	assert.Less(t, strings.Index(out, "Would import 2 rows"), strings.Index(out, "Dry run: 1 request(s) would be sent"))
+}
+
+func TestEmployeesImport_RejectsBadInput(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "email\n", "employees", "import", "-", "--match-on", "id")
+	require.EqualError(t, err, `invalid --match-on "id" (expected email or payroll-id)`)
+
+	_, _, err = runRoot(t, server, "email,nickname\n", "employees", "import", "-")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown CSV column "nickname"`)
+
+	_, _, err = runRoot(t, server, "email\n", "employees", "import", "-", "--match-on", "payroll-id")
+	require.EqualError(t, err, `CSV has no "payroll_id" column to match on`)
+}
@@ -106,6 +106,11 @@ func TestEmployeesInviteCommand_WithMockClient(t *testing.T) {
 
 func TestEmployeesReactivateCommand_WithMockClient(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// Snapshot for undo.
+			_ = json.NewEncoder(w).Encode(api.Employee{Id: 123, Active: false})
+			return
+		}
 		assert.Equal(t, http.MethodPost, r.Method)
 		assert.Equal(t, "/api/v1/resource/Employee/123", r.URL.Path)
 		w.Header().Set("Content-Type", "application/json")
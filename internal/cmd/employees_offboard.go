@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// What offboard does with shifts after the termination date.
+const (
+	offboardShiftsKeep   = "keep"
+	offboardShiftsDelete = "delete"
+	offboardShiftsReopen = "reopen"
+)
+
+// offboardAction is one step of an offboarding plan.
+type offboardAction struct {
+	Action string `json:"action"`
+	Target string `json:"target"`
+	ID     int    `json:"id,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+
+	apply func(ctx context.Context) error
+}
+
+// offboardPlan is everything offboard will do for one employee.
+type offboardPlan struct {
+	Employee *api.Employee
+	Date     string
+	Timezone *time.Location
+	Actions  []offboardAction
+	// Kept lists future shifts left alone under --shifts keep.
+	Kept []api.Roster
+}
+
+func (p *offboardPlan) add(a offboardAction) {
+	p.Actions = append(p.Actions, a)
+}
+
+// buildOffboardPlan reads the employee's open work and plans, in order:
+// closing in-progress timesheets, cancelling pending leave that ends after
+// the date, handling future shifts, removing extra locations, terminating and
+// posting a journal note. Times are shown in the employee's main location's
+// timezone.
+func buildOffboardPlan(ctx context.Context, client *api.Client, id int, date, shifts, note string) (*offboardPlan, error) {
+	employee, err := client.Employees().Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	tz, err := locationTimezone(ctx, client, employee.Company)
+	if err != nil {
+		return nil, err
+	}
+	plan := &offboardPlan{Employee: employee, Date: date, Timezone: tz}
+	byEmployee := map[string]interface{}{"field": "Employee", "type": "eq", "data": id}
+
+	timesheets, err := client.Timesheets().QueryAll(ctx, &api.QueryInput{Search: map[string]interface{}{
+		"s1": byEmployee,
+		"s2": map[string]interface{}{"field": "IsInProgress", "type": "eq", "data": true},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range timesheets {
+		if !t.IsInProgress {
+			continue
+		}
+		timesheetID := t.Id
+		plan.add(offboardAction{
+			Action: "close",
+			Target: "timesheet",
+			ID:     timesheetID,
+			Detail: "started " + formatOffboardTime(t.StartTime, tz),
+			apply: func(ctx context.Context) error {
+				_, err := client.Timesheets().ClockOut(ctx, &api.ClockInput{Timesheet: timesheetID})
+				return err
+			},
+		})
+	}
+
+	leaves, err := client.Leave().QueryAll(ctx, &api.LeaveQueryInput{Search: map[string]interface{}{
+		"s1": byEmployee,
+		"s2": map[string]interface{}{"field": "DateEnd", "type": "gt", "data": date},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	comment := fmt.Sprintf("Employee offboarded as of %s", date)
+	for _, l := range leaves {
+		// Leave that has already ended stays on record as taken.
+		if l.Status != 0 || datePart(l.DateEnd) <= date {
+			continue
+		}
+		leaveID := l.Id
+		plan.add(offboardAction{
+			Action: "cancel",
+			Target: "leave",
+			ID:     leaveID,
+			Detail: fmt.Sprintf("%s to %s", datePart(l.DateStart), datePart(l.DateEnd)),
+			apply: func(ctx context.Context) error {
+				return client.Leave().Cancel(ctx, leaveID, comment)
+			},
+		})
+	}
+
+	rosters, err := client.Rosters().QueryAll(ctx, &api.QueryInput{Search: map[string]interface{}{
+		"s1": byEmployee,
+		"s2": map[string]interface{}{"field": "Date", "type": "gt", "data": date},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rosters {
+		if datePart(r.Date) <= date {
+			continue
+		}
+		rosterID := r.Id
+		detail := fmt.Sprintf("%s %s", datePart(r.Date), formatOffboardShift(r, tz))
+		switch shifts {
+		case offboardShiftsDelete:
+			plan.add(offboardAction{Action: "delete", Target: "shift", ID: rosterID, Detail: detail, apply: func(ctx context.Context) error {
+				return client.Rosters().Delete(ctx, rosterID)
+			}})
+		case offboardShiftsReopen:
+			plan.add(offboardAction{Action: "reopen", Target: "shift", ID: rosterID, Detail: detail, apply: func(ctx context.Context) error {
+				_, err := client.Rosters().Reopen(ctx, rosterID)
+				return err
+			}})
+		default:
+			plan.Kept = append(plan.Kept, r)
+		}
+	}
+
+	workplaces, err := client.Employees().ListLocations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workplaces {
+		// The main location stays on the employee's record.
+		if w.Company == employee.Company {
+			continue
+		}
+		locationID := w.Company
+		plan.add(offboardAction{Action: "remove", Target: "location", ID: locationID, apply: func(ctx context.Context) error {
+			return client.Employees().RemoveLocation(ctx, id, locationID)
+		}})
+	}
+
+	plan.add(offboardAction{Action: "terminate", Target: "employee", ID: id, Detail: "as of " + date, apply: func(ctx context.Context) error {
+		if err := client.Employees().Terminate(ctx, id, date); err != nil {
+			return err
+		}
+		if employee.Active {
+			recordUndo(ctx, client, undoEmployeeTerminate, id, fmt.Sprintf("terminate employee %d as of %s", id, date), employeeStatusSnapshot{Active: true})
+		}
+		return nil
+	}})
+
+	if note == "" {
+		note = offboardNote(plan)
+	}
+	plan.add(offboardAction{Action: "post", Target: "journal", Detail: note, apply: func(ctx context.Context) error {
+		_, err := client.Management().PostJournal(ctx, &api.CreateJournalInput{Employee: id, Company: employee.Company, Comment: note})
+		return err
+	}})
+	return plan, nil
+}
+
+// offboardNote summarises the plan for the journal.
+func offboardNote(p *offboardPlan) string {
+	counts := map[string]int{}
+	for _, a := range p.Actions {
+		counts[a.Action+" "+a.Target]++
+	}
+	note := fmt.Sprintf("Offboarded as of %s.", p.Date)
+	for _, part := range []struct{ key, label string }{
+		{"close timesheet", "timesheets closed"},
+		{"cancel leave", "leave requests cancelled"},
+		{"delete shift", "future shifts deleted"},
+		{"reopen shift", "future shifts reopened"},
+		{"remove location", "locations removed"},
+	} {
+		if n := counts[part.key]; n > 0 {
+			note += fmt.Sprintf(" %d %s.", n, part.label)
+		}
+	}
+	if len(p.Kept) > 0 {
+		note += fmt.Sprintf(" %d future shifts left in place.", len(p.Kept))
+	}
+	return note
+}
+
+func formatOffboardTime(ts int64, tz *time.Location) string {
+	if ts == 0 {
+		return "-"
+	}
+	return time.Unix(ts, 0).In(tz).Format("2006-01-02 15:04")
+}
+
+func formatOffboardShift(r api.Roster, tz *time.Location) string {
+	return time.Unix(r.StartTime, 0).In(tz).Format("15:04") + "-" + time.Unix(r.EndTime, 0).In(tz).Format("15:04")
+}
+
+func printOffboardPlan(w io.Writer, p *offboardPlan) {
+	name := p.Employee.DisplayName
+	if name == "" {
+		name = "employee " + strconv.Itoa(p.Employee.Id)
+	}
+	_, _ = fmt.Fprintf(w, "Offboarding %s (employee %d) as of %s:\n", name, p.Employee.Id, p.Date)
+	for _, a := range p.Actions {
+		_, _ = fmt.Fprintf(w, "  %s\n", offboardActionLabel(a))
+	}
+	if len(p.Kept) > 0 {
+		_, _ = fmt.Fprintf(w, "\n%d future shifts will be kept (use --shifts delete or --shifts reopen):\n", len(p.Kept))
+		for _, r := range p.Kept {
+			_, _ = fmt.Fprintf(w, "  shift %d: %s %s\n", r.Id, datePart(r.Date), formatOffboardShift(r, p.Timezone))
+		}
+	}
+}
+
+func offboardActionLabel(a offboardAction) string {
+	label := a.Action + " " + a.Target
+	if a.ID != 0 && a.Target != "employee" {
+		label += " " + strconv.Itoa(a.ID)
+	}
+	if a.Detail != "" {
+		if a.Target == "journal" {
+			label += fmt.Sprintf(" %q", a.Detail)
+		} else {
+			label += " (" + a.Detail + ")"
+		}
+	}
+	return label
+}
+
+func newEmployeesOffboardCmd() *cobra.Command {
+	var date, shifts, note string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "offboard <id>",
+		Short: "Terminate an employee and clean up their open work",
+		Long: `Offboard an employee as of --date. In order, offboard:
+
+  - closes their in-progress timesheets
+  - cancels their leave requests awaiting approval that end after the date
+  - deletes or reopens their shifts after the date (--shifts)
+  - removes their locations other than their main one
+  - terminates them as of the date
+  - posts a journal note summarising the above (or --note)
+
+The plan is shown and confirmed once before anything changes; --yes skips
+the prompt. Future shifts are listed but kept unless --shifts is delete or
+reopen. Times are shown in the timezone of the employee's main location. If a
+step fails, offboard stops and reports what was already done.`,
+		Example: `  deputy employees offboard 42 --date 2024-07-31
+  deputy employees offboard 42 --date 2024-07-31 --shifts reopen --yes
+  deputy employees offboard 42 --date 2024-07-31 --shifts delete --dry-run`,
+		Args: RequireArg("id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid employee ID: %s", args[0])
+			}
+			if date == "" {
+				return errors.New("--date is required")
+			}
+			if err := validateDateFormat(date); err != nil {
+				return err
+			}
+			switch shifts {
+			case offboardShiftsKeep, offboardShiftsDelete, offboardShiftsReopen:
+			default:
+				return fmt.Errorf("invalid --shifts %q (expected keep, delete or reopen)", shifts)
+			}
+
+			ctx := cmd.Context()
+			io := iocontext.FromContext(ctx)
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			plan, err := buildOffboardPlan(ctx, client, id, date, shifts, note)
+			if err != nil {
+				return err
+			}
+
+			format := outfmt.GetFormat(ctx)
+			if format != "json" {
+				printOffboardPlan(io.Out, plan)
+			}
+			if err := confirmDestructive(ctx, yes, fmt.Sprintf("Offboard employee %d?", id)); err != nil {
+				return err
+			}
+
+			if format != "json" {
+				_, _ = fmt.Fprintln(io.Out)
+			}
+			var failed error
+			for i := range plan.Actions {
+				a := &plan.Actions[i]
+				if failed != nil {
+					a.Status = "skipped"
+					continue
+				}
+				if err := a.apply(ctx); err != nil {
+					a.Status = "failed"
+					a.Error = err.Error()
+					failed = fmt.Errorf("%s: %w", offboardActionLabel(*a), err)
+					continue
+				}
+				a.Status = "done"
+				if format != "json" {
+					_, _ = fmt.Fprintf(io.Out, "%s: done\n", offboardActionLabel(*a))
+				}
+			}
+
+			if format == "json" {
+				if err := outfmt.New(ctx).OutputWithMeta(plan.Actions, map[string]interface{}{
+					"employee":   id,
+					"date":       date,
+					"keptShifts": plan.Kept,
+				}); err != nil {
+					return err
+				}
+			} else if failed == nil {
+				_, _ = fmt.Fprintf(io.Out, "\nEmployee %d offboarded as of %s\n", id, date)
+			}
+			return failed
+		},
+	}
+
+	cmd.Flags().StringVar(&date, "date", "", "Termination date (YYYY-MM-DD) (required)")
+	cmd.Flags().StringVar(&shifts, "shifts", offboardShiftsKeep, "Shifts after the date: keep, delete or reopen")
+	cmd.Flags().StringVar(&note, "note", "", "Journal note (default: a summary of the changes)")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// offboardServer fakes one employee with open work; failPath makes that
+// write fail.
+func offboardServer(failPath string, writes *[]string, bodies map[string]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		call := r.Method + " " + r.URL.Path
+		switch call {
+		case "GET /api/v1/supervise/employee/42":
+			_, _ = w.Write([]byte(`{"Id":42,"DisplayName":"Jane Doe","Company":1,"Active":true}`))
+			return
+		case "GET /api/v1/resource/Company/1":
+			_, _ = w.Write([]byte(`{"Id":1,"Timezone":"Australia/Sydney"}`))
+			return
+		case "POST /api/v1/resource/Timesheet/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":500,"Employee":42,"IsInProgress":true,"StartTime":1722330000}]`))
+			return
+		case "POST /api/v1/resource/Leave/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":77,"Employee":42,"Status":0,"DateStart":"2024-08-05","DateEnd":"2024-08-09"},{"Id":78,"Employee":42,"Status":1},{"Id":79,"Employee":42,"Status":0,"DateStart":"2024-07-15","DateEnd":"2024-07-19"}]`))
+			return
+		case "POST /api/v1/resource/Roster/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":900,"Employee":42,"Date":"2024-08-01T00:00:00+10:00","StartTime":1722463200,"EndTime":1722492000},{"Id":901,"Employee":42,"Date":"2024-08-02"}]`))
+			return
+		case "POST /api/v1/resource/EmployeeWorkplace/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":1,"Employee":42,"Company":1},{"Id":2,"Employee":42,"Company":2}]`))
+			return
+		}
+		*writes = append(*writes, call)
+		if bodies != nil {
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			bodies[call] = body
+		}
+		if r.URL.Path == failPath {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"message":"Not allowed"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+}
+
+func TestEmployeesOffboard_RunsPlanInOrder(t *testing.T) {
+	var writes []string
+	bodies := map[string]map[string]interface{}{}
+	server := offboardServer("", &writes, bodies)
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "employees", "offboard", "42", "--date", "2024-07-31", "--shifts", "reopen", "--yes", "-o", "text")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"POST /api/v1/supervise/timesheet/stop",
+		"POST /api/v1/resource/Leave/77",
+		"POST /api/v1/resource/Roster/900",
+		"POST /api/v1/resource/Roster/901",
+		"DELETE /api/v1/supervise/employee/42/location/2",
+		"POST /api/v1/supervise/employee/42/terminate",
+		"POST /api/v1/supervise/journal",
+	}, writes)
+	assert.Equal(t, 3.0, bodies["POST /api/v1/resource/Leave/77"]["intStatus"])
+	assert.Equal(t, 500.0, bodies["POST /api/v1/supervise/timesheet/stop"]["intTimesheetId"])
+	assert.Equal(t, "Offboarded as of 2024-07-31. 1 timesheets closed. 1 leave requests cancelled. 2 future shifts reopened. 1 locations removed.",
+		bodies["POST /api/v1/supervise/journal"]["strComment"])
+
+	assert.Contains(t, out, "Offboarding Jane Doe (employee 42) as of 2024-07-31:\n")
+	assert.Contains(t, out, "  cancel leave 77 (2024-08-05 to 2024-08-09)\n")
+	assert.NotContains(t, out, "leave 79", "leave that ended before the date is kept")
+	// Times are in the location's timezone, not the machine's.
+	assert.Contains(t, out, "  close timesheet 500 (started 2024-07-30 19:00)\n")
+	assert.Contains(t, out, "  reopen shift 900 (2024-08-01 08:00-16:00)\n")
+	assert.Contains(t, out, "  terminate employee (as of 2024-07-31)\n")
+	assert.Contains(t, out, "reopen shift 901 (2024-08-02 ")
+	assert.Contains(t, out, "Employee 42 offboarded as of 2024-07-31\n")
+}
+
+func TestEmployeesOffboard_KeepsShiftsAndStopsOnFailure(t *testing.T) {
+	var writes []string
+	server := offboardServer("/api/v1/supervise/employee/42/location/2", &writes, nil)
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "employees", "offboard", "42", "--date", "2024-07-31", "--note", "Resigned", "-o", "json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remove location 2:")
+
+	// Shifts are kept by default; nothing after the failed step runs.
+	assert.Equal(t, []string{
+		"POST /api/v1/supervise/timesheet/stop",
+		"POST /api/v1/resource/Leave/77",
+		"DELETE /api/v1/supervise/employee/42/location/2",
+	}, writes)
+
+	var parsed struct {
+		Items []offboardAction       `json:"items"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 5)
+	assert.Equal(t, "failed", parsed.Items[2].Status)
+	assert.Equal(t, "skipped", parsed.Items[3].Status)
+	assert.Equal(t, "Resigned", parsed.Items[4].Detail)
+	assert.Len(t, parsed.Meta["keptShifts"], 2)
+}
+
+func TestEmployeesOffboard_ValidatesFlags(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "", "employees", "offboard", "42")
+	require.EqualError(t, err, "--date is required")
+
+	_, _, err = runRoot(t, server, "", "employees", "offboard", "42", "--date", "2024-07-31", "--shifts", "drop")
+	require.EqualError(t, err, `invalid --shifts "drop" (expected keep, delete or reopen)`)
+}
@@ -0,0 +1,480 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// OnboardSpec is the hires file read by `deputy employees onboard`.
+type OnboardSpec struct {
+	Hires []OnboardHire `yaml:"hires"`
+}
+
+// OnboardHire describes one person to onboard. Email identifies the hire, so
+// re-running a file finds employees created by an earlier run.
+type OnboardHire struct {
+	FirstName      string                  `yaml:"firstName"`
+	LastName       string                  `yaml:"lastName"`
+	Email          string                  `yaml:"email"`
+	Mobile         string                  `yaml:"mobile"`
+	StartDate      string                  `yaml:"startDate"`
+	Role           int                     `yaml:"role"`
+	Location       string                  `yaml:"location"`
+	Locations      []string                `yaml:"locations"`
+	Award          *OnboardAward           `yaml:"award"`
+	Unavailability []OnboardUnavailability `yaml:"unavailability"`
+	Invite         bool                    `yaml:"invite"`
+}
+
+// OnboardAward is the library award to assign.
+type OnboardAward struct {
+	Code      string   `yaml:"code"`
+	Country   string   `yaml:"country"`
+	Overrides []string `yaml:"overrides"`
+}
+
+// OnboardUnavailability is one unavailable date range.
+type OnboardUnavailability struct {
+	Start   string `yaml:"start"`
+	End     string `yaml:"end"`
+	Comment string `yaml:"comment"`
+}
+
+func (h OnboardHire) name() string {
+	return strings.TrimSpace(h.FirstName + " " + h.LastName)
+}
+
+func (s *OnboardSpec) validate() error {
+	if len(s.Hires) == 0 {
+		return errors.New("no hires listed")
+	}
+	seen := map[string]bool{}
+	for i, h := range s.Hires {
+		prefix := fmt.Sprintf("hires[%d]", i)
+		if h.FirstName == "" || h.LastName == "" {
+			return fmt.Errorf("%s: firstName and lastName are required", prefix)
+		}
+		if h.Email == "" {
+			return fmt.Errorf("%s: email is required", prefix)
+		}
+		key := strings.ToLower(h.Email)
+		if seen[key] {
+			return fmt.Errorf("%s: %s is listed more than once", prefix, h.Email)
+		}
+		seen[key] = true
+		if h.Location == "" {
+			return fmt.Errorf("%s: location is required", prefix)
+		}
+		for _, ref := range append([]string{h.Location}, h.Locations...) {
+			if _, err := strconv.Atoi(ref); err != nil && !isEntityRef(ref) {
+				return fmt.Errorf("%s: location %q must be an ID or a code:/name: reference", prefix, ref)
+			}
+		}
+		if h.StartDate != "" {
+			if err := validateDateFormat(h.StartDate); err != nil {
+				return fmt.Errorf("%s: %w", prefix, err)
+			}
+		}
+		if a := h.Award; a != nil {
+			if a.Code == "" || a.Country == "" {
+				return fmt.Errorf("%s: award code and country are required", prefix)
+			}
+			for _, o := range a.Overrides {
+				if _, err := parseOverridePayRule(o); err != nil {
+					return fmt.Errorf("%s: %w", prefix, err)
+				}
+			}
+		}
+		for j, u := range h.Unavailability {
+			if u.Start == "" || u.End == "" {
+				return fmt.Errorf("%s unavailability[%d]: start and end are required", prefix, j)
+			}
+			for _, d := range []string{u.Start, u.End} {
+				if err := validateDateFormat(d); err != nil {
+					return fmt.Errorf("%s unavailability[%d]: %w", prefix, j, err)
+				}
+			}
+			if u.End < u.Start {
+				return fmt.Errorf("%s unavailability[%d]: end is before start", prefix, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Step outcomes reported by onboard.
+const (
+	onboardDone       = "done"
+	onboardSkipped    = "skipped"
+	onboardFailed     = "failed"
+	onboardRolledBack = "rolled back"
+)
+
+// onboardStep reports one step of one hire.
+type onboardStep struct {
+	Step   string `json:"step"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// onboardResult reports one hire.
+type onboardResult struct {
+	Email      string        `json:"email"`
+	Name       string        `json:"name"`
+	EmployeeID int           `json:"employeeId,omitempty"`
+	OK         bool          `json:"ok"`
+	Error      string        `json:"error,omitempty"`
+	Steps      []onboardStep `json:"steps"`
+}
+
+// onboardRun holds what every hire in a file shares: the client, one
+// employee listing for email lookups, and the location resolver.
+type onboardRun struct {
+	client    *api.Client
+	resolver  *entityResolver
+	employees map[string]api.Employee
+	progress  io.Writer
+}
+
+func newOnboardRun(ctx context.Context, client *api.Client, progress io.Writer) (*onboardRun, error) {
+	employees, err := client.Employees().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	byEmail := make(map[string]api.Employee, len(employees))
+	for _, e := range employees {
+		if e.Email != "" {
+			byEmail[strings.ToLower(e.Email)] = e
+		}
+	}
+	resolver := newEntityResolver()
+	resolver.client = client
+	return &onboardRun{client: client, resolver: resolver, employees: byEmail, progress: progress}, nil
+}
+
+func (r *onboardRun) resolveLocation(ctx context.Context, ref string) (int, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		return id, nil
+	}
+	return r.resolver.resolve(ctx, entityLocation, ref)
+}
+
+// report records a step and prints it as progress.
+func (r *onboardRun) report(res *onboardResult, step, status, detail string) {
+	res.Steps = append(res.Steps, onboardStep{Step: step, Status: status, Detail: detail})
+	if r.progress == nil {
+		return
+	}
+	line := fmt.Sprintf("  %s: %s", step, status)
+	if detail != "" {
+		line += " (" + detail + ")"
+	}
+	_, _ = fmt.Fprintln(r.progress, line)
+}
+
+// onboardUndo reverses one step; only steps taken in this run are undone.
+type onboardUndo struct {
+	step string
+	fn   func() error
+}
+
+// onboard runs every step for one hire. Steps already done are skipped. When
+// a step fails, the steps this run took are undone in reverse order; awards
+// cannot be undone, and the invite goes last so a failed hire is never sent
+// one.
+func (r *onboardRun) onboard(ctx context.Context, h OnboardHire) onboardResult {
+	res := onboardResult{Email: h.Email, Name: h.name()}
+	if r.progress != nil {
+		_, _ = fmt.Fprintf(r.progress, "%s <%s>\n", res.Name, h.Email)
+	}
+	var undo []onboardUndo
+
+	fail := func(step string, err error) onboardResult {
+		r.report(&res, step, onboardFailed, err.Error())
+		res.Error = fmt.Sprintf("%s: %v", step, err)
+		for i := len(undo) - 1; i >= 0; i-- {
+			u := undo[i]
+			if uerr := u.fn(); uerr != nil {
+				r.report(&res, "undo "+u.step, onboardFailed, uerr.Error())
+				continue
+			}
+			r.report(&res, "undo "+u.step, onboardRolledBack, "")
+		}
+		return res
+	}
+
+	mainLocation, err := r.resolveLocation(ctx, h.Location)
+	if err != nil {
+		return fail("resolve locations", err)
+	}
+	locations := []int{mainLocation}
+	for _, ref := range h.Locations {
+		id, err := r.resolveLocation(ctx, ref)
+		if err != nil {
+			return fail("resolve locations", err)
+		}
+		locations = append(locations, id)
+	}
+
+	// add
+	employee, existed := r.employees[strings.ToLower(h.Email)]
+	if existed {
+		r.report(&res, "add", onboardSkipped, fmt.Sprintf("exists as employee %d", employee.Id))
+	} else {
+		created, err := r.client.Employees().Create(ctx, &api.CreateEmployeeInput{
+			FirstName: h.FirstName,
+			LastName:  h.LastName,
+			Email:     h.Email,
+			Mobile:    h.Mobile,
+			StartDate: h.StartDate,
+			Company:   mainLocation,
+			Role:      h.Role,
+		})
+		if err != nil {
+			return fail("add", err)
+		}
+		employee = *created
+		r.employees[strings.ToLower(h.Email)] = employee
+		id := employee.Id
+		undo = append(undo, onboardUndo{step: "add", fn: func() error {
+			delete(r.employees, strings.ToLower(h.Email))
+			return r.client.Employees().Delete(ctx, id)
+		}})
+		r.report(&res, "add", onboardDone, fmt.Sprintf("employee %d", id))
+	}
+	res.EmployeeID = employee.Id
+
+	// assign-location: a new employee already works at their main location.
+	assigned := map[int]bool{}
+	if existed {
+		workplaces, err := r.client.Employees().ListLocations(ctx, employee.Id)
+		if err != nil {
+			return fail("assign-location", err)
+		}
+		for _, w := range workplaces {
+			assigned[w.Company] = true
+		}
+	} else {
+		assigned[mainLocation] = true
+	}
+	for _, loc := range locations {
+		step := fmt.Sprintf("assign-location %d", loc)
+		if assigned[loc] || loc == employee.Company {
+			r.report(&res, step, onboardSkipped, "already assigned")
+			continue
+		}
+		if err := r.client.Employees().AssignLocation(ctx, employee.Id, loc); err != nil {
+			return fail(step, err)
+		}
+		assigned[loc] = true
+		employeeID, locationID := employee.Id, loc
+		undo = append(undo, onboardUndo{step: step, fn: func() error {
+			return r.client.Employees().RemoveLocation(ctx, employeeID, locationID)
+		}})
+		r.report(&res, step, onboardDone, "")
+	}
+
+	// award: an active agreement means pay is already set up.
+	if a := h.Award; a != nil {
+		step := "award " + a.Code
+		hasAgreement := false
+		if existed {
+			agreements, err := r.client.Agreements().ListByEmployee(ctx, employee.Id, true)
+			if err != nil {
+				return fail(step, err)
+			}
+			hasAgreement = len(agreements) > 0
+		}
+		if hasAgreement {
+			r.report(&res, step, onboardSkipped, "employee already has an active agreement")
+		} else {
+			overrides := make([]api.OverridePayRule, 0, len(a.Overrides))
+			for _, raw := range a.Overrides {
+				o, _ := parseOverridePayRule(raw) // checked by validate
+				overrides = append(overrides, o)
+			}
+			_, err := r.client.PayRates().SetAwardFromLibrary(ctx, employee.Id, &api.SetAwardFromLibraryInput{
+				CountryCode:     a.Country,
+				AwardCode:       a.Code,
+				OverridePayRule: overrides,
+			})
+			if err != nil {
+				return fail(step, err)
+			}
+			r.report(&res, step, onboardDone, "")
+		}
+	}
+
+	// unavailability: ranges with the same dates are already there.
+	if len(h.Unavailability) > 0 {
+		var existing []api.Unavailability
+		if existed {
+			if existing, err = r.client.Employees().ListUnavailability(ctx, employee.Id); err != nil {
+				return fail("unavailability", err)
+			}
+		}
+		for _, u := range h.Unavailability {
+			step := fmt.Sprintf("unavailability %s..%s", u.Start, u.End)
+			if hasUnavailability(existing, u) {
+				r.report(&res, step, onboardSkipped, "already recorded")
+				continue
+			}
+			created, err := r.client.Employees().AddUnavailability(ctx, &api.CreateUnavailabilityInput{
+				Employee:  employee.Id,
+				DateStart: u.Start,
+				DateEnd:   u.End,
+				Comment:   u.Comment,
+			})
+			if err != nil {
+				return fail(step, err)
+			}
+			id := created.Id
+			undo = append(undo, onboardUndo{step: step, fn: func() error {
+				return r.client.Employees().DeleteUnavailability(ctx, id)
+			}})
+			r.report(&res, step, onboardDone, "")
+		}
+	}
+
+	// invite: an employee with a login has already accepted one.
+	if h.Invite {
+		hasLogin := false
+		if existed {
+			record, err := r.client.Resource("Employee").Get(ctx, employee.Id)
+			if err != nil {
+				return fail("invite", err)
+			}
+			if userID, ok := record["UserId"].(float64); ok && userID > 0 {
+				hasLogin = true
+			}
+		}
+		if hasLogin {
+			r.report(&res, "invite", onboardSkipped, "employee already has a login")
+		} else {
+			if err := r.client.Employees().Invite(ctx, employee.Id); err != nil {
+				return fail("invite", err)
+			}
+			r.report(&res, "invite", onboardDone, "")
+		}
+	}
+
+	res.OK = true
+	return res
+}
+
+func hasUnavailability(existing []api.Unavailability, want OnboardUnavailability) bool {
+	for _, u := range existing {
+		if datePart(u.DateStart) == want.Start && datePart(u.DateEnd) == want.End {
+			return true
+		}
+	}
+	return false
+}
+
+func newEmployeesOnboardCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "onboard",
+		Short: "Onboard new hires from a YAML file",
+		Long: `Run the whole onboarding sequence for each hire in a YAML file: add the
+employee, assign their locations, set their award, record unavailability and
+send the invite.
+
+Example hires.yaml:
+
+  hires:
+    - firstName: Jane
+      lastName: Doe
+      email: jane@example.com
+      startDate: 2024-07-01
+      role: 50
+      location: code:MST          # main location (ID, code: or name:)
+      locations: [code:CBD, 7]     # further locations
+      award:
+        code: MA000003
+        country: au
+        overrides: ["12:28.50"]
+      unavailability:
+        - start: 2024-07-10
+          end: 2024-07-12
+          comment: Exams
+      invite: true
+
+Hires are matched to existing employees by email, so re-running a file skips
+steps already done: existing employees, assigned locations, an active pay
+agreement, unavailability with the same dates, and invites for employees who
+already have a login. If a step fails, the steps taken for that hire in this
+run are undone (a new employee is deleted) and the next hire is started.
+Awards cannot be undone; invites go last so a failed hire is never sent one.`,
+		Example: `  deputy employees onboard -f hires.yaml
+  deputy employees onboard -f hires.yaml --dry-run
+  cat hires.yaml | deputy employees onboard -f - -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return errors.New("-f/--file is required")
+			}
+			ctx := cmd.Context()
+			io := iocontext.FromContext(ctx)
+			spec := &OnboardSpec{}
+			if err := readStrictYAML(file, io.In, spec); err != nil {
+				return err
+			}
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			format := outfmt.GetFormat(ctx)
+			progress := io.Out
+			if format == "json" {
+				progress = nil
+			}
+			run, err := newOnboardRun(ctx, client, progress)
+			if err != nil {
+				return err
+			}
+
+			results := make([]onboardResult, 0, len(spec.Hires))
+			failed := 0
+			for _, h := range spec.Hires {
+				res := run.onboard(ctx, h)
+				if !res.OK {
+					failed++
+				}
+				results = append(results, res)
+			}
+
+			if format == "json" {
+				if err := outfmt.New(ctx).OutputWithMeta(results, map[string]interface{}{
+					"count":     len(results),
+					"succeeded": len(results) - failed,
+					"failed":    failed,
+					"file":      file,
+				}); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(io.Out, "\nOnboarded %d of %d hires\n", len(results)-failed, len(results))
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d hires failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Hires YAML file, or - for stdin (required)")
+
+	return cmd
+}
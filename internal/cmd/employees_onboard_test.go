@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const onboardHiresYAML = `hires:
+  - firstName: Jane
+    lastName: Doe
+    email: jane@example.com
+    startDate: 2024-07-01
+    location: code:MST
+    locations: [code:CBD]
+    award:
+      code: MA000003
+      country: au
+      overrides: ["12:28.50"]
+    unavailability:
+      - start: 2024-07-10
+        end: 2024-07-12
+        comment: Exams
+    invite: true
+  - firstName: Sam
+    lastName: Lee
+    email: SAM@example.com
+    location: 1
+    locations: [2, 3]
+    award:
+      code: MA000003
+      country: au
+    unavailability:
+      - start: 2024-08-01
+        end: 2024-08-01
+      - start: 2024-09-01
+        end: 2024-09-02
+    invite: true
+`
+
+func TestEmployeesOnboard_RunsStepsSkipsDoneAndRollsBack(t *testing.T) {
+	var mu sync.Mutex
+	var writes []string
+	var createBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		if r.Method != "GET" && !strings.HasSuffix(r.URL.Path, "/QUERY") {
+			mu.Lock()
+			writes = append(writes, call)
+			mu.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "GET /api/v1/supervise/employee":
+			_, _ = w.Write([]byte(`[{"Id":7,"DisplayName":"Sam Lee","Email":"sam@example.com","Company":1}]`))
+		case "GET /api/v1/supervise/location/simplified":
+			_, _ = w.Write([]byte(`[{"Id":1,"CompanyName":"Main Street","CompanyCode":"MST"},{"Id":2,"CompanyName":"City","CompanyCode":"CBD"}]`))
+		case "POST /api/v1/supervise/employee":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&createBody))
+			_, _ = w.Write([]byte(`{"Id":42,"DisplayName":"Jane Doe","Company":1}`))
+		case "POST /api/v1/resource/EmployeeWorkplace/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":1,"Employee":7,"Company":1},{"Id":2,"Employee":7,"Company":2}]`))
+		case "POST /api/v1/resource/EmployeeAgreement/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":9,"Employee":7,"Active":true}]`))
+		case "POST /api/v1/resource/EmployeeAvailability/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":30,"Employee":7,"DateStart":"2024-08-01T00:00:00+10:00","DateEnd":"2024-08-01T23:59:59+10:00"}]`))
+		case "POST /api/v1/resource/EmployeeAvailability":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body["intEmployee"] == 7.0 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"message":"Overlaps leave"}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Id":31}`))
+		case "POST /api/v1/supervise/employee/42/location",
+			"POST /api/v1/supervise/employee/7/location",
+			"DELETE /api/v1/supervise/employee/7/location/3",
+			"POST /api/v1/supervise/employee/42/setAwardFromLibrary",
+			"POST /api/v1/supervise/employee/42/invite":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s", call)
+		}
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, onboardHiresYAML, "employees", "onboard", "-f", "-", "-o", "text")
+	require.EqualError(t, err, "1 of 2 hires failed")
+
+	assert.Equal(t, "2024-07-01", createBody["strStartDate"])
+	assert.Equal(t, 1.0, createBody["intCompany"])
+
+	// Sam already exists: location 2, the award and the first range are
+	// skipped; location 3 is assigned, the second range fails, and the
+	// assignment is undone. No invite is sent.
+	assert.Equal(t, []string{
+		"POST /api/v1/supervise/employee",
+		"POST /api/v1/supervise/employee/42/location",
+		"POST /api/v1/supervise/employee/42/setAwardFromLibrary",
+		"POST /api/v1/resource/EmployeeAvailability",
+		"POST /api/v1/supervise/employee/42/invite",
+		"POST /api/v1/supervise/employee/7/location",
+		"POST /api/v1/resource/EmployeeAvailability",
+		"DELETE /api/v1/supervise/employee/7/location/3",
+	}, writes)
+
+	assert.Contains(t, out, "Jane Doe <jane@example.com>\n  add: done (employee 42)\n  assign-location 1: skipped (already assigned)\n  assign-location 2: done\n")
+	assert.Contains(t, out, "  add: skipped (exists as employee 7)\n")
+	assert.Contains(t, out, "  award MA000003: skipped (employee already has an active agreement)\n")
+	assert.Contains(t, out, "  unavailability 2024-08-01..2024-08-01: skipped (already recorded)\n")
+	assert.Contains(t, out, "  unavailability 2024-09-01..2024-09-02: failed (")
+	assert.Contains(t, out, "  undo assign-location 3: rolled back\n")
+	assert.Contains(t, out, "Onboarded 1 of 2 hires")
+	assert.NotContains(t, out, "Sam Lee <SAM@example.com>\n  invite")
+}
+
+func TestEmployeesOnboard_NewHireFailureDeletesEmployee(t *testing.T) {
+	var writes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := r.Method + " " + r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		switch call {
+		case "GET /api/v1/supervise/employee":
+			_, _ = w.Write([]byte(`[]`))
+		case "POST /api/v1/supervise/employee":
+			writes = append(writes, call)
+			_, _ = w.Write([]byte(`{"Id":42,"Company":1}`))
+		case "POST /api/v1/supervise/employee/42/location":
+			writes = append(writes, call)
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"message":"Not allowed"}}`))
+		case "DELETE /api/v1/supervise/employee/42":
+			writes = append(writes, call)
+			w.WriteHeader(http.StatusOK)
+		default:
+			t.Errorf("unexpected request %s", call)
+		}
+	}))
+	defer server.Close()
+
+	input := "hires:\n  - firstName: Jane\n    lastName: Doe\n    email: jane@example.com\n    location: 1\n    locations: [2]\n"
+	out, _, err := runRoot(t, server, input, "employees", "onboard", "-f", "-", "-o", "json")
+	require.EqualError(t, err, "1 of 1 hires failed")
+	assert.Equal(t, []string{
+		"POST /api/v1/supervise/employee",
+		"POST /api/v1/supervise/employee/42/location",
+		"DELETE /api/v1/supervise/employee/42",
+	}, writes)
+
+	var parsed struct {
+		Items []onboardResult        `json:"items"`
+		Meta  map[string]interface{} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 1)
+	res := parsed.Items[0]
+	assert.False(t, res.OK)
+	assert.Equal(t, 42, res.EmployeeID)
+	assert.Contains(t, res.Error, "assign-location 2:")
+	assert.Equal(t, onboardStep{Step: "undo add", Status: onboardRolledBack}, res.Steps[len(res.Steps)-1])
+	assert.Equal(t, 1.0, parsed.Meta["failed"])
+}
+
+func TestReadOnboardSpec_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"empty", "hires: []\n", "no hires listed"},
+		{"unknown key", "hires:\n  - firstName: A\n    lastname: B\n", "lastname"},
+		{"missing email", "hires:\n  - firstName: A\n    lastName: B\n    location: 1\n", "email is required"},
+		{"duplicate email", "hires:\n  - {firstName: A, lastName: B, email: a@x.com, location: 1}\n  - {firstName: C, lastName: D, email: A@x.com, location: 1}\n", "listed more than once"},
+		{"bad location", "hires:\n  - {firstName: A, lastName: B, email: a@x.com, location: Main}\n", "code:/name: reference"},
+		{"bad date", "hires:\n  - {firstName: A, lastName: B, email: a@x.com, location: 1, startDate: 1/7/2024}\n", "expected YYYY-MM-DD"},
+		{"award without country", "hires:\n  - {firstName: A, lastName: B, email: a@x.com, location: 1, award: {code: X}}\n", "award code and country"},
+		{"backwards range", "hires:\n  - {firstName: A, lastName: B, email: a@x.com, location: 1, unavailability: [{start: 2024-02-02, end: 2024-02-01}]}\n", "end is before start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := readStrictYAML("-", strings.NewReader(tt.yaml), &OnboardSpec{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
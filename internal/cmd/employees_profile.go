@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+)
+
+// employeeProfile is `employees get` output: the employee with their Contact
+// and main Address records joined under Deputy's *Object names.
+type employeeProfile struct {
+	*api.Employee
+	ContactObject     map[string]interface{} `json:"ContactObject,omitempty"`
+	MainAddressObject map[string]interface{} `json:"MainAddressObject,omitempty"`
+}
+
+// loadEmployeeProfile fetches the employee's joined Contact and Address. A
+// join that cannot be read is left out with a warning on stderr rather than
+// hiding the employee record itself.
+func loadEmployeeProfile(ctx context.Context, client *api.Client, employee *api.Employee) *employeeProfile {
+	errOut := iocontext.FromContext(ctx).ErrOut
+	profile := &employeeProfile{Employee: employee}
+	if employee.Contact != 0 {
+		contact, err := client.Resource("Contact").Get(ctx, employee.Contact)
+		if err != nil {
+			_, _ = fmt.Fprintf(errOut, "warning: could not load contact %d: %v\n", employee.Contact, err)
+		} else {
+			profile.ContactObject = contact
+		}
+	}
+	if employee.MainAddress != 0 {
+		address, err := client.Resource("Address").Get(ctx, employee.MainAddress)
+		if err != nil {
+			_, _ = fmt.Fprintf(errOut, "warning: could not load address %d: %v\n", employee.MainAddress, err)
+		} else {
+			profile.MainAddressObject = address
+		}
+	}
+	return profile
+}
+
+// printJoinedRecord prints a joined record's non-empty fields under a
+// heading, leaving out Deputy's bookkeeping fields.
+func printJoinedRecord(w io.Writer, title string, record map[string]interface{}) {
+	if record == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "\n%s:\n", title)
+	keys := make([]string, 0, len(record))
+	for k, v := range record {
+		if resourceReadOnlyFields[k] || k == "_DPMetaData" || v == nil || v == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(w, "  %s: %v\n", k, record[k])
+	}
+}
+
+// employeeCustomFields maps --custom key=value pairs to CustomFieldData
+// columns (f01, f02, ...). Keys match a custom field's ApiName or Name.
+func employeeCustomFields(ctx context.Context, client *api.Client, customs []string) (map[string]interface{}, error) {
+	defs, err := client.Resource("CustomField").List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]string, len(defs)*2)
+	var names []string
+	for _, d := range defs {
+		column, _ := d["DeputyField"].(string)
+		if column == "" {
+			continue
+		}
+		for _, key := range []string{"ApiName", "Name"} {
+			if name, _ := d[key].(string); name != "" {
+				columns[strings.ToLower(name)] = column
+			}
+		}
+		name, _ := d["ApiName"].(string)
+		if name == "" {
+			name, _ = d["Name"].(string)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	data := make(map[string]interface{}, len(customs))
+	for _, custom := range customs {
+		key, value, ok := strings.Cut(custom, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --custom %q (expected key=value)", custom)
+		}
+		column, ok := columns[strings.ToLower(key)]
+		if !ok {
+			return nil, fmt.Errorf("unknown custom field %q (available: %s)", key, strings.Join(names, ", "))
+		}
+		data[column] = value
+	}
+	return data, nil
+}
+
+// setEmployeeCustomFields writes custom field values to the employee's
+// CustomFieldData record. An employee without one gets a new record, whose
+// ID is returned so the caller can link it.
+func setEmployeeCustomFields(ctx context.Context, client *api.Client, employeeID int, data map[string]interface{}) (int, error) {
+	record, err := client.Resource("Employee").Get(ctx, employeeID)
+	if err != nil {
+		return 0, err
+	}
+	if id, ok := record["CustomFieldData"].(float64); ok && id > 0 {
+		_, err := client.Resource("CustomFieldData").Update(ctx, int(id), data)
+		return 0, err
+	}
+	created, err := client.Resource("CustomFieldData").Create(ctx, data)
+	if err != nil {
+		return 0, err
+	}
+	id, _ := created["Id"].(float64)
+	return int(id), nil
+}
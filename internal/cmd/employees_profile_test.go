@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmployeesGet_ShowsContactAndAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/supervise/employee/42":
+			_, _ = w.Write([]byte(`{"Id":42,"DisplayName":"Jane Doe","Company":1,"Role":50,"PayrollId":"P42","Contact":7,"MainAddress":8}`))
+		case "/api/v1/resource/Contact/7":
+			_, _ = w.Write([]byte(`{"Id":7,"Phone1":"0298765432","Email1":"","Modified":"2024-01-01"}`))
+		case "/api/v1/resource/Address/8":
+			_, _ = w.Write([]byte(`{"Id":8,"Street1":"1 Main St","City":"Sydney"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "employees", "get", "42", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Payroll ID: P42\n")
+	assert.Contains(t, out, "\nContact:\n  Phone1: 0298765432\n\nAddress:\n  City: Sydney\n  Street1: 1 Main St\n")
+
+	out, _, err = runRoot(t, server, "", "employees", "get", "42", "-o", "json")
+	require.NoError(t, err)
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	assert.Equal(t, 42.0, parsed["Id"])
+	assert.Equal(t, 7.0, parsed["Contact"])
+	assert.Equal(t, "Sydney", parsed["MainAddressObject"].(map[string]interface{})["City"])
+}
+
+func TestEmployeesGet_JoinFailureIsAWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/supervise/employee/42":
+			_, _ = w.Write([]byte(`{"Id":42,"DisplayName":"Jane Doe","Contact":7,"MainAddress":8}`))
+		case "/api/v1/resource/Contact/7":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"message":"Not allowed"}}`))
+		case "/api/v1/resource/Address/8":
+			_, _ = w.Write([]byte(`{"Id":8,"City":"Sydney"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	out, errOut, err := runRoot(t, server, "", "employees", "get", "42", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Name:       Jane Doe\n")
+	assert.NotContains(t, out, "Contact:")
+	assert.Contains(t, out, "\nAddress:\n  City: Sydney\n")
+	assert.Contains(t, errOut, "warning: could not load contact 7:")
+}
+
+func TestEmployeesUpdate_SetAndCustomFields(t *testing.T) {
+	var writes []string
+	bodies := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		call := r.Method + " " + r.URL.Path
+		switch call {
+		case "GET /api/v1/resource/Employee/INFO":
+			_, _ = w.Write([]byte(`{"name":"Employee","fields":{"Id":"Integer","Role":"Integer","DateOfBirth":"Date","Position":"VarChar","CustomFieldData":"Integer","Modified":"DateTime"}}`))
+			return
+		case "GET /api/v1/resource/CustomField":
+			_, _ = w.Write([]byte(`[{"Id":1,"Name":"Shirt size","ApiName":"shirt_size","DeputyField":"f01"},{"Id":2,"Name":"Locker number","DeputyField":"f02"}]`))
+			return
+		case "GET /api/v1/resource/Employee/42":
+			_, _ = w.Write([]byte(`{"Id":42,"CustomFieldData":null}`))
+			return
+		case "GET /api/v1/supervise/employee/42":
+			_, _ = w.Write([]byte(`{"Id":42,"DisplayName":"Jane Doe"}`))
+			return
+		}
+		writes = append(writes, call)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies[call] = body
+		switch call {
+		case "POST /api/v1/resource/CustomFieldData":
+			_, _ = w.Write([]byte(`{"Id":300}`))
+		case "POST /api/v1/resource/Employee/42":
+			_, _ = w.Write([]byte(`{"Id":42}`))
+		default:
+			t.Errorf("unexpected request %s", call)
+		}
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "employees", "update", "42",
+		"--set", "role=50", "--set", "DateOfBirth=1990-04-01",
+		"--custom", "SHIRT_SIZE=M", "--custom", "Locker number=12", "-o", "text")
+	require.NoError(t, err)
+	assert.Equal(t, "Updated employee 42: Jane Doe\n", out)
+
+	// Custom values go to a new CustomFieldData record, which is linked in
+	// the same Employee update as the --set fields.
+	assert.Equal(t, []string{"POST /api/v1/resource/CustomFieldData", "POST /api/v1/resource/Employee/42"}, writes)
+	assert.Equal(t, map[string]interface{}{"f01": "M", "f02": "12"}, bodies["POST /api/v1/resource/CustomFieldData"])
+	assert.Equal(t, map[string]interface{}{"Role": 50.0, "DateOfBirth": "1990-04-01", "CustomFieldData": 300.0}, bodies["POST /api/v1/resource/Employee/42"])
+}
+
+func TestEmployeesUpdate_RejectsBadFieldsBeforeWriting(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/resource/Employee/INFO":
+			_, _ = w.Write([]byte(`{"name":"Employee","fields":{"Id":"Integer","Role":"Integer","Modified":"DateTime"}}`))
+		case "GET /api/v1/resource/CustomField":
+			_, _ = w.Write([]byte(`[{"Id":1,"Name":"Shirt size","ApiName":"shirt_size","DeputyField":"f01"}]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "", "employees", "update", "42", "--first-name", "Jo", "--set", "Role=abc")
+	require.EqualError(t, err, `Role must be an integer, got "abc"`)
+
+	_, _, err = runRoot(t, server, "", "employees", "update", "42", "--set", "Modified=1", "--set", "Shoe=9")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Modified cannot be set")
+	assert.Contains(t, err.Error(), `unknown field "Shoe"`)
+
+	_, _, err = runRoot(t, server, "", "employees", "update", "42", "--custom", "hat=L")
+	require.EqualError(t, err, `unknown custom field "hat" (available: shirt_size)`)
+}
@@ -857,6 +857,33 @@ func TestEmployeesUpdateCommand_WithMockClient(t *testing.T) {
 		assert.Contains(t, buf.String(), "Updated employee 123: Updated Name")
 	})
 
+	t.Run("update sends payroll ID", func(t *testing.T) {
+		var body map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(api.Employee{Id: 123, DisplayName: "Ann Lee", PayrollID: "P-123"})
+		}))
+		defer server.Close()
+
+		client := newTestClient(server.URL, "test-token")
+		mockFactory := &MockClientFactory{client: client}
+
+		buf := &bytes.Buffer{}
+		ctx := WithClientFactory(context.Background(), mockFactory)
+		ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+		cmd := newEmployeesUpdateCmd()
+		cmd.SetContext(ctx)
+		cmd.SetOut(buf)
+		cmd.SetArgs([]string{"123", "--payroll-id", "P-123"})
+		err := cmd.Execute()
+
+		require.NoError(t, err)
+		assert.Equal(t, "P-123", body["strPayrollId"])
+		assert.Contains(t, buf.String(), "Updated employee 123: Ann Lee")
+	})
+
 	t.Run("update returns JSON in json mode", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -969,6 +996,9 @@ func TestEmployeesAssignLocationCommand_WithMockClient(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
+			if r.URL.Path == "/api/v1/resource/EmployeeWorkplace/QUERY" {
+				_, _ = w.Write([]byte(`[]`))
+			}
 		}))
 		defer server.Close()
 
@@ -1024,6 +1054,9 @@ func TestEmployeesRemoveLocationCommand_WithMockClient(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			w.WriteHeader(http.StatusOK)
+			if r.URL.Path == "/api/v1/resource/EmployeeWorkplace/QUERY" {
+				_, _ = w.Write([]byte(`[]`))
+			}
 		}))
 		defer server.Close()
 
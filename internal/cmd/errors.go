@@ -40,6 +40,9 @@ func FormatError(err error, debug bool) string {
 	}
 
 	msg := err.Error()
+	if errors.Is(err, ErrFindings) {
+		return msg
+	}
 	if strings.Contains(msg, "invalid jq query") {
 		return msg + "\nHint: Check the --query expression or drop it. Use -o json for machine output."
 	}
@@ -69,6 +72,18 @@ func FormatErrorJSON(err error) string {
 		return ""
 	}
 
+	jsonErr := JSONError{Error: errorDetail(err)}
+	data, err := json.Marshal(jsonErr)
+	if err != nil {
+		// Fallback for marshaling failure (should never happen with this struct)
+		return fmt.Sprintf(`{"error":{"code":"INTERNAL_ERROR","message":%q}}`, err.Error())
+	}
+	return string(data)
+}
+
+// errorDetail classifies err the same way for top-level errors and per-item
+// results of bulk commands.
+func errorDetail(err error) JSONErrorDetail {
 	detail := JSONErrorDetail{
 		Code:    api.ErrCodeInvalidInput,
 		Message: err.Error(),
@@ -89,6 +104,8 @@ func FormatErrorJSON(err error) string {
 	} else {
 		msg := err.Error()
 		switch {
+		case errors.Is(err, ErrFindings):
+			detail.Code = "FINDINGS"
 		case strings.Contains(msg, "invalid jq query"):
 			detail.Code = api.ErrCodeInvalidInput
 			detail.Hint = "Check the --query expression"
@@ -105,14 +122,7 @@ func FormatErrorJSON(err error) string {
 			detail.Hint = "Request timed out, retry"
 		}
 	}
-
-	jsonErr := JSONError{Error: detail}
-	data, err := json.Marshal(jsonErr)
-	if err != nil {
-		// Fallback for marshaling failure (should never happen with this struct)
-		return fmt.Sprintf(`{"error":{"code":"INTERNAL_ERROR","message":%q}}`, err.Error())
-	}
-	return string(data)
+	return detail
 }
 
 // hintForStatus returns a human-readable hint for the given HTTP status code.
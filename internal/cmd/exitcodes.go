@@ -17,14 +17,24 @@ const (
 	ExitNotFound   = 4
 	ExitRateLimit  = 5
 	ExitTempError  = 6
+	ExitFindings   = 7
 )
 
+// ErrFindings is returned by checks such as `timesheets audit` after their
+// report has been printed, when they found problems at or above the
+// requested severity.
+var ErrFindings = errors.New("problems found")
+
 // ExitCodeFromError maps an error to a stable exit code.
 func ExitCodeFromError(err error) int {
 	if err == nil {
 		return ExitOK
 	}
 
+	if errors.Is(err, ErrFindings) {
+		return ExitFindings
+	}
+
 	// ErrEmptyResult shares ExitNotFound (4): "no results" is treated the
 	// same as "resource not found" for agent consumption.
 	if errors.Is(err, outfmt.ErrEmptyResult) {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/salmonumbrella/deputy-cli/internal/api"
@@ -36,6 +37,7 @@ func TestExitCodeFromError(t *testing.T) {
 		{"timeout plain", errors.New("context deadline exceeded (timeout)"), 6},
 		{"empty code with status 401", &api.APIError{Code: "", StatusCode: 401, Message: "unauthorized"}, 3},
 		{"empty result sentinel", outfmt.ErrEmptyResult, 4},
+		{"findings sentinel", fmt.Errorf("%w: 2 errors", ErrFindings), 7},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,21 +1,62 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 
 	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/audit"
 	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
 	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
 	"github.com/salmonumbrella/deputy-cli/internal/secrets"
 )
 
+// parseRelativeDuration parses a duration that may also use days (d) and
+// weeks (w), such as "7d", "2w" or "36h".
+func parseRelativeDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		count, err := strconv.Atoi(s[:n-1])
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		day := 24 * time.Hour
+		if s[n-1] == 'w' {
+			day *= 7
+		}
+		return time.Duration(count) * day, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// parseSinceFlag accepts either a YYYY-MM-DD date (midnight local time) or a
+// duration ago such as "7d" or "24h".
+func parseSinceFlag(val, flagName string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", val, time.Local); err == nil {
+		return t, nil
+	}
+	d, err := parseRelativeDuration(val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q: expected YYYY-MM-DD or a duration like 7d or 24h", flagName, val)
+	}
+	return time.Now().Add(-d), nil
+}
+
 // validateDateFormat validates that a date string is in YYYY-MM-DD format.
 func validateDateFormat(dateStr string) error {
 	_, err := time.Parse("2006-01-02", dateStr)
@@ -85,6 +126,82 @@ func DebugFromContext(ctx context.Context) bool {
 	return false
 }
 
+// Context key for the --dry-run request recorder.
+type dryRunKey struct{}
+
+// WithDryRun stores the dry-run recorder in context. Clients created from the
+// context capture mutating requests into it instead of sending them.
+func WithDryRun(ctx context.Context, plan *api.DryRun) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, plan)
+}
+
+// DryRunFromContext returns the dry-run recorder, or nil when requests
+// should be sent normally.
+func DryRunFromContext(ctx context.Context) *api.DryRun {
+	if plan, ok := ctx.Value(dryRunKey{}).(*api.DryRun); ok {
+		return plan
+	}
+	return nil
+}
+
+type auditKey struct{}
+
+// WithAudit stores the audit recorder in context. Clients created from the
+// context append every mutating request they send to the audit log.
+func WithAudit(ctx context.Context, rec *audit.Recorder) context.Context {
+	return context.WithValue(ctx, auditKey{}, rec)
+}
+
+// AuditFromContext returns the audit recorder, or nil when requests are not
+// being audited.
+func AuditFromContext(ctx context.Context) *audit.Recorder {
+	if rec, ok := ctx.Value(auditKey{}).(*audit.Recorder); ok {
+		return rec
+	}
+	return nil
+}
+
+type rateLimiterKey struct{}
+
+// WithRateLimiter stores the limiter shared by every client created from the
+// context, so all requests of one invocation draw from the same budget.
+func WithRateLimiter(ctx context.Context, l *api.RateLimiter) context.Context {
+	return context.WithValue(ctx, rateLimiterKey{}, l)
+}
+
+// RateLimiterFromContext returns the shared limiter, or nil.
+func RateLimiterFromContext(ctx context.Context) *api.RateLimiter {
+	if l, ok := ctx.Value(rateLimiterKey{}).(*api.RateLimiter); ok {
+		return l
+	}
+	return nil
+}
+
+// parseRateLimit parses --rate / DEPUTY_RATE_LIMIT: requests per second as
+// "5" or "5/s", or per minute as "300/m". "0" means unlimited.
+func parseRateLimit(s string) (float64, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if s == "" {
+		return 0, nil
+	}
+	per := 1.0
+	if n, unit, ok := strings.Cut(s, "/"); ok {
+		switch unit {
+		case "s", "sec", "second":
+		case "m", "min", "minute":
+			per = 60
+		default:
+			return 0, fmt.Errorf("invalid rate %q (expected N, N/s or N/m)", s)
+		}
+		s = n
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid rate %q (expected N, N/s or N/m)", s)
+	}
+	return n / per, nil
+}
+
 // Context key for "no keychain" mode.
 type noKeychainKey struct{}
 
@@ -141,6 +258,13 @@ func getClientFromContext(ctx context.Context) (*api.Client, error) {
 		return nil, err
 	}
 	client.SetDebug(DebugFromContext(ctx))
+	client.SetDryRun(DryRunFromContext(ctx))
+	if rec := AuditFromContext(ctx); rec != nil {
+		client.SetRequestObserver(rec.Observer(audit.ProfileFromBaseURL(client.BaseURL())))
+	}
+	if l := RateLimiterFromContext(ctx); l != nil {
+		client.SetRateLimiter(l)
+	}
 	return client, nil
 }
 
@@ -216,8 +340,14 @@ func applyPagination[T any](items []T, offset, limit int) []T {
 // It auto-confirms (returns nil) if:
 // - yes flag is true (--yes/-y was passed)
 // - output format is JSON (programmatic/AI agent mode)
+// - --dry-run is set (no request will be sent)
 // Otherwise, it prompts the user and returns an error if they don't confirm.
 func confirmDestructive(ctx context.Context, yes bool, promptMsg string) error {
+	// Nothing is sent in dry-run mode, so there is nothing to confirm
+	if DryRunFromContext(ctx) != nil {
+		return nil
+	}
+
 	// Auto-confirm in JSON output mode (AI agent automation)
 	format := outfmt.GetFormat(ctx)
 	if format == "json" {
@@ -239,3 +369,77 @@ func confirmDestructive(ctx context.Context, yes bool, promptMsg string) error {
 	}
 	return nil
 }
+
+// datePart trims a Deputy date-time to YYYY-MM-DD.
+func datePart(s string) string {
+	if len(s) > len("2006-01-02") {
+		return s[:len("2006-01-02")]
+	}
+	return s
+}
+
+// locationTimezone loads the IANA timezone configured for a location so that
+// wall-clock times can be converted to Unix timestamps the way Deputy expects.
+// Locations without a usable timezone fall back to the local timezone.
+func locationTimezone(ctx context.Context, client *api.Client, locationID int) (*time.Location, error) {
+	location, err := client.Locations().Get(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	if location.Timezone == "" {
+		return time.Local, nil
+	}
+	tz, err := time.LoadLocation(location.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("location %d has unknown timezone %q", locationID, location.Timezone)
+	}
+	return tz, nil
+}
+
+// forEachConcurrent calls fn for every index in [0, n) using at most workers
+// goroutines. It returns once all calls have finished. fn is responsible for
+// recording its own result; indexes make that safe without extra locking.
+func forEachConcurrent(n, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// readStrictYAML decodes the YAML file at path ("-" reads stdin) into v and
+// validates it. Unknown keys are rejected so typos don't silently become
+// no-ops.
+func readStrictYAML(path string, stdin io.Reader, v interface{ validate() error }) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := v.validate(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
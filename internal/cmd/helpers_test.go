@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -667,3 +669,89 @@ func TestFormatArgNames(t *testing.T) {
 		assert.Equal(t, "", result)
 	})
 }
+
+func TestForEachConcurrent_BoundsWorkers(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	done := make([]bool, 20)
+
+	forEachConcurrent(len(done), 3, func(i int) {
+		mu.Lock()
+		inFlight++
+		if inFlight > peak {
+			peak = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		done[i] = true
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	})
+
+	assert.LessOrEqual(t, peak, 3)
+	for i, ok := range done {
+		assert.True(t, ok, "index %d not processed", i)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		err  bool
+	}{
+		{"", 0, false},
+		{"0", 0, false},
+		{"5", 5, false},
+		{"2.5/s", 2.5, false},
+		{"300/m", 5, false},
+		{"300/min", 5, false},
+		{"5/h", 0, true},
+		{"fast", 0, true},
+		{"-1", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseRateLimit(tt.in)
+		if tt.err {
+			assert.Error(t, err, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestRootCmd_SharesRateLimiterAcrossClients(t *testing.T) {
+	t.Setenv("DEPUTY_RATE_LIMIT", "120/m")
+	var seen []*api.RateLimiter
+	errOut := &bytes.Buffer{}
+	ctx := iocontext.WithIO(context.Background(), &iocontext.IO{Out: &bytes.Buffer{}, ErrOut: errOut})
+
+	root := NewRootCmd()
+	root.AddCommand(&cobra.Command{
+		Use: "probe",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for i := 0; i < 2; i++ {
+				client, err := getClientFromContext(cmd.Context())
+				if err != nil {
+					return err
+				}
+				seen = append(seen, client.RateLimiter())
+			}
+			return nil
+		},
+	})
+	ctx = WithClientFactory(ctx, &MockClientFactory{client: newTestClient("http://127.0.0.1", "test-token")})
+	root.SetArgs([]string{"probe", "--debug", "--max-in-flight", "3"})
+	require.NoError(t, root.ExecuteContext(ctx))
+
+	require.Len(t, seen, 2)
+	require.NotNil(t, seen[0])
+	assert.Same(t, seen[0], seen[1])
+	assert.Contains(t, errOut.String(), "Debug: rate limiter: rate 2/s, max in flight 3")
+
+	root = NewRootCmd()
+	root.SetArgs([]string{"version", "--rate", "lots"})
+	assert.EqualError(t, root.ExecuteContext(ctx), `invalid rate "lots" (expected N, N/s or N/m)`)
+}
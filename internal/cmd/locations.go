@@ -378,9 +378,25 @@ Example:
 				return err
 			}
 
+			current, snapped := snapshotForUndo(cmd.Context(), fmt.Sprintf("location %d settings", id), func() (*api.LocationSettings, error) {
+				return client.Locations().GetSettings(cmd.Context(), id)
+			})
+
 			if err := client.Locations().UpdateSettings(cmd.Context(), id, settings); err != nil {
 				return err
 			}
+			if snapped {
+				before := locationSettingsSnapshot{Settings: map[string]interface{}{}}
+				for key := range settings {
+					if value, ok := current.Settings[key]; ok {
+						before.Settings[key] = value
+					} else {
+						before.Added = append(before.Added, key)
+					}
+				}
+				sort.Strings(before.Added)
+				recordUndo(cmd.Context(), client, undoLocationSettings, id, fmt.Sprintf("update %d setting(s) for location %d", len(settings), id), before)
+			}
 
 			io := iocontext.FromContext(cmd.Context())
 			_, _ = fmt.Fprintf(io.Out, "Updated settings for location %d\n", id)
@@ -243,6 +243,11 @@ func TestLocationsSettingsCommand_JSON(t *testing.T) {
 
 func TestLocationsSettingsUpdateCommand_WithMockClient(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// Snapshot for undo.
+			_ = json.NewEncoder(w).Encode(api.LocationSettings{Id: 123, Settings: map[string]interface{}{}})
+			return
+		}
 		assert.Equal(t, http.MethodPost, r.Method)
 		assert.Equal(t, "/api/v1/supervise/location/123/settings", r.URL.Path)
 		w.WriteHeader(http.StatusOK)
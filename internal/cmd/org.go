@@ -0,0 +1,974 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// OrgSpec is the desired org structure read by `deputy plan` and
+// `deputy apply`. Locations are matched to live ones by code (or name when no
+// code is given), departments by code or name within their location, and
+// webhooks by topic and URL.
+type OrgSpec struct {
+	Locations []OrgLocation `yaml:"locations"`
+	Webhooks  []OrgWebhook  `yaml:"webhooks"`
+}
+
+// OrgLocation describes a Company (location) and what belongs to it.
+type OrgLocation struct {
+	Code        string                 `yaml:"code"`
+	Name        string                 `yaml:"name"`
+	Address     string                 `yaml:"address"`
+	Timezone    string                 `yaml:"timezone"`
+	Settings    map[string]interface{} `yaml:"settings"`
+	Departments []OrgDepartment        `yaml:"departments"`
+}
+
+// OrgDepartment describes an OperationalUnit. Parent names another
+// department of the same location by code or name.
+type OrgDepartment struct {
+	Code      string `yaml:"code"`
+	Name      string `yaml:"name"`
+	Parent    string `yaml:"parent"`
+	SortOrder int    `yaml:"sortOrder"`
+}
+
+// OrgWebhook describes a webhook subscription.
+type OrgWebhook struct {
+	Topic   string `yaml:"topic"`
+	URL     string `yaml:"url"`
+	Type    string `yaml:"type"`
+	Enabled *bool  `yaml:"enabled"`
+}
+
+func (l OrgLocation) key() string {
+	if l.Code != "" {
+		return l.Code
+	}
+	return l.Name
+}
+
+func (d OrgDepartment) key() string {
+	if d.Code != "" {
+		return d.Code
+	}
+	return d.Name
+}
+
+func (w OrgWebhook) enabled() bool {
+	return w.Enabled == nil || *w.Enabled
+}
+
+func (s *OrgSpec) validate() error {
+	seenLocations := map[string]bool{}
+	for i, l := range s.Locations {
+		if l.Name == "" {
+			return fmt.Errorf("locations[%d]: name is required", i)
+		}
+		key := strings.ToLower(l.key())
+		if seenLocations[key] {
+			return fmt.Errorf("location %q is listed more than once", l.key())
+		}
+		seenLocations[key] = true
+
+		seenDepartments := map[string]bool{}
+		for j, d := range l.Departments {
+			if d.Name == "" {
+				return fmt.Errorf("location %q departments[%d]: name is required", l.key(), j)
+			}
+			dkey := strings.ToLower(d.key())
+			if seenDepartments[dkey] {
+				return fmt.Errorf("location %q: department %q is listed more than once", l.key(), d.key())
+			}
+			seenDepartments[dkey] = true
+		}
+		if _, err := orderDepartments(l); err != nil {
+			return fmt.Errorf("location %q: %w", l.key(), err)
+		}
+	}
+
+	seenWebhooks := map[string]bool{}
+	for i, w := range s.Webhooks {
+		if w.Topic == "" || w.URL == "" {
+			return fmt.Errorf("webhooks[%d]: topic and url are required", i)
+		}
+		key := webhookKey(w.Topic, w.URL)
+		if seenWebhooks[key] {
+			return fmt.Errorf("webhook %s %s is listed more than once", w.Topic, w.URL)
+		}
+		seenWebhooks[key] = true
+	}
+	return nil
+}
+
+// orderDepartments returns a location's departments with every parent before
+// its children.
+func orderDepartments(l OrgLocation) ([]OrgDepartment, error) {
+	byKey := make(map[string]OrgDepartment, len(l.Departments))
+	for _, d := range l.Departments {
+		byKey[strings.ToLower(d.key())] = d
+	}
+	lookupParent := func(d OrgDepartment) (OrgDepartment, bool, error) {
+		if d.Parent == "" {
+			return OrgDepartment{}, false, nil
+		}
+		if p, ok := byKey[strings.ToLower(d.Parent)]; ok {
+			return p, true, nil
+		}
+		for _, p := range l.Departments {
+			if strings.EqualFold(p.Name, d.Parent) {
+				return p, true, nil
+			}
+		}
+		return OrgDepartment{}, false, fmt.Errorf("department %q has unknown parent %q", d.key(), d.Parent)
+	}
+
+	const (
+		visiting = iota + 1
+		done
+	)
+	state := map[string]int{}
+	var ordered []OrgDepartment
+	var visit func(d OrgDepartment) error
+	visit = func(d OrgDepartment) error {
+		key := strings.ToLower(d.key())
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("department %q is its own ancestor", d.key())
+		case done:
+			return nil
+		}
+		state[key] = visiting
+		parent, ok, err := lookupParent(d)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+		state[key] = done
+		ordered = append(ordered, d)
+		return nil
+	}
+	for _, d := range l.Departments {
+		if err := visit(d); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+func webhookKey(topic, url string) string {
+	return strings.ToLower(topic) + " " + url
+}
+
+// Plan actions, in the terraform sense.
+const (
+	orgCreate  = "create"
+	orgUpdate  = "update"
+	orgReplace = "replace"
+	orgArchive = "archive"
+	orgDelete  = "delete"
+)
+
+// orgFieldChange is one attribute that differs between the file and Deputy.
+type orgFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// orgChange is one step of a plan. Steps are kept in the order they must be
+// applied: locations, their settings and departments (parents first) and
+// webhooks, then archives and deletes, children first.
+type orgChange struct {
+	Action  string           `json:"action"`
+	Kind    string           `json:"kind"`
+	Address string           `json:"address"`
+	ID      int              `json:"id,omitempty"`
+	Fields  []orgFieldChange `json:"fields,omitempty"`
+
+	apply func(ctx context.Context, client *api.Client, ids *orgIDs) (int, error)
+}
+
+// orgIDs resolves file keys to Deputy IDs while a plan is applied, so
+// departments can reference locations and parents created earlier in the run.
+type orgIDs struct {
+	locations   map[string]int
+	departments map[string]int
+}
+
+func (ids *orgIDs) departmentKey(location, department string) string {
+	return strings.ToLower(location) + "/" + strings.ToLower(department)
+}
+
+type orgPlan struct {
+	Changes []orgChange
+	ids     *orgIDs
+}
+
+func (p *orgPlan) add(c orgChange) {
+	p.Changes = append(p.Changes, c)
+}
+
+// counts tallies changes by action.
+func (p *orgPlan) counts() map[string]int {
+	counts := map[string]int{orgCreate: 0, orgUpdate: 0, orgReplace: 0, orgArchive: 0, orgDelete: 0}
+	for _, c := range p.Changes {
+		counts[c.Action]++
+	}
+	return counts
+}
+
+// buildOrgPlan diffs spec against live state. With prune, live locations,
+// departments and webhooks missing from the file are archived or deleted.
+func buildOrgPlan(ctx context.Context, client *api.Client, spec *OrgSpec, prune bool) (*orgPlan, error) {
+	plan := &orgPlan{ids: &orgIDs{locations: map[string]int{}, departments: map[string]int{}}}
+
+	liveLocations, err := client.Locations().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	liveDepartments, err := client.Departments().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	matchedLocations := map[int]bool{}
+	matchedDepartments := map[int]bool{}
+	for _, want := range spec.Locations {
+		live := matchLocation(liveLocations, want)
+		if live != nil {
+			matchedLocations[live.Id] = true
+			full, err := client.Locations().Get(ctx, live.Id)
+			if err != nil {
+				return nil, err
+			}
+			plan.ids.locations[strings.ToLower(want.key())] = full.Id
+			planLocationUpdate(plan, want, full)
+			if len(want.Settings) > 0 {
+				current, err := client.Locations().GetSettings(ctx, full.Id)
+				if err != nil {
+					return nil, err
+				}
+				planLocationSettings(plan, want, full.Id, current.Settings)
+			}
+		} else {
+			planLocationCreate(plan, want)
+			if len(want.Settings) > 0 {
+				planLocationSettings(plan, want, 0, nil)
+			}
+		}
+
+		var locationDepartments []api.Department
+		if live != nil {
+			for _, d := range liveDepartments {
+				if d.Company == live.Id {
+					locationDepartments = append(locationDepartments, d)
+				}
+			}
+		}
+		ordered, err := orderDepartments(want)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range ordered {
+			if existing := matchDepartment(locationDepartments, d); existing != nil {
+				matchedDepartments[existing.Id] = true
+				plan.ids.departments[plan.ids.departmentKey(want.key(), d.key())] = existing.Id
+				planDepartmentUpdate(plan, want, d, existing)
+			} else {
+				planDepartmentCreate(plan, want, d)
+			}
+		}
+	}
+
+	liveWebhooks, err := client.Webhooks().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	matchedWebhooks := map[int]bool{}
+	for _, want := range spec.Webhooks {
+		existing := matchWebhook(liveWebhooks, want)
+		if existing != nil {
+			matchedWebhooks[existing.Id] = true
+		}
+		planWebhook(plan, want, existing)
+	}
+
+	if prune {
+		// Archive departments deepest first so children go before parents.
+		depth := departmentDepths(liveDepartments)
+		var stale []api.Department
+		for _, d := range liveDepartments {
+			if d.Active && !matchedDepartments[d.Id] && matchedLocations[d.Company] {
+				stale = append(stale, d)
+			}
+		}
+		sort.SliceStable(stale, func(i, j int) bool { return depth[stale[i].Id] > depth[stale[j].Id] })
+		for _, d := range stale {
+			planDepartmentArchive(plan, liveLocations, d)
+		}
+		for _, l := range liveLocations {
+			if l.Active && !matchedLocations[l.Id] {
+				planLocationArchive(plan, l)
+			}
+		}
+		for _, w := range liveWebhooks {
+			if !matchedWebhooks[w.Id] {
+				planWebhookDelete(plan, w)
+			}
+		}
+	}
+	return plan, nil
+}
+
+func matchLocation(live []api.Location, want OrgLocation) *api.Location {
+	for i, l := range live {
+		if want.Code != "" {
+			if strings.EqualFold(l.Code, want.Code) || strings.EqualFold(l.CompanyCode, want.Code) {
+				return &live[i]
+			}
+		} else if strings.EqualFold(l.CompanyName, want.Name) {
+			return &live[i]
+		}
+	}
+	return nil
+}
+
+func matchDepartment(live []api.Department, want OrgDepartment) *api.Department {
+	for i, d := range live {
+		if want.Code != "" {
+			if strings.EqualFold(d.CompanyCode, want.Code) {
+				return &live[i]
+			}
+		} else if strings.EqualFold(d.CompanyName, want.Name) {
+			return &live[i]
+		}
+	}
+	return nil
+}
+
+func matchWebhook(live []api.Webhook, want OrgWebhook) *api.Webhook {
+	key := webhookKey(want.Topic, want.URL)
+	for i, w := range live {
+		if webhookKey(w.Topic, w.Url) == key {
+			return &live[i]
+		}
+	}
+	return nil
+}
+
+func departmentDepths(departments []api.Department) map[int]int {
+	parent := make(map[int]int, len(departments))
+	for _, d := range departments {
+		parent[d.Id] = d.ParentId
+	}
+	depth := make(map[int]int, len(departments))
+	for _, d := range departments {
+		n := 0
+		for p := parent[d.Id]; p != 0 && n < len(departments); p = parent[p] {
+			n++
+		}
+		depth[d.Id] = n
+	}
+	return depth
+}
+
+func locationAddress(l OrgLocation) string {
+	return fmt.Sprintf("location %q", l.key())
+}
+
+func departmentAddress(l OrgLocation, d OrgDepartment) string {
+	return fmt.Sprintf("department %q", l.key()+"/"+d.key())
+}
+
+func planLocationCreate(plan *orgPlan, want OrgLocation) {
+	fields := []orgFieldChange{{Field: "name", New: orgValue(want.Name)}}
+	if want.Code != "" {
+		fields = append(fields, orgFieldChange{Field: "code", New: orgValue(want.Code)})
+	}
+	if want.Address != "" {
+		fields = append(fields, orgFieldChange{Field: "address", New: orgValue(want.Address)})
+	}
+	if want.Timezone != "" {
+		fields = append(fields, orgFieldChange{Field: "timezone", New: orgValue(want.Timezone)})
+	}
+	plan.add(orgChange{
+		Action:  orgCreate,
+		Kind:    "location",
+		Address: locationAddress(want),
+		Fields:  fields,
+		apply: func(ctx context.Context, client *api.Client, ids *orgIDs) (int, error) {
+			created, err := client.Locations().Create(ctx, &api.CreateLocationInput{
+				CompanyName: want.Name,
+				Code:        want.Code,
+				Address:     want.Address,
+				Timezone:    want.Timezone,
+			})
+			if err != nil {
+				return 0, err
+			}
+			ids.locations[strings.ToLower(want.key())] = created.Id
+			return created.Id, nil
+		},
+	})
+}
+
+func planLocationUpdate(plan *orgPlan, want OrgLocation, live *api.Location) {
+	var fields []orgFieldChange
+	input := &api.UpdateLocationInput{}
+	if want.Name != live.CompanyName {
+		fields = append(fields, orgFieldChange{Field: "name", Old: orgValue(live.CompanyName), New: orgValue(want.Name)})
+		input.CompanyName = want.Name
+	}
+	if want.Code != "" && want.Code != live.Code && want.Code != live.CompanyCode {
+		fields = append(fields, orgFieldChange{Field: "code", Old: orgValue(live.Code), New: orgValue(want.Code)})
+		input.Code = want.Code
+	}
+	// Address may be a reference to an Address record; only plain strings
+	// can be compared.
+	var liveAddress string
+	if want.Address != "" && json.Unmarshal(live.Address, &liveAddress) == nil && want.Address != liveAddress {
+		fields = append(fields, orgFieldChange{Field: "address", Old: orgValue(liveAddress), New: orgValue(want.Address)})
+		input.Address = want.Address
+	}
+	if want.Timezone != "" && want.Timezone != live.Timezone {
+		fields = append(fields, orgFieldChange{Field: "timezone", Old: orgValue(live.Timezone), New: orgValue(want.Timezone)})
+		input.Timezone = want.Timezone
+	}
+	if len(fields) == 0 {
+		return
+	}
+	id := live.Id
+	plan.add(orgChange{
+		Action:  orgUpdate,
+		Kind:    "location",
+		Address: locationAddress(want),
+		ID:      id,
+		Fields:  fields,
+		apply: func(ctx context.Context, client *api.Client, _ *orgIDs) (int, error) {
+			_, err := client.Locations().Update(ctx, id, input)
+			return id, err
+		},
+	})
+}
+
+func planLocationSettings(plan *orgPlan, want OrgLocation, id int, current map[string]interface{}) {
+	changed := map[string]interface{}{}
+	var fields []orgFieldChange
+	for _, key := range sortedKeys(want.Settings) {
+		desired := normalizeJSONValue(want.Settings[key])
+		old, exists := current[key]
+		if exists && reflect.DeepEqual(normalizeJSONValue(old), desired) {
+			continue
+		}
+		change := orgFieldChange{Field: key, New: orgValue(desired)}
+		if exists {
+			change.Old = orgValue(old)
+		}
+		fields = append(fields, change)
+		changed[key] = desired
+	}
+	if len(fields) == 0 {
+		return
+	}
+	action := orgUpdate
+	if id == 0 {
+		action = orgCreate
+	}
+	plan.add(orgChange{
+		Action:  action,
+		Kind:    "location-settings",
+		Address: fmt.Sprintf("settings %q", want.key()),
+		ID:      id,
+		Fields:  fields,
+		apply: func(ctx context.Context, client *api.Client, ids *orgIDs) (int, error) {
+			locationID := ids.locations[strings.ToLower(want.key())]
+			return locationID, client.Locations().UpdateSettings(ctx, locationID, changed)
+		},
+	})
+}
+
+// departmentParentID resolves a department's parent from the IDs known so
+// far. Parents are always planned first, so they are known when applying.
+func departmentParentID(ids *orgIDs, l OrgLocation, d OrgDepartment) int {
+	if d.Parent == "" {
+		return 0
+	}
+	if id, ok := ids.departments[ids.departmentKey(l.key(), d.Parent)]; ok {
+		return id
+	}
+	for _, p := range l.Departments {
+		if strings.EqualFold(p.Name, d.Parent) {
+			return ids.departments[ids.departmentKey(l.key(), p.key())]
+		}
+	}
+	return 0
+}
+
+func planDepartmentCreate(plan *orgPlan, l OrgLocation, d OrgDepartment) {
+	fields := []orgFieldChange{{Field: "name", New: orgValue(d.Name)}}
+	if d.Code != "" {
+		fields = append(fields, orgFieldChange{Field: "code", New: orgValue(d.Code)})
+	}
+	if d.Parent != "" {
+		fields = append(fields, orgFieldChange{Field: "parent", New: orgValue(d.Parent)})
+	}
+	if d.SortOrder != 0 {
+		fields = append(fields, orgFieldChange{Field: "sortOrder", New: orgValue(d.SortOrder)})
+	}
+	plan.add(orgChange{
+		Action:  orgCreate,
+		Kind:    "department",
+		Address: departmentAddress(l, d),
+		Fields:  fields,
+		apply: func(ctx context.Context, client *api.Client, ids *orgIDs) (int, error) {
+			created, err := client.Departments().Create(ctx, &api.CreateDepartmentInput{
+				Company:     ids.locations[strings.ToLower(l.key())],
+				ParentId:    departmentParentID(ids, l, d),
+				CompanyName: d.Name,
+				CompanyCode: d.Code,
+				SortOrder:   d.SortOrder,
+			})
+			if err != nil {
+				return 0, err
+			}
+			ids.departments[ids.departmentKey(l.key(), d.key())] = created.Id
+			return created.Id, nil
+		},
+	})
+}
+
+func planDepartmentUpdate(plan *orgPlan, l OrgLocation, d OrgDepartment, live *api.Department) {
+	var fields []orgFieldChange
+	input := &api.UpdateDepartmentInput{}
+	if d.Name != live.CompanyName {
+		fields = append(fields, orgFieldChange{Field: "name", Old: orgValue(live.CompanyName), New: orgValue(d.Name)})
+		input.CompanyName = d.Name
+	}
+	if d.Code != "" && d.Code != live.CompanyCode {
+		fields = append(fields, orgFieldChange{Field: "code", Old: orgValue(live.CompanyCode), New: orgValue(d.Code)})
+		input.CompanyCode = d.Code
+	}
+	if d.SortOrder != 0 && d.SortOrder != live.SortOrder {
+		fields = append(fields, orgFieldChange{Field: "sortOrder", Old: orgValue(live.SortOrder), New: orgValue(d.SortOrder)})
+		input.SortOrder = d.SortOrder
+	}
+	if !live.Active {
+		active := true
+		fields = append(fields, orgFieldChange{Field: "active", Old: "false", New: "true"})
+		input.Active = &active
+	}
+	parentID := departmentParentID(plan.ids, l, d)
+	// A parent that is itself about to be created has no ID yet.
+	parentChanged := parentID != live.ParentId || (d.Parent != "" && parentID == 0)
+	if parentChanged {
+		old := "(none)"
+		if live.ParentId != 0 {
+			old = fmt.Sprintf("%d", live.ParentId)
+		}
+		newParent := "(none)"
+		if d.Parent != "" {
+			newParent = orgValue(d.Parent)
+		}
+		fields = append(fields, orgFieldChange{Field: "parent", Old: old, New: newParent})
+	}
+	if len(fields) == 0 {
+		return
+	}
+	id := live.Id
+	plan.add(orgChange{
+		Action:  orgUpdate,
+		Kind:    "department",
+		Address: departmentAddress(l, d),
+		ID:      id,
+		Fields:  fields,
+		apply: func(ctx context.Context, client *api.Client, ids *orgIDs) (int, error) {
+			if parentChanged {
+				parent := departmentParentID(ids, l, d)
+				input.ParentId = &parent
+			}
+			_, err := client.Departments().Update(ctx, id, input)
+			return id, err
+		},
+	})
+}
+
+func planDepartmentArchive(plan *orgPlan, locations []api.Location, d api.Department) {
+	location := fmt.Sprintf("%d", d.Company)
+	for _, l := range locations {
+		if l.Id == d.Company {
+			location = l.Code
+			if location == "" {
+				location = l.CompanyName
+			}
+			break
+		}
+	}
+	name := d.CompanyCode
+	if name == "" {
+		name = d.CompanyName
+	}
+	id := d.Id
+	plan.add(orgChange{
+		Action:  orgArchive,
+		Kind:    "department",
+		Address: fmt.Sprintf("department %q", location+"/"+name),
+		ID:      id,
+		apply: func(ctx context.Context, client *api.Client, _ *orgIDs) (int, error) {
+			active := false
+			_, err := client.Departments().Update(ctx, id, &api.UpdateDepartmentInput{Active: &active})
+			return id, err
+		},
+	})
+}
+
+func planLocationArchive(plan *orgPlan, l api.Location) {
+	name := l.Code
+	if name == "" {
+		name = l.CompanyName
+	}
+	id := l.Id
+	plan.add(orgChange{
+		Action:  orgArchive,
+		Kind:    "location",
+		Address: fmt.Sprintf("location %q", name),
+		ID:      id,
+		apply: func(ctx context.Context, client *api.Client, _ *orgIDs) (int, error) {
+			return id, client.Locations().Archive(ctx, id)
+		},
+	})
+}
+
+func planWebhook(plan *orgPlan, want OrgWebhook, live *api.Webhook) {
+	address := fmt.Sprintf("webhook %q", want.Topic+" "+want.URL)
+	create := func(ctx context.Context, client *api.Client) (int, error) {
+		created, err := client.Webhooks().Create(ctx, &api.CreateWebhookInput{
+			Topic:   want.Topic,
+			Url:     want.URL,
+			Type:    want.Type,
+			Enabled: want.enabled(),
+		})
+		if err != nil {
+			return 0, err
+		}
+		return created.Id, nil
+	}
+
+	if live == nil {
+		fields := []orgFieldChange{
+			{Field: "topic", New: orgValue(want.Topic)},
+			{Field: "url", New: orgValue(want.URL)},
+			{Field: "enabled", New: orgValue(want.enabled())},
+		}
+		if want.Type != "" {
+			fields = append(fields, orgFieldChange{Field: "type", New: orgValue(want.Type)})
+		}
+		plan.add(orgChange{
+			Action:  orgCreate,
+			Kind:    "webhook",
+			Address: address,
+			Fields:  fields,
+			apply: func(ctx context.Context, client *api.Client, _ *orgIDs) (int, error) {
+				return create(ctx, client)
+			},
+		})
+		return
+	}
+
+	// Webhooks cannot be updated in place, so any difference replaces them.
+	var fields []orgFieldChange
+	if want.enabled() != live.Enabled {
+		fields = append(fields, orgFieldChange{Field: "enabled", Old: orgValue(live.Enabled), New: orgValue(want.enabled())})
+	}
+	if want.Type != "" && !strings.EqualFold(want.Type, live.Type) {
+		fields = append(fields, orgFieldChange{Field: "type", Old: orgValue(live.Type), New: orgValue(want.Type)})
+	}
+	if len(fields) == 0 {
+		return
+	}
+	id := live.Id
+	plan.add(orgChange{
+		Action:  orgReplace,
+		Kind:    "webhook",
+		Address: address,
+		ID:      id,
+		Fields:  fields,
+		apply: func(ctx context.Context, client *api.Client, _ *orgIDs) (int, error) {
+			if err := client.Webhooks().Delete(ctx, id); err != nil {
+				return 0, err
+			}
+			return create(ctx, client)
+		},
+	})
+}
+
+func planWebhookDelete(plan *orgPlan, w api.Webhook) {
+	id := w.Id
+	plan.add(orgChange{
+		Action:  orgDelete,
+		Kind:    "webhook",
+		Address: fmt.Sprintf("webhook %q", w.Topic+" "+w.Url),
+		ID:      id,
+		apply: func(ctx context.Context, client *api.Client, _ *orgIDs) (int, error) {
+			return id, client.Webhooks().Delete(ctx, id)
+		},
+	})
+}
+
+// normalizeJSONValue round-trips v through JSON so YAML ints and JSON floats
+// compare equal.
+func normalizeJSONValue(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// orgValue renders a value the way the plan shows it: JSON, so strings are
+// quoted.
+func orgValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+var orgActionSymbols = map[string]string{
+	orgCreate:  "+",
+	orgUpdate:  "~",
+	orgReplace: "-/+",
+	orgArchive: "-",
+	orgDelete:  "-",
+}
+
+// printOrgPlan writes a terraform-style plan.
+func printOrgPlan(w io.Writer, plan *orgPlan, source string) {
+	if len(plan.Changes) == 0 {
+		_, _ = fmt.Fprintf(w, "No changes. Deputy matches %s.\n", source)
+		return
+	}
+	for _, c := range plan.Changes {
+		line := fmt.Sprintf("  %s %s", orgActionSymbols[c.Action], c.Address)
+		if c.ID != 0 {
+			line += fmt.Sprintf(" (id %d)", c.ID)
+		}
+		switch c.Action {
+		case orgArchive, orgDelete:
+			line += " will be " + c.Action + "d"
+		case orgReplace:
+			line += " must be replaced"
+		}
+		_, _ = fmt.Fprintln(w, line)
+
+		width := 0
+		for _, f := range c.Fields {
+			width = max(width, len(f.Field))
+		}
+		for _, f := range c.Fields {
+			if f.Old == "" {
+				_, _ = fmt.Fprintf(w, "      %-*s = %s\n", width, f.Field, f.New)
+			} else {
+				_, _ = fmt.Fprintf(w, "      %-*s = %s -> %s\n", width, f.Field, f.Old, f.New)
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n%s\n", orgSummary("Plan:", plan.counts(), "to "))
+}
+
+func orgSummary(prefix string, counts map[string]int, verbPrefix string) string {
+	parts := []string{}
+	for _, action := range []string{orgCreate, orgUpdate, orgReplace, orgArchive, orgDelete} {
+		if action == orgReplace && counts[action] == 0 {
+			continue
+		}
+		word := action
+		if verbPrefix == "" {
+			word = action + "d"
+		}
+		parts = append(parts, fmt.Sprintf("%d %s%s", counts[action], verbPrefix, word))
+	}
+	return prefix + " " + strings.Join(parts, ", ") + "."
+}
+
+func newPlanCmd() *cobra.Command {
+	var file string
+	var prune bool
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Show how Deputy differs from an org file",
+		Long: `Compare locations, departments, location settings and webhooks described in a
+YAML file against Deputy and show the changes 'deputy apply' would make.
+
+Example org.yaml:
+
+  locations:
+    - code: MST
+      name: Main Street
+      timezone: Australia/Sydney
+      settings:
+        RosterWeekStart: 1
+      departments:
+        - name: Kitchen
+          code: KIT
+        - name: Prep
+          parent: KIT
+  webhooks:
+    - topic: Timesheet.Insert
+      url: https://example.com/hook
+
+Locations match by code (or name), departments by code or name within their
+location, and webhooks by topic and URL. With --prune, active locations and
+departments missing from the file are archived and missing webhooks deleted.`,
+		Example: `  deputy plan -f org.yaml
+  deputy plan -f org.yaml --prune -o json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return errors.New("-f/--file is required")
+			}
+			ctx := cmd.Context()
+			spec := &OrgSpec{}
+			if err := readStrictYAML(file, iocontext.FromContext(ctx).In, spec); err != nil {
+				return err
+			}
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			plan, err := buildOrgPlan(ctx, client, spec, prune)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputWithMeta(plan.Changes, map[string]interface{}{
+					"count":   len(plan.Changes),
+					"summary": plan.counts(),
+					"file":    file,
+				})
+			}
+			printOrgPlan(iocontext.FromContext(ctx).Out, plan, file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Org YAML file, or - for stdin (required)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Archive or delete live records missing from the file")
+
+	return cmd
+}
+
+// orgResult reports one applied change.
+type orgResult struct {
+	Action  string `json:"action"`
+	Kind    string `json:"kind"`
+	Address string `json:"address"`
+	ID      int    `json:"id,omitempty"`
+}
+
+func newApplyCmd() *cobra.Command {
+	var file string
+	var prune, yes bool
+
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Make Deputy match an org file",
+		Long: `Apply the changes 'deputy plan' shows, in dependency order: locations, their
+settings, departments (parents before children) and webhooks, then archives
+and deletes. The plan is shown and confirmed first; --yes skips the prompt.
+If a step fails, apply stops and reports what was already changed.`,
+		Example: `  deputy apply -f org.yaml
+  deputy apply -f org.yaml --prune --yes
+  deputy apply -f org.yaml --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				return errors.New("-f/--file is required")
+			}
+			ctx := cmd.Context()
+			io := iocontext.FromContext(ctx)
+			spec := &OrgSpec{}
+			if err := readStrictYAML(file, io.In, spec); err != nil {
+				return err
+			}
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			plan, err := buildOrgPlan(ctx, client, spec, prune)
+			if err != nil {
+				return err
+			}
+
+			format := outfmt.GetFormat(ctx)
+			if format != "json" {
+				printOrgPlan(io.Out, plan, file)
+			}
+			if len(plan.Changes) > 0 {
+				if err := confirmDestructive(ctx, yes, "Apply these changes?"); err != nil {
+					return err
+				}
+			}
+
+			applied := map[string]int{orgCreate: 0, orgUpdate: 0, orgReplace: 0, orgArchive: 0, orgDelete: 0}
+			results := make([]orgResult, 0, len(plan.Changes))
+			for _, c := range plan.Changes {
+				id, err := c.apply(ctx, client, plan.ids)
+				if err != nil {
+					if format != "json" {
+						_, _ = fmt.Fprintf(io.Out, "%s\n", orgSummary("Stopped after:", applied, ""))
+					}
+					return fmt.Errorf("%s %s: %w", c.Action, c.Address, err)
+				}
+				applied[c.Action]++
+				results = append(results, orgResult{Action: c.Action, Kind: c.Kind, Address: c.Address, ID: id})
+				if format != "json" {
+					_, _ = fmt.Fprintf(io.Out, "%s: %sd", c.Address, c.Action)
+					if id != 0 {
+						_, _ = fmt.Fprintf(io.Out, " (id %d)", id)
+					}
+					_, _ = fmt.Fprintln(io.Out)
+				}
+			}
+
+			if format == "json" {
+				return outfmt.New(ctx).OutputWithMeta(results, map[string]interface{}{
+					"count":   len(results),
+					"summary": applied,
+					"file":    file,
+				})
+			}
+			if len(plan.Changes) > 0 {
+				_, _ = fmt.Fprintf(io.Out, "\n%s\n", orgSummary("Apply complete!", applied, ""))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Org YAML file, or - for stdin (required)")
+	cmd.Flags().BoolVar(&prune, "prune", false, "Archive or delete live records missing from the file")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+func TestOrderDepartments(t *testing.T) {
+	loc := OrgLocation{Name: "Main", Departments: []OrgDepartment{
+		{Name: "Prep", Parent: "Kitchen"},
+		{Name: "Kitchen", Code: "KIT"},
+		{Name: "Cold", Parent: "prep"},
+	}}
+	ordered, err := orderDepartments(loc)
+	require.NoError(t, err)
+	var names []string
+	for _, d := range ordered {
+		names = append(names, d.Name)
+	}
+	assert.Equal(t, []string{"Kitchen", "Prep", "Cold"}, names)
+
+	loc.Departments[1].Parent = "Cold"
+	_, err = orderDepartments(loc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "its own ancestor")
+}
+
+func TestReadOrgSpec_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"unknown key", "locations:\n  - name: A\n    timezon: UTC\n", "timezon"},
+		{"missing name", "locations:\n  - code: A\n", "name is required"},
+		{"duplicate location", "locations:\n  - name: A\n  - name: a\n", "listed more than once"},
+		{"unknown parent", "locations:\n  - name: A\n    departments:\n      - name: B\n        parent: C\n", "unknown parent"},
+		{"webhook without url", "webhooks:\n  - topic: Roster.Insert\n", "topic and url are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := readStrictYAML("-", strings.NewReader(tt.yaml), &OrgSpec{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+// orgTestServer fakes one existing location with two departments and one
+// webhook, and records writes.
+type orgTestServer struct {
+	mu     sync.Mutex
+	writes []string
+	bodies []map[string]interface{}
+}
+
+func (s *orgTestServer) handler() http.Handler {
+	record := func(r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		s.writes = append(s.writes, r.Method+" "+r.URL.Path)
+		s.bodies = append(s.bodies, body)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/supervise/location/simplified", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Location{
+			{Id: 1, CompanyName: "Main Street", Code: "MST", Active: true},
+			{Id: 2, CompanyName: "Old Town", Code: "OLD", Active: true},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/Company/1", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 1, CompanyName: "Main Street", Code: "MST", Timezone: "Australia/Sydney", Address: json.RawMessage(`"1 Main St"`), Active: true})
+	})
+	mux.HandleFunc("/api/v1/supervise/location/1/settings", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			record(r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(api.LocationSettings{Id: 1, Settings: map[string]interface{}{"RosterWeekStart": 1.0, "Other": "x"}})
+	})
+	mux.HandleFunc("/api/v1/resource/OperationalUnit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			record(r)
+			_ = json.NewEncoder(w).Encode(api.Department{Id: 50})
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]api.Department{
+			{Id: 10, Company: 1, CompanyName: "Kitchen", CompanyCode: "KIT", Active: true},
+			{Id: 11, Company: 1, CompanyName: "Bar", CompanyCode: "BAR", Active: true},
+			{Id: 12, Company: 1, ParentId: 11, CompanyName: "Cellar", CompanyCode: "CEL", Active: true},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/OperationalUnit/", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		_ = json.NewEncoder(w).Encode(api.Department{})
+	})
+	mux.HandleFunc("/api/v1/supervise/location", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 40})
+	})
+	mux.HandleFunc("/api/v1/supervise/location/", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		_ = json.NewEncoder(w).Encode(api.Location{})
+	})
+	mux.HandleFunc("/api/v1/resource/Webhook", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			record(r)
+			_ = json.NewEncoder(w).Encode(api.Webhook{Id: 70})
+			return
+		}
+		_ = json.NewEncoder(w).Encode([]api.Webhook{
+			{Id: 7, Topic: "Timesheet.Insert", Url: "https://example.com/ts", Enabled: true},
+			{Id: 8, Topic: "Roster.Publish", Url: "https://example.com/old", Enabled: true},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/Webhook/", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+	})
+	return mux
+}
+
+const testOrgYAML = `locations:
+  - code: MST
+    name: Main Street
+    timezone: Australia/Melbourne
+    settings:
+      RosterWeekStart: 1
+      TimesheetRounding: 15
+    departments:
+      - name: Kitchen
+        code: KIT
+      - name: Prep
+        code: PRP
+        parent: KIT
+  - code: NEW
+    name: New Site
+    departments:
+      - name: Floor
+webhooks:
+  - topic: Timesheet.Insert
+    url: https://example.com/ts
+`
+
+// runOrgYAML writes orgYAML to a file and runs plan or apply against it.
+func runOrgYAML(t *testing.T, server *httptest.Server, orgYAML, stdin string, args ...string) (string, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "org.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(orgYAML), 0o600))
+
+	out, _, err := runRoot(t, server, stdin, append(args, "-f", path)...)
+	return out, err
+}
+
+func TestPlanCommand(t *testing.T) {
+	fake := &orgTestServer{}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	out, err := runOrgYAML(t, server, testOrgYAML, "", "plan", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, `~ location "MST" (id 1)`)
+	assert.Contains(t, out, `timezone = "Australia/Sydney" -> "Australia/Melbourne"`)
+	assert.Contains(t, out, `~ settings "MST" (id 1)`)
+	assert.Contains(t, out, `TimesheetRounding = 15`)
+	assert.NotContains(t, out, "RosterWeekStart")
+	assert.Contains(t, out, `+ department "MST/PRP"`)
+	assert.Contains(t, out, `+ location "NEW"`)
+	assert.Contains(t, out, `+ department "NEW/Floor"`)
+	assert.NotContains(t, out, "webhook")
+	assert.Contains(t, out, "Plan: 3 to create, 2 to update, 0 to archive, 0 to delete.")
+	assert.Empty(t, fake.writes)
+
+	out, err = runOrgYAML(t, server, testOrgYAML, "", "plan", "--prune", "-o", "text")
+	require.NoError(t, err)
+	// Children are archived before their parents.
+	assert.Less(t, strings.Index(out, `"MST/CEL"`), strings.Index(out, `"MST/BAR"`))
+	assert.Contains(t, out, `- location "OLD" (id 2) will be archived`)
+	assert.Contains(t, out, `- webhook "Roster.Publish https://example.com/old" (id 8) will be deleted`)
+	assert.Contains(t, out, "Plan: 3 to create, 2 to update, 3 to archive, 1 to delete.")
+}
+
+func TestApplyCommand_DependencyOrder(t *testing.T) {
+	fake := &orgTestServer{}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	out, err := runOrgYAML(t, server, testOrgYAML, "n\n", "apply", "-o", "text")
+	require.EqualError(t, err, "operation cancelled")
+	assert.Empty(t, fake.writes)
+	assert.Contains(t, out, "Apply these changes?")
+
+	out, err = runOrgYAML(t, server, testOrgYAML, "", "apply", "--yes", "-o", "text")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"PUT /api/v1/supervise/location/1",
+		"POST /api/v1/supervise/location/1/settings",
+		"POST /api/v1/resource/OperationalUnit",
+		"POST /api/v1/supervise/location",
+		"POST /api/v1/resource/OperationalUnit",
+	}, fake.writes)
+	assert.Contains(t, out, "Apply complete! 3 created, 2 updated, 0 archived, 0 deleted.")
+
+	// Prep's parent is the existing Kitchen; Floor belongs to the location
+	// created earlier in the same run.
+	assert.Equal(t, 1.0, fake.bodies[2]["intCompanyId"])
+	assert.Equal(t, 10.0, fake.bodies[2]["intParentId"])
+	assert.Equal(t, 40.0, fake.bodies[4]["intCompanyId"])
+	assert.Equal(t, map[string]interface{}{"TimesheetRounding": 15.0}, fake.bodies[1]["arrSettings"])
+}
+
+func TestApplyCommand_ClearsDepartmentParent(t *testing.T) {
+	fake := &orgTestServer{}
+	server := httptest.NewServer(fake.handler())
+	defer server.Close()
+
+	orgYAML := `locations:
+  - code: MST
+    name: Main Street
+    departments:
+      - name: Kitchen
+        code: KIT
+      - name: Bar
+        code: BAR
+      - name: Cellar
+        code: CEL
+`
+	out, err := runOrgYAML(t, server, orgYAML, "", "apply", "--yes", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, `parent = 11 -> (none)`)
+	require.Equal(t, []string{"POST /api/v1/resource/OperationalUnit/12"}, fake.writes)
+	assert.Equal(t, map[string]interface{}{"intParentId": 0.0}, fake.bodies[0])
+}
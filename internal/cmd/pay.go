@@ -403,10 +403,32 @@ Config should be a JSON string (or provided via --config-file).`,
 				return err
 			}
 
+			current, snapped := snapshotForUndo(cmd.Context(), fmt.Sprintf("agreement %d", agreementID), func() (*api.EmployeeAgreement, error) {
+				return client.Agreements().Get(cmd.Context(), agreementID)
+			})
+
 			agreement, err := client.Agreements().Update(cmd.Context(), agreementID, input)
 			if err != nil {
 				return err
 			}
+			if snapped {
+				var before agreementSnapshot
+				if input.BaseRate != nil {
+					// An agreement without a base rate is restored to 0.
+					rate := 0.0
+					if current.BaseRate != nil {
+						rate = *current.BaseRate
+					}
+					before.BaseRate = &rate
+				}
+				if input.Config != nil {
+					before.Config = current.Config
+					if before.Config == nil {
+						before.Config = json.RawMessage("{}")
+					}
+				}
+				recordUndo(cmd.Context(), client, undoAgreementUpdate, agreementID, fmt.Sprintf("update agreement %d", agreementID), before)
+			}
 
 			format := outfmt.GetFormat(cmd.Context())
 			if format == "json" {
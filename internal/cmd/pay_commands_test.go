@@ -258,6 +258,11 @@ func TestPayAgreementsListCommand_Errors(t *testing.T) {
 
 func TestPayAgreementsUpdateCommand(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// Snapshot for undo.
+			_ = json.NewEncoder(w).Encode(api.EmployeeAgreement{Id: 9, Employee: 42, Active: true})
+			return
+		}
 		assert.Equal(t, http.MethodPost, r.Method)
 		assert.Equal(t, "/api/v1/resource/EmployeeAgreement/9", r.URL.Path)
 
@@ -291,6 +296,11 @@ func TestPayAgreementsUpdateCommand(t *testing.T) {
 
 func TestPayAgreementsUpdateCommand_ConfigFile(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			// Snapshot for undo.
+			_ = json.NewEncoder(w).Encode(api.EmployeeAgreement{Id: 9, Employee: 42, Active: true})
+			return
+		}
 		assert.Equal(t, http.MethodPost, r.Method)
 		assert.Equal(t, "/api/v1/resource/EmployeeAgreement/9", r.URL.Path)
 
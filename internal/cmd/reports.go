@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+func newReportsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "reports",
+		Aliases: []string{"report"},
+		Short:   "Operational reports built from rosters, timesheets and sales",
+	}
+
+	cmd.AddCommand(newReportsLabourCmd())
+	cmd.AddCommand(newReportsAttendanceCmd())
+
+	return cmd
+}
+
+// reportFormat resolves the effective output format for report commands.
+// Reports accept --format csv in addition to the global text/json output.
+func reportFormat(ctx context.Context, flagValue string) (string, error) {
+	switch strings.ToLower(flagValue) {
+	case "":
+		return outfmt.GetFormat(ctx), nil
+	case "text", "json", "csv":
+		return strings.ToLower(flagValue), nil
+	default:
+		return "", fmt.Errorf("invalid --format %q (expected text, json or csv)", flagValue)
+	}
+}
+
+// writeCSV writes a header row followed by rows as RFC 4180 CSV.
+func writeCSV(w io.Writer, headers []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// locationAreas returns the operational units (areas) that belong to a
+// location, keyed by ID with their display names.
+func locationAreas(ctx context.Context, client *api.Client, locationID int) (map[int]string, error) {
+	departments, err := client.Departments().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	areas := make(map[int]string)
+	for _, d := range departments {
+		if d.Company == locationID {
+			areas[d.Id] = d.CompanyName
+		}
+	}
+	return areas, nil
+}
+
+// dateRangeSearch builds a resource QUERY search for records whose Date falls
+// within [from, to] (both YYYY-MM-DD), plus any extra filter expressions.
+func dateRangeSearch(from, to string, extra ...string) (map[string]interface{}, error) {
+	filters := append([]string{"Date>=" + from, "Date<=" + to}, extra...)
+	return parseFilters(filters)
+}
+
+// employeeDisplayNames returns every employee's display name keyed by ID.
+func employeeDisplayNames(ctx context.Context, client *api.Client) (map[int]string, error) {
+	employees, err := client.Employees().List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(employees))
+	for _, e := range employees {
+		names[e.Id] = e.DisplayName
+	}
+	return names, nil
+}
@@ -0,0 +1,494 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// Attendance statuses for a single rostered or worked shift.
+const (
+	attendanceOK         = "ok"
+	attendanceLate       = "late"
+	attendanceEarly      = "early-finish"
+	attendanceLateEarly  = "late+early-finish"
+	attendanceNoShow     = "no-show"
+	attendanceUnrostered = "unrostered"
+	attendanceInProgress = "in-progress"
+)
+
+// AttendanceGrace holds the tolerances, in minutes, before a deviation from
+// the roster is reported.
+type AttendanceGrace struct {
+	Late     int `json:"late"`
+	Early    int `json:"early"`
+	Overtime int `json:"overtime"`
+}
+
+// AttendanceDetail is one rostered shift and/or timesheet on a given day.
+type AttendanceDetail struct {
+	Date            string `json:"date"`
+	Employee        int    `json:"employee"`
+	EmployeeName    string `json:"employeeName,omitempty"`
+	Roster          int    `json:"roster,omitempty"`
+	Timesheet       int    `json:"timesheet,omitempty"`
+	RosteredStart   string `json:"rosteredStart,omitempty"`
+	RosteredEnd     string `json:"rosteredEnd,omitempty"`
+	ActualStart     string `json:"actualStart,omitempty"`
+	ActualEnd       string `json:"actualEnd,omitempty"`
+	Status          string `json:"status"`
+	LateMinutes     int    `json:"lateMinutes"`
+	EarlyMinutes    int    `json:"earlyMinutes"`
+	OvertimeMinutes int    `json:"overtimeMinutes"`
+}
+
+// AttendanceSummary ranks an employee's punctuality over the report range.
+// Employees with the most issues (late starts, early finishes and no-shows)
+// rank first.
+type AttendanceSummary struct {
+	Rank            int     `json:"rank"`
+	Employee        int     `json:"employee"`
+	EmployeeName    string  `json:"employeeName,omitempty"`
+	RosteredShifts  int     `json:"rosteredShifts"`
+	Attended        int     `json:"attended"`
+	LateStarts      int     `json:"lateStarts"`
+	LateMinutes     int     `json:"lateMinutes"`
+	EarlyFinishes   int     `json:"earlyFinishes"`
+	EarlyMinutes    int     `json:"earlyMinutes"`
+	NoShows         int     `json:"noShows"`
+	Unrostered      int     `json:"unrostered"`
+	OvertimeMinutes int     `json:"overtimeMinutes"`
+	Punctuality     float64 `json:"punctuality"`
+}
+
+// AttendanceReport is the JSON shape of `deputy reports attendance`.
+type AttendanceReport struct {
+	From      string              `json:"from"`
+	To        string              `json:"to"`
+	Location  int                 `json:"location,omitempty"`
+	Grace     AttendanceGrace     `json:"grace"`
+	Employees []AttendanceSummary `json:"employees"`
+	Days      []AttendanceDetail  `json:"days"`
+}
+
+func newReportsAttendanceCmd() *cobra.Command {
+	var fromDate, toDate, format string
+	var locationID, employeeID int
+	var grace AttendanceGrace
+	var summaryOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "attendance",
+		Short: "Compare rosters to timesheets for punctuality and no-shows",
+		Long: `Match each roster to the employee's timesheet with the largest overlapping
+time and report deviations:
+
+  late           started more than --late-grace minutes after the rostered start
+  early-finish   finished more than --early-grace minutes before the rostered end
+  no-show        rostered shift in the past with no overlapping timesheet
+  unrostered     timesheet with no overlapping roster
+  overtime       minutes worked past the rostered end, beyond --overtime-grace
+
+Employees are ranked with the most issues first, followed by per-day detail.
+Times are shown in the location's timezone when --location is set.`,
+		Example: `  deputy reports attendance --from 2026-10-12 --to 2026-10-18
+  deputy reports attendance --from 2026-10-12 --to 2026-10-18 --location 3 --late-grace 10
+  deputy reports attendance --from 2026-10-01 --to 2026-10-31 --employee 42 --format csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromDate == "" || toDate == "" {
+				return errors.New("--from and --to are required")
+			}
+			from, _, err := parseDateFlag(fromDate, "--from")
+			if err != nil {
+				return err
+			}
+			to, _, err := parseDateFlag(toDate, "--to")
+			if err != nil {
+				return err
+			}
+			if from.After(to) {
+				return errors.New("--from must be on or before --to")
+			}
+			if grace.Late < 0 || grace.Early < 0 || grace.Overtime < 0 {
+				return errors.New("grace periods must not be negative")
+			}
+			outFormat, err := reportFormat(cmd.Context(), format)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			tz := time.Local
+			var areas map[int]string
+			if locationID != 0 {
+				if tz, err = locationTimezone(ctx, client, locationID); err != nil {
+					return err
+				}
+				if areas, err = locationAreas(ctx, client, locationID); err != nil {
+					return err
+				}
+			}
+
+			var extra []string
+			if employeeID != 0 {
+				extra = append(extra, "Employee="+strconv.Itoa(employeeID))
+			}
+			search, err := dateRangeSearch(fromDate, toDate, extra...)
+			if err != nil {
+				return err
+			}
+			rosters, err := client.Rosters().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+			timesheets, err := client.Timesheets().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+			names, err := employeeDisplayNames(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			rosters, timesheets = filterAttendanceRecords(rosters, timesheets, areas, employeeID)
+			days := matchAttendance(rosters, timesheets, grace, tz, time.Now())
+			for i := range days {
+				days[i].EmployeeName = names[days[i].Employee]
+			}
+			report := AttendanceReport{
+				From:      fromDate,
+				To:        toDate,
+				Location:  locationID,
+				Grace:     grace,
+				Employees: rankAttendance(days),
+				Days:      days,
+			}
+			if summaryOnly {
+				report.Days = []AttendanceDetail{}
+			}
+
+			io := iocontext.FromContext(ctx)
+			switch outFormat {
+			case "json":
+				f := outfmt.New(outfmt.WithFormat(ctx, "json"))
+				return f.Output(report)
+			case "csv":
+				if summaryOnly {
+					rows := make([][]string, 0, len(report.Employees))
+					for _, s := range report.Employees {
+						rows = append(rows, []string{
+							strconv.Itoa(s.Rank), strconv.Itoa(s.Employee), s.EmployeeName,
+							strconv.Itoa(s.RosteredShifts), strconv.Itoa(s.Attended),
+							strconv.Itoa(s.LateStarts), strconv.Itoa(s.LateMinutes),
+							strconv.Itoa(s.EarlyFinishes), strconv.Itoa(s.EarlyMinutes),
+							strconv.Itoa(s.NoShows), strconv.Itoa(s.Unrostered),
+							strconv.Itoa(s.OvertimeMinutes), fmt.Sprintf("%.1f", s.Punctuality),
+						})
+					}
+					return writeCSV(io.Out, []string{"rank", "employee", "employee_name", "rostered_shifts", "attended", "late_starts", "late_minutes", "early_finishes", "early_minutes", "no_shows", "unrostered", "overtime_minutes", "punctuality"}, rows)
+				}
+				rows := make([][]string, 0, len(report.Days))
+				for _, d := range report.Days {
+					rows = append(rows, []string{
+						d.Date, strconv.Itoa(d.Employee), d.EmployeeName,
+						idOrEmpty(d.Roster), idOrEmpty(d.Timesheet),
+						d.RosteredStart, d.RosteredEnd, d.ActualStart, d.ActualEnd, d.Status,
+						strconv.Itoa(d.LateMinutes), strconv.Itoa(d.EarlyMinutes), strconv.Itoa(d.OvertimeMinutes),
+					})
+				}
+				return writeCSV(io.Out, []string{"date", "employee", "employee_name", "roster", "timesheet", "rostered_start", "rostered_end", "actual_start", "actual_end", "status", "late_minutes", "early_minutes", "overtime_minutes"}, rows)
+			}
+
+			f := outfmt.New(ctx)
+			f.StartTable([]string{"RANK", "EMPLOYEE", "SHIFTS", "LATE", "LATE MIN", "EARLY", "EARLY MIN", "NO-SHOW", "UNROSTERED", "OVERTIME MIN", "PUNCTUALITY %"})
+			for _, s := range report.Employees {
+				f.Row(
+					strconv.Itoa(s.Rank),
+					employeeLabel(s.Employee, s.EmployeeName),
+					strconv.Itoa(s.RosteredShifts),
+					strconv.Itoa(s.LateStarts),
+					strconv.Itoa(s.LateMinutes),
+					strconv.Itoa(s.EarlyFinishes),
+					strconv.Itoa(s.EarlyMinutes),
+					strconv.Itoa(s.NoShows),
+					strconv.Itoa(s.Unrostered),
+					strconv.Itoa(s.OvertimeMinutes),
+					fmt.Sprintf("%.1f", s.Punctuality),
+				)
+			}
+			f.EndTable()
+
+			if summaryOnly {
+				return nil
+			}
+			_, _ = fmt.Fprintln(io.Out)
+			f = outfmt.New(ctx)
+			f.StartTable([]string{"DATE", "EMPLOYEE", "ROSTERED", "ACTUAL", "STATUS", "LATE", "EARLY", "OVERTIME"})
+			for _, d := range report.Days {
+				f.Row(
+					d.Date,
+					employeeLabel(d.Employee, d.EmployeeName),
+					timeRange(d.RosteredStart, d.RosteredEnd),
+					timeRange(d.ActualStart, d.ActualEnd),
+					d.Status,
+					strconv.Itoa(d.LateMinutes),
+					strconv.Itoa(d.EarlyMinutes),
+					strconv.Itoa(d.OvertimeMinutes),
+				)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fromDate, "from", "", "Start date (YYYY-MM-DD, required)")
+	cmd.Flags().StringVar(&toDate, "to", "", "End date (YYYY-MM-DD, required)")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Only include shifts in this location's areas")
+	cmd.Flags().IntVar(&employeeID, "employee", 0, "Only include this employee")
+	cmd.Flags().IntVar(&grace.Late, "late-grace", 5, "Minutes after the rostered start before a start counts as late")
+	cmd.Flags().IntVar(&grace.Early, "early-grace", 5, "Minutes before the rostered end before a finish counts as early")
+	cmd.Flags().IntVar(&grace.Overtime, "overtime-grace", 0, "Minutes past the rostered end before overtime is counted")
+	cmd.Flags().BoolVar(&summaryOnly, "summary", false, "Show only the employee ranking")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: text, json or csv (defaults to --output)")
+
+	return cmd
+}
+
+// filterAttendanceRecords drops open shifts, leave timesheets and records
+// outside the location's areas (when areas is non-nil) or for other employees.
+func filterAttendanceRecords(rosters []api.Roster, timesheets []api.Timesheet, areas map[int]string, employeeID int) ([]api.Roster, []api.Timesheet) {
+	keep := func(employee, area int) bool {
+		if employee == 0 || (employeeID != 0 && employee != employeeID) {
+			return false
+		}
+		if areas != nil {
+			if _, ok := areas[area]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	var rs []api.Roster
+	for _, r := range rosters {
+		if !r.Open && keep(r.Employee, r.OperationalUnit) {
+			rs = append(rs, r)
+		}
+	}
+	var ts []api.Timesheet
+	for _, t := range timesheets {
+		if !t.IsLeave && keep(t.Employee, t.OperationalUnit) {
+			ts = append(ts, t)
+		}
+	}
+	return rs, ts
+}
+
+// matchAttendance pairs each roster with the same employee's timesheet that
+// overlaps it the most. Each timesheet is matched at most once; leftovers are
+// reported as unrostered. In-progress timesheets are treated as ending at now.
+func matchAttendance(rosters []api.Roster, timesheets []api.Timesheet, grace AttendanceGrace, tz *time.Location, now time.Time) []AttendanceDetail {
+	sort.Slice(rosters, func(i, j int) bool { return rosters[i].StartTime < rosters[j].StartTime })
+
+	timesheetEnd := func(t api.Timesheet) int64 {
+		if t.IsInProgress || t.EndTime == 0 {
+			return now.Unix()
+		}
+		return t.EndTime
+	}
+	clock := func(ts int64) string {
+		return time.Unix(ts, 0).In(tz).Format("15:04")
+	}
+
+	used := make(map[int]bool, len(timesheets))
+	var days []AttendanceDetail
+	for _, r := range rosters {
+		best, bestOverlap := -1, int64(0)
+		for i, t := range timesheets {
+			if used[i] || t.Employee != r.Employee {
+				continue
+			}
+			overlap := min(r.EndTime, timesheetEnd(t)) - max(r.StartTime, t.StartTime)
+			if overlap > bestOverlap {
+				best, bestOverlap = i, overlap
+			}
+		}
+
+		d := AttendanceDetail{
+			Date:          reportDay(r.StartTime, r.Date, tz).Format("2006-01-02"),
+			Employee:      r.Employee,
+			Roster:        r.Id,
+			RosteredStart: clock(r.StartTime),
+			RosteredEnd:   clock(r.EndTime),
+		}
+		if best < 0 {
+			if r.StartTime >= now.Unix() {
+				// Future shifts haven't been missed yet.
+				continue
+			}
+			d.Status = attendanceNoShow
+			days = append(days, d)
+			continue
+		}
+
+		t := timesheets[best]
+		used[best] = true
+		d.Timesheet = t.Id
+		d.ActualStart = clock(t.StartTime)
+		if late := minutesBetween(r.StartTime, t.StartTime); late > grace.Late {
+			d.LateMinutes = late
+		}
+		if t.IsInProgress || t.EndTime == 0 {
+			d.Status = attendanceInProgress
+			days = append(days, d)
+			continue
+		}
+		d.ActualEnd = clock(t.EndTime)
+		if early := minutesBetween(t.EndTime, r.EndTime); early > grace.Early {
+			d.EarlyMinutes = early
+		}
+		if over := minutesBetween(r.EndTime, t.EndTime); over > grace.Overtime {
+			d.OvertimeMinutes = over
+		}
+		d.Status = attendanceStatus(d.LateMinutes, d.EarlyMinutes)
+		days = append(days, d)
+	}
+
+	for i, t := range timesheets {
+		if used[i] {
+			continue
+		}
+		d := AttendanceDetail{
+			Date:        reportDay(t.StartTime, t.Date, tz).Format("2006-01-02"),
+			Employee:    t.Employee,
+			Timesheet:   t.Id,
+			ActualStart: clock(t.StartTime),
+			Status:      attendanceUnrostered,
+		}
+		if !t.IsInProgress && t.EndTime != 0 {
+			d.ActualEnd = clock(t.EndTime)
+		}
+		days = append(days, d)
+	}
+
+	sort.SliceStable(days, func(i, j int) bool {
+		if days[i].Date != days[j].Date {
+			return days[i].Date < days[j].Date
+		}
+		return days[i].Employee < days[j].Employee
+	})
+	return days
+}
+
+// rankAttendance summarises detail rows per employee, worst first.
+func rankAttendance(days []AttendanceDetail) []AttendanceSummary {
+	byEmployee := make(map[int]*AttendanceSummary)
+	for _, d := range days {
+		s, ok := byEmployee[d.Employee]
+		if !ok {
+			s = &AttendanceSummary{Employee: d.Employee, EmployeeName: d.EmployeeName}
+			byEmployee[d.Employee] = s
+		}
+		if d.Roster != 0 {
+			s.RosteredShifts++
+		}
+		switch d.Status {
+		case attendanceNoShow:
+			s.NoShows++
+			continue
+		case attendanceUnrostered:
+			s.Unrostered++
+			continue
+		}
+		s.Attended++
+		if d.LateMinutes > 0 {
+			s.LateStarts++
+			s.LateMinutes += d.LateMinutes
+		}
+		if d.EarlyMinutes > 0 {
+			s.EarlyFinishes++
+			s.EarlyMinutes += d.EarlyMinutes
+		}
+		s.OvertimeMinutes += d.OvertimeMinutes
+	}
+
+	summaries := make([]AttendanceSummary, 0, len(byEmployee))
+	for _, s := range byEmployee {
+		if s.RosteredShifts > 0 {
+			onTime := s.Attended - s.LateStarts
+			s.Punctuality = float64(onTime) / float64(s.RosteredShifts) * 100
+		}
+		summaries = append(summaries, *s)
+	}
+	issues := func(s AttendanceSummary) int { return s.LateStarts + s.EarlyFinishes + s.NoShows }
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if issues(a) != issues(b) {
+			return issues(a) > issues(b)
+		}
+		if a.LateMinutes+a.EarlyMinutes != b.LateMinutes+b.EarlyMinutes {
+			return a.LateMinutes+a.EarlyMinutes > b.LateMinutes+b.EarlyMinutes
+		}
+		return a.Employee < b.Employee
+	})
+	for i := range summaries {
+		summaries[i].Rank = i + 1
+	}
+	return summaries
+}
+
+func attendanceStatus(lateMinutes, earlyMinutes int) string {
+	switch {
+	case lateMinutes > 0 && earlyMinutes > 0:
+		return attendanceLateEarly
+	case lateMinutes > 0:
+		return attendanceLate
+	case earlyMinutes > 0:
+		return attendanceEarly
+	default:
+		return attendanceOK
+	}
+}
+
+// minutesBetween returns whole minutes from a to b, or 0 when b is not after a.
+func minutesBetween(a, b int64) int {
+	if b <= a {
+		return 0
+	}
+	return int((b - a) / 60)
+}
+
+func employeeLabel(id int, name string) string {
+	if name == "" {
+		return strconv.Itoa(id)
+	}
+	return fmt.Sprintf("%s (%d)", name, id)
+}
+
+func timeRange(start, end string) string {
+	if start == "" && end == "" {
+		return "-"
+	}
+	return strings.TrimSpace(start + "-" + end)
+}
+
+func idOrEmpty(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// LabourReportRow is one group (day, week or area) of the labour report.
+// LabourPercent is nil when there were no sales to compare against.
+type LabourReportRow struct {
+	Group         string   `json:"group"`
+	RosteredHours float64  `json:"rosteredHours"`
+	RosteredCost  float64  `json:"rosteredCost"`
+	ActualHours   float64  `json:"actualHours"`
+	ActualCost    float64  `json:"actualCost"`
+	Sales         float64  `json:"sales"`
+	LabourPercent *float64 `json:"labourPercent"`
+	Variance      float64  `json:"variance"`
+}
+
+const unassignedArea = "(unassigned)"
+
+func newReportsLabourCmd() *cobra.Command {
+	var fromDate, toDate, groupBy, format string
+	var locationID int
+
+	cmd := &cobra.Command{
+		Use:   "labour",
+		Short: "Compare rostered and actual labour cost against sales",
+		Long: `Compare rostered cost, actual timesheet cost and sales for a location.
+
+For each group the report shows:
+  rostered cost   sum of roster Cost
+  actual cost     sum of timesheet Cost
+  sales           sum of SalesData values
+  labour %        actual cost / sales x 100
+  variance        actual cost - rostered cost
+
+Use --format csv to export for spreadsheets.`,
+		Aliases: []string{"labor"},
+		Example: `  deputy reports labour --from 2026-10-12 --to 2026-10-18 --location 3
+  deputy reports labour --from 2026-10-01 --to 2026-10-31 --location 3 --group-by week
+  deputy reports labour --from 2026-10-12 --to 2026-10-18 --location 3 --group-by area --format csv > labour.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromDate == "" || toDate == "" {
+				return errors.New("--from and --to are required")
+			}
+			if locationID == 0 {
+				return errors.New("--location is required")
+			}
+			from, _, err := parseDateFlag(fromDate, "--from")
+			if err != nil {
+				return err
+			}
+			to, _, err := parseDateFlag(toDate, "--to")
+			if err != nil {
+				return err
+			}
+			if from.After(to) {
+				return errors.New("--from must be on or before --to")
+			}
+			if groupBy != "day" && groupBy != "week" && groupBy != "area" {
+				return fmt.Errorf("invalid --group-by %q (expected day, week or area)", groupBy)
+			}
+			outFormat, err := reportFormat(cmd.Context(), format)
+			if err != nil {
+				return err
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			tz, err := locationTimezone(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+			areas, err := locationAreas(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+
+			search, err := dateRangeSearch(fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			rosters, err := client.Rosters().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+			timesheets, err := client.Timesheets().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+
+			rangeStart := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, tz)
+			rangeEnd := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, tz)
+			sales, err := client.Sales().Query(ctx, &api.SalesQueryInput{
+				Company:   locationID,
+				StartTime: rangeStart.Unix(),
+				EndTime:   rangeEnd.Unix() - 1,
+			})
+			if err != nil {
+				return err
+			}
+
+			rows := buildLabourReport(rosters, timesheets, sales, areas, tz, groupBy, rangeStart, rangeEnd)
+			total := labourTotals(rows)
+
+			io := iocontext.FromContext(ctx)
+			switch outFormat {
+			case "csv":
+				csvRows := make([][]string, 0, len(rows)+1)
+				for _, r := range append(rows, total) {
+					csvRows = append(csvRows, []string{
+						r.Group,
+						fmt.Sprintf("%.2f", r.RosteredHours),
+						fmt.Sprintf("%.2f", r.RosteredCost),
+						fmt.Sprintf("%.2f", r.ActualHours),
+						fmt.Sprintf("%.2f", r.ActualCost),
+						fmt.Sprintf("%.2f", r.Sales),
+						formatPercent(r.LabourPercent, ""),
+						fmt.Sprintf("%.2f", r.Variance),
+					})
+				}
+				return writeCSV(io.Out, []string{"group", "rostered_hours", "rostered_cost", "actual_hours", "actual_cost", "sales", "labour_percent", "variance"}, csvRows)
+			case "json":
+				f := outfmt.New(outfmt.WithFormat(ctx, "json"))
+				return f.OutputWithMeta(rows, map[string]any{
+					"count":    len(rows),
+					"from":     fromDate,
+					"to":       toDate,
+					"location": locationID,
+					"groupBy":  groupBy,
+					"totals":   total,
+				})
+			}
+
+			f := outfmt.New(ctx)
+			f.StartTable([]string{"GROUP", "ROSTERED HRS", "ROSTERED COST", "ACTUAL HRS", "ACTUAL COST", "SALES", "LABOUR %", "VARIANCE"})
+			for _, r := range append(rows, total) {
+				f.Row(
+					r.Group,
+					fmt.Sprintf("%.2f", r.RosteredHours),
+					fmt.Sprintf("%.2f", r.RosteredCost),
+					fmt.Sprintf("%.2f", r.ActualHours),
+					fmt.Sprintf("%.2f", r.ActualCost),
+					fmt.Sprintf("%.2f", r.Sales),
+					formatPercent(r.LabourPercent, "-"),
+					fmt.Sprintf("%+.2f", r.Variance),
+				)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fromDate, "from", "", "Start date (YYYY-MM-DD, required)")
+	cmd.Flags().StringVar(&toDate, "to", "", "End date (YYYY-MM-DD, required)")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Location ID (required)")
+	cmd.Flags().StringVar(&groupBy, "group-by", "day", "Group rows by day, week or area")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: text, json or csv (defaults to --output)")
+
+	return cmd
+}
+
+// buildLabourReport aggregates rosters, timesheets and sales for the location's
+// areas into rows keyed by groupBy. Rosters and timesheets outside the areas
+// are ignored; sales outside [rangeStart, rangeEnd) are ignored.
+func buildLabourReport(rosters []api.Roster, timesheets []api.Timesheet, sales []api.SalesData, areas map[int]string, tz *time.Location, groupBy string, rangeStart, rangeEnd time.Time) []LabourReportRow {
+	groups := make(map[string]*LabourReportRow)
+	row := func(key string) *LabourReportRow {
+		if r, ok := groups[key]; ok {
+			return r
+		}
+		r := &LabourReportRow{Group: key}
+		groups[key] = r
+		return r
+	}
+	keyFor := func(ts int64, date string, area int) string {
+		switch groupBy {
+		case "area":
+			if name, ok := areas[area]; ok {
+				return name
+			}
+			return unassignedArea
+		case "week":
+			return weekStart(reportDay(ts, date, tz)).Format("2006-01-02")
+		default:
+			return reportDay(ts, date, tz).Format("2006-01-02")
+		}
+	}
+
+	for _, r := range rosters {
+		if _, ok := areas[r.OperationalUnit]; !ok {
+			continue
+		}
+		g := row(keyFor(r.StartTime, r.Date, r.OperationalUnit))
+		g.RosteredCost += r.Cost
+		if r.EndTime > r.StartTime {
+			g.RosteredHours += float64(r.EndTime-r.StartTime) / 3600
+		}
+	}
+	for _, t := range timesheets {
+		if _, ok := areas[t.OperationalUnit]; !ok || t.IsLeave {
+			continue
+		}
+		g := row(keyFor(t.StartTime, t.Date, t.OperationalUnit))
+		g.ActualCost += t.Cost
+		g.ActualHours += t.TotalTime
+	}
+	for _, s := range sales {
+		at := time.Unix(s.Timestamp, 0)
+		if at.Before(rangeStart) || !at.Before(rangeEnd) {
+			continue
+		}
+		g := row(keyFor(s.Timestamp, "", s.Area))
+		g.Sales += s.Value
+	}
+
+	rows := make([]LabourReportRow, 0, len(groups))
+	for _, g := range groups {
+		finishLabourRow(g)
+		rows = append(rows, *g)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Group < rows[j].Group })
+	return rows
+}
+
+// labourTotals sums all rows into a TOTAL row.
+func labourTotals(rows []LabourReportRow) LabourReportRow {
+	total := LabourReportRow{Group: "TOTAL"}
+	for _, r := range rows {
+		total.RosteredHours += r.RosteredHours
+		total.RosteredCost += r.RosteredCost
+		total.ActualHours += r.ActualHours
+		total.ActualCost += r.ActualCost
+		total.Sales += r.Sales
+	}
+	finishLabourRow(&total)
+	return total
+}
+
+func finishLabourRow(r *LabourReportRow) {
+	r.Variance = r.ActualCost - r.RosteredCost
+	r.LabourPercent = nil
+	if r.Sales != 0 {
+		pct := r.ActualCost / r.Sales * 100
+		r.LabourPercent = &pct
+	}
+}
+
+// reportDay returns the calendar day for a record, preferring the Unix
+// timestamp in the location's timezone and falling back to a YYYY-MM-DD date.
+func reportDay(ts int64, date string, tz *time.Location) time.Time {
+	if ts > 0 {
+		t := time.Unix(ts, 0).In(tz)
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tz)
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", datePart(date), tz); err == nil {
+		return parsed
+	}
+	return time.Time{}
+}
+
+// weekStart returns the Monday on or before day.
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func formatPercent(pct *float64, empty string) string {
+	if pct == nil {
+		return empty
+	}
+	return fmt.Sprintf("%.1f", *pct)
+}
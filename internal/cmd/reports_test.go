@@ -0,0 +1,314 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// newReportsTestServer serves one location (UTC) with two areas, plus rosters,
+// timesheets and sales on 2026-10-12 (Monday) and 2026-10-13.
+func newReportsTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	day1 := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC).Unix()
+	day2 := time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC).Unix()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Company/1", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 1, CompanyName: "Main", Timezone: "UTC"})
+	})
+	mux.HandleFunc("/api/v1/resource/OperationalUnit", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Department{
+			{Id: 10, Company: 1, CompanyName: "Kitchen"},
+			{Id: 11, Company: 1, CompanyName: "Bar"},
+			{Id: 99, Company: 2, CompanyName: "Elsewhere"},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/Roster/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Roster{
+			{Id: 1, Date: "2026-10-12", StartTime: day1, EndTime: day1 + 8*3600, OperationalUnit: 10, Cost: 200},
+			{Id: 2, Date: "2026-10-13", StartTime: day2, EndTime: day2 + 4*3600, OperationalUnit: 11, Cost: 100},
+			{Id: 3, Date: "2026-10-13", StartTime: day2, EndTime: day2 + 4*3600, OperationalUnit: 99, Cost: 999},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/Timesheet/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Timesheet{
+			{Id: 1, Date: "2026-10-12", StartTime: day1, TotalTime: 9, OperationalUnit: 10, Cost: 225},
+			{Id: 2, Date: "2026-10-13", StartTime: day2, TotalTime: 4, OperationalUnit: 11, Cost: 100},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/SalesData", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("company"))
+		_ = json.NewEncoder(w).Encode([]api.SalesData{
+			{Company: 1, Area: 10, Timestamp: day1 + 3600, Value: 900},
+			{Company: 1, Timestamp: day2 + 3600, Value: 500},
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestReportsLabourCommand_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing dates", []string{"--location", "1"}, "--from and --to are required"},
+		{"missing location", []string{"--from", "2026-10-12", "--to", "2026-10-13"}, "--location is required"},
+		{"reversed range", []string{"--from", "2026-10-13", "--to", "2026-10-12", "--location", "1"}, "on or before"},
+		{"bad group", []string{"--from", "2026-10-12", "--to", "2026-10-13", "--location", "1", "--group-by", "month"}, "invalid --group-by"},
+		{"bad format", []string{"--from", "2026-10-12", "--to", "2026-10-13", "--location", "1", "--format", "xml"}, "invalid --format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newReportsLabourCmd()
+			cmd.SetContext(context.Background())
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestReportsLabourCommand_JSONByDay(t *testing.T) {
+	server := newReportsTestServer(t)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = outfmt.WithFormat(ctx, "json")
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newReportsLabourCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--from", "2026-10-12", "--to", "2026-10-13", "--location", "1"})
+	require.NoError(t, cmd.Execute())
+
+	var out struct {
+		Items []LabourReportRow `json:"items"`
+		Meta  struct {
+			Totals LabourReportRow `json:"totals"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	require.Len(t, out.Items, 2)
+
+	first := out.Items[0]
+	assert.Equal(t, "2026-10-12", first.Group)
+	assert.InDelta(t, 8, first.RosteredHours, 0.001)
+	assert.InDelta(t, 200, first.RosteredCost, 0.001)
+	assert.InDelta(t, 225, first.ActualCost, 0.001)
+	assert.InDelta(t, 25, first.Variance, 0.001)
+	require.NotNil(t, first.LabourPercent)
+	assert.InDelta(t, 25, *first.LabourPercent, 0.001)
+
+	assert.InDelta(t, 300, out.Meta.Totals.RosteredCost, 0.001)
+	assert.InDelta(t, 1400, out.Meta.Totals.Sales, 0.001)
+}
+
+func TestReportsLabourCommand_CSVByArea(t *testing.T) {
+	server := newReportsTestServer(t)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newReportsLabourCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--from", "2026-10-12", "--to", "2026-10-13", "--location", "1", "--group-by", "area", "--format", "csv"})
+	require.NoError(t, cmd.Execute())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, "group,rostered_hours,rostered_cost,actual_hours,actual_cost,sales,labour_percent,variance", lines[0])
+	assert.Equal(t, "(unassigned),0.00,0.00,0.00,0.00,500.00,0.0,0.00", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "Bar,4.00,100.00,4.00,100.00,0.00,,"))
+	assert.True(t, strings.HasPrefix(lines[3], "Kitchen,"))
+	assert.True(t, strings.HasPrefix(lines[4], "TOTAL,"))
+}
+
+// pagedQuery serves rows to a resource QUERY one page at a time the way
+// Deputy does: from the request's start, and never more than
+// api.QueryPageSize records whatever max asks for.
+func pagedQuery[T any](t *testing.T, rows []T) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input api.QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		limit := input.Max
+		if limit <= 0 || limit > api.QueryPageSize {
+			limit = api.QueryPageSize
+		}
+		end := min(len(rows), input.Start+limit)
+		page := []T{}
+		if input.Start < end {
+			page = rows[input.Start:end]
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}
+}
+
+func TestReportsLabourCommand_PagesLargeRanges(t *testing.T) {
+	day := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC).Unix()
+	const n = api.QueryPageSize + 100
+	rosters := make([]api.Roster, n)
+	timesheets := make([]api.Timesheet, n)
+	for i := range rosters {
+		rosters[i] = api.Roster{Id: i + 1, Date: "2026-10-12", StartTime: day, EndTime: day + 3600, OperationalUnit: 10, Cost: 10}
+		timesheets[i] = api.Timesheet{Id: i + 1, Date: "2026-10-12", StartTime: day, TotalTime: 1, OperationalUnit: 10, Cost: 12}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Company/1", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 1, Timezone: "UTC"})
+	})
+	mux.HandleFunc("/api/v1/resource/OperationalUnit", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Department{{Id: 10, Company: 1, CompanyName: "Kitchen"}})
+	})
+	mux.HandleFunc("/api/v1/resource/Roster/QUERY", pagedQuery(t, rosters))
+	mux.HandleFunc("/api/v1/resource/Timesheet/QUERY", pagedQuery(t, timesheets))
+	mux.HandleFunc("/api/v1/resource/SalesData", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = outfmt.WithFormat(ctx, "json")
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newReportsLabourCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--from", "2026-10-12", "--to", "2026-10-12", "--location", "1"})
+	require.NoError(t, cmd.Execute())
+
+	var out struct {
+		Meta struct {
+			Totals LabourReportRow `json:"totals"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.InDelta(t, n, out.Meta.Totals.RosteredHours, 0.001)
+	assert.InDelta(t, 10*n, out.Meta.Totals.RosteredCost, 0.001)
+	assert.InDelta(t, n, out.Meta.Totals.ActualHours, 0.001)
+	assert.InDelta(t, 12*n, out.Meta.Totals.ActualCost, 0.001)
+}
+
+func TestWeekStart(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2026-10-12", weekStart(sunday).Format("2006-01-02"))
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "2026-10-12", weekStart(monday).Format("2006-01-02"))
+}
+
+func TestReportsAttendanceCommand(t *testing.T) {
+	// Rosters 09:00-17:00 UTC on 2024-03-04.
+	base := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC).Unix()
+	hour := int64(3600)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Roster/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Roster{
+			{Id: 1, Date: "2024-03-04", Employee: 5, StartTime: base, EndTime: base + 8*hour},
+			{Id: 2, Date: "2024-03-04", Employee: 6, StartTime: base, EndTime: base + 8*hour},
+			{Id: 3, Date: "2024-03-04", Employee: 7, StartTime: base, EndTime: base + 8*hour},
+			{Id: 4, Date: "2024-03-04", Open: true, StartTime: base, EndTime: base + 8*hour},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/Timesheet/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Timesheet{
+			// Employee 5: 20 minutes late, stays 30 minutes over.
+			{Id: 11, Date: "2024-03-04", Employee: 5, StartTime: base + 20*60, EndTime: base + 8*hour + 30*60},
+			// Employee 6: 3 minutes late (within grace), leaves an hour early.
+			{Id: 12, Date: "2024-03-04", Employee: 6, StartTime: base + 3*60, EndTime: base + 7*hour},
+			// Employee 8: not rostered.
+			{Id: 13, Date: "2024-03-04", Employee: 8, StartTime: base, EndTime: base + 4*hour},
+		})
+	})
+	mux.HandleFunc("/api/v1/supervise/employee", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Employee{{Id: 5, DisplayName: "Ann"}, {Id: 6, DisplayName: "Ben"}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = outfmt.WithFormat(ctx, "json")
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newReportsAttendanceCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"--from", "2024-03-04", "--to", "2024-03-04", "--late-grace", "5"})
+	require.NoError(t, cmd.Execute())
+
+	var report AttendanceReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+
+	statuses := make(map[int]AttendanceDetail)
+	for _, d := range report.Days {
+		statuses[d.Employee] = d
+	}
+	require.Len(t, statuses, 4)
+	assert.Equal(t, "late", statuses[5].Status)
+	assert.Equal(t, 20, statuses[5].LateMinutes)
+	assert.Equal(t, 30, statuses[5].OvertimeMinutes)
+	assert.Equal(t, "Ann", statuses[5].EmployeeName)
+	assert.Equal(t, "early-finish", statuses[6].Status)
+	assert.Equal(t, 0, statuses[6].LateMinutes)
+	assert.Equal(t, 60, statuses[6].EarlyMinutes)
+	assert.Equal(t, "no-show", statuses[7].Status)
+	assert.Equal(t, "unrostered", statuses[8].Status)
+
+	require.Len(t, report.Employees, 4)
+	// One issue each for 6, 5 and 7; ties break on minutes lost.
+	ranked := []int{report.Employees[0].Employee, report.Employees[1].Employee, report.Employees[2].Employee}
+	assert.Equal(t, []int{6, 5, 7}, ranked)
+	assert.Equal(t, 1, report.Employees[0].Rank)
+	assert.Equal(t, 8, report.Employees[3].Employee)
+	assert.Equal(t, 1, report.Employees[3].Unrostered)
+
+	t.Run("text output", func(t *testing.T) {
+		buf.Reset()
+		ctx := outfmt.WithFormat(ctx, "text")
+		cmd := newReportsAttendanceCmd()
+		cmd.SetContext(ctx)
+		cmd.SetArgs([]string{"--from", "2024-03-04", "--to", "2024-03-04"})
+		require.NoError(t, cmd.Execute())
+		assert.Contains(t, buf.String(), "PUNCTUALITY %")
+		assert.Contains(t, buf.String(), "Ann (5)")
+		assert.Contains(t, buf.String(), "no-show")
+	})
+}
+
+func TestMatchAttendance_PrefersLargestOverlap(t *testing.T) {
+	base := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC).Unix()
+	rosters := []api.Roster{
+		{Id: 1, Employee: 5, StartTime: base, EndTime: base + 4*3600},
+		{Id: 2, Employee: 5, StartTime: base + 5*3600, EndTime: base + 9*3600},
+	}
+	timesheets := []api.Timesheet{
+		{Id: 20, Employee: 5, StartTime: base + 5*3600, EndTime: base + 9*3600},
+		{Id: 10, Employee: 5, StartTime: base, EndTime: base + 4*3600},
+	}
+	days := matchAttendance(rosters, timesheets, AttendanceGrace{}, time.UTC, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+	require.Len(t, days, 2)
+	assert.Equal(t, 10, days[0].Timesheet)
+	assert.Equal(t, 20, days[1].Timesheet)
+	assert.Equal(t, "ok", days[0].Status)
+	assert.Equal(t, "ok", days[1].Status)
+}
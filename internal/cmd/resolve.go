@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+// entityKind identifies which Deputy records a reference resolves against.
+type entityKind string
+
+const (
+	entityEmployee   entityKind = "employee"
+	entityLocation   entityKind = "location"
+	entityDepartment entityKind = "department"
+	entityPayRule    entityKind = "pay rule"
+)
+
+// Reference prefixes accepted wherever a numeric ID is expected.
+const (
+	refEmail = "email:"
+	refName  = "name:"
+	refCode  = "code:"
+)
+
+// entityFlagKinds maps flag names to the records their IDs refer to.
+var entityFlagKinds = map[string]entityKind{
+	"employee":   entityEmployee,
+	"location":   entityLocation,
+	"company":    entityLocation,
+	"department": entityDepartment,
+	"opunit":     entityDepartment,
+	"area":       entityDepartment,
+	"pay-rule":   entityPayRule,
+}
+
+// entityArgKinds maps "<parent> <placeholder>" (or just "<placeholder>") from
+// a command's Use line to the records its first positional arg refers to.
+var entityArgKinds = map[string]entityKind{
+	"<employee-id>":    entityEmployee,
+	"employees <id>":   entityEmployee,
+	"locations <id>":   entityLocation,
+	"departments <id>": entityDepartment,
+}
+
+// isEntityRef reports whether s uses one of the name-based reference prefixes.
+func isEntityRef(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, refEmail) || strings.HasPrefix(lower, refName) || strings.HasPrefix(lower, refCode)
+}
+
+// entityArgKind returns the entity kind of c's first positional argument.
+func entityArgKind(c *cobra.Command) (entityKind, bool) {
+	fields := strings.Fields(c.Use)
+	if len(fields) < 2 {
+		return "", false
+	}
+	if kind, ok := entityArgKinds[fields[1]]; ok {
+		return kind, true
+	}
+	if c.Parent() != nil {
+		if kind, ok := entityArgKinds[c.Parent().Name()+" "+fields[1]]; ok {
+			return kind, true
+		}
+	}
+	return "", false
+}
+
+// entityFlagValue wraps an int or intSlice flag so it also accepts name-based
+// references. Numeric values pass straight through; references are held until
+// resolveEntityRefs swaps them for IDs.
+type entityFlagValue struct {
+	target  pflag.Value
+	kind    entityKind
+	pending []string
+}
+
+func (v *entityFlagValue) Type() string { return v.target.Type() }
+
+func (v *entityFlagValue) String() string {
+	if len(v.pending) > 0 {
+		return strings.Join(v.pending, ",")
+	}
+	return v.target.String()
+}
+
+func (v *entityFlagValue) Set(s string) error {
+	if isEntityRef(s) {
+		v.pending = append(v.pending, s)
+		return nil
+	}
+	return v.target.Set(s)
+}
+
+// registerEntityResolution lets every ID argument and ID flag in the tree
+// accept email:, name: and code: references. Resolution runs just before the
+// command's RunE, so commands keep working with plain integer IDs.
+func registerEntityResolution(root *cobra.Command) {
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		var flags []*entityFlagValue
+		c.Flags().VisitAll(func(f *pflag.Flag) {
+			kind, ok := entityFlagKinds[f.Name]
+			if !ok || (f.Value.Type() != "int" && f.Value.Type() != "intSlice") {
+				return
+			}
+			wrapped := &entityFlagValue{target: f.Value, kind: kind}
+			f.Value = wrapped
+			flags = append(flags, wrapped)
+		})
+		argKind, hasArg := entityArgKind(c)
+
+		if c.RunE != nil && (len(flags) > 0 || hasArg) {
+			run := c.RunE
+			c.RunE = func(cmd *cobra.Command, args []string) error {
+				resolver := newEntityResolver()
+				for _, f := range flags {
+					for _, ref := range f.pending {
+						id, err := resolver.resolve(cmd.Context(), f.kind, ref)
+						if err != nil {
+							return err
+						}
+						if err := f.target.Set(strconv.Itoa(id)); err != nil {
+							return err
+						}
+					}
+					f.pending = nil
+				}
+				if hasArg && len(args) > 0 && isEntityRef(args[0]) {
+					id, err := resolver.resolve(cmd.Context(), argKind, args[0])
+					if err != nil {
+						return err
+					}
+					args = append([]string{strconv.Itoa(id)}, args[1:]...)
+				}
+				return run(cmd, args)
+			}
+		}
+
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+// entityCandidate is one record a reference may resolve to.
+type entityCandidate struct {
+	ID    int
+	Name  string
+	Email string
+	Codes []string
+}
+
+// entityResolver looks up candidates once per kind per command run.
+type entityResolver struct {
+	client     *api.Client
+	candidates map[entityKind][]entityCandidate
+}
+
+func newEntityResolver() *entityResolver {
+	return &entityResolver{candidates: make(map[entityKind][]entityCandidate)}
+}
+
+// resolve turns an email:, name: or code: reference into an ID.
+func (r *entityResolver) resolve(ctx context.Context, kind entityKind, ref string) (int, error) {
+	prefix, value, _ := strings.Cut(ref, ":")
+	prefix = strings.ToLower(prefix) + ":"
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	if value == "" {
+		return 0, fmt.Errorf("empty %s reference %q", kind, ref)
+	}
+	switch {
+	case prefix == refEmail && kind != entityEmployee:
+		return 0, fmt.Errorf("email: references only apply to employees, not %ss", kind)
+	case prefix == refCode && kind != entityLocation && kind != entityDepartment:
+		return 0, fmt.Errorf("code: references only apply to locations and departments, not %ss", kind)
+	}
+
+	candidates, err := r.load(ctx, kind)
+	if err != nil {
+		return 0, err
+	}
+
+	var matches []entityCandidate
+	switch prefix {
+	case refEmail:
+		for _, c := range candidates {
+			if c.Email != "" && strings.EqualFold(c.Email, value) {
+				matches = append(matches, c)
+			}
+		}
+	case refCode:
+		for _, c := range candidates {
+			for _, code := range c.Codes {
+				if code != "" && strings.EqualFold(code, value) {
+					matches = append(matches, c)
+					break
+				}
+			}
+		}
+	default:
+		matches = matchEntityName(candidates, value)
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no %s matches %s", kind, ref)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, fmt.Sprintf("%d (%s)", m.ID, m.Name))
+		}
+		return 0, fmt.Errorf("%s %s is ambiguous; candidates: %s", kind, ref, strings.Join(names, ", "))
+	}
+}
+
+func (r *entityResolver) load(ctx context.Context, kind entityKind) ([]entityCandidate, error) {
+	if c, ok := r.candidates[kind]; ok {
+		return c, nil
+	}
+	if r.client == nil {
+		client, err := getClientFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		r.client = client
+	}
+
+	var candidates []entityCandidate
+	switch kind {
+	case entityEmployee:
+		employees, err := r.client.Employees().List(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range employees {
+			name := e.DisplayName
+			if name == "" {
+				name = strings.TrimSpace(e.FirstName + " " + e.LastName)
+			}
+			candidates = append(candidates, entityCandidate{ID: e.Id, Name: name, Email: e.Email})
+		}
+	case entityLocation:
+		locations, err := r.client.Locations().List(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, l := range locations {
+			candidates = append(candidates, entityCandidate{ID: l.Id, Name: l.CompanyName, Codes: []string{l.Code, l.CompanyCode}})
+		}
+	case entityDepartment:
+		departments, err := r.client.Departments().List(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range departments {
+			candidates = append(candidates, entityCandidate{ID: d.Id, Name: d.CompanyName, Codes: []string{d.CompanyCode}})
+		}
+	case entityPayRule:
+		rules, err := r.client.Timesheets().ListPayRules(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range rules {
+			candidates = append(candidates, entityCandidate{ID: p.Id, Name: p.PayTitle})
+		}
+	}
+	r.candidates[kind] = candidates
+	return candidates, nil
+}
+
+// matchEntityName finds candidates by name, from strictest to loosest:
+// exact (ignoring case and spacing), then every word of the query appearing
+// as a word prefix, then a small edit distance. The first tier with any
+// matches wins.
+func matchEntityName(candidates []entityCandidate, query string) []entityCandidate {
+	q := normalizeName(query)
+	qWords := strings.Fields(q)
+
+	tiers := make([][]entityCandidate, 3)
+	for _, c := range candidates {
+		name := normalizeName(c.Name)
+		switch {
+		case name == q:
+			tiers[0] = append(tiers[0], c)
+		case wordsPrefixMatch(strings.Fields(name), qWords):
+			tiers[1] = append(tiers[1], c)
+		case levenshtein(name, q) <= maxNameDistance(q):
+			tiers[2] = append(tiers[2], c)
+		}
+	}
+	for _, tier := range tiers {
+		if len(tier) > 0 {
+			sort.Slice(tier, func(i, j int) bool { return tier[i].ID < tier[j].ID })
+			return tier
+		}
+	}
+	return nil
+}
+
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// wordsPrefixMatch reports whether every query word is a prefix of a
+// distinct name word, so "jan do" matches "Jane Doe".
+func wordsPrefixMatch(nameWords, queryWords []string) bool {
+	if len(queryWords) == 0 {
+		return false
+	}
+	used := make([]bool, len(nameWords))
+	for _, q := range queryWords {
+		found := false
+		for i, w := range nameWords {
+			if !used[i] && strings.HasPrefix(w, q) {
+				used[i] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// maxNameDistance allows roughly one typo per five characters.
+func maxNameDistance(q string) int {
+	return max(1, len(q)/5)
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+func TestMatchEntityName(t *testing.T) {
+	candidates := []entityCandidate{
+		{ID: 1, Name: "Jane Doe"},
+		{ID: 2, Name: "Jane Smith"},
+		{ID: 3, Name: "John Roe"},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"jane  doe", []int{1}},
+		{"jan do", []int{1}},
+		{"jane", []int{1, 2}},
+		{"jon roe", []int{3}},
+		{"nobody", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []int
+			for _, m := range matchEntityName(candidates, tt.query) {
+				got = append(got, m.ID)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func newResolveTestServer(t *testing.T, requested *[]string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/supervise/employee", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Employee{
+			{Id: 12, DisplayName: "Jane Doe", Email: "jane@example.com"},
+			{Id: 13, DisplayName: "Jane Smith", Email: "jsmith@example.com"},
+		})
+	})
+	mux.HandleFunc("/api/v1/supervise/employee/", func(w http.ResponseWriter, r *http.Request) {
+		*requested = append(*requested, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(api.Employee{Id: 12, DisplayName: "Jane Doe"})
+	})
+	mux.HandleFunc("/api/v1/supervise/location/simplified", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Location{{Id: 3, CompanyName: "Main Street", Code: "MST"}})
+	})
+	mux.HandleFunc("/api/v1/resource/Company/3", func(w http.ResponseWriter, r *http.Request) {
+		*requested = append(*requested, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 3, CompanyName: "Main Street", Code: "MST"})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestEntityResolution_PositionalArgs(t *testing.T) {
+	var requested []string
+	server := newResolveTestServer(t, &requested)
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "", "employees", "get", "email:JANE@example.com")
+	require.NoError(t, err)
+	_, _, err = runRoot(t, server, "", "employees", "get", `name:"jane doe"`)
+	require.NoError(t, err)
+	_, _, err = runRoot(t, server, "", "locations", "get", "code:mst")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/api/v1/supervise/employee/12", "/api/v1/supervise/employee/12", "/api/v1/resource/Company/3"}, requested)
+}
+
+func TestEntityResolution_Errors(t *testing.T) {
+	var requested []string
+	server := newResolveTestServer(t, &requested)
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "", "employees", "get", "name:jane")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ambiguous")
+	assert.Contains(t, err.Error(), "12 (Jane Doe)")
+	assert.Contains(t, err.Error(), "13 (Jane Smith)")
+
+	_, _, err = runRoot(t, server, "", "employees", "get", "email:nobody@example.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no employee matches")
+
+	_, _, err = runRoot(t, server, "", "locations", "get", "email:x@example.com")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only apply to employees")
+
+	_, _, err = runRoot(t, server, "", "employees", "get", "abc")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid employee ID")
+	assert.Empty(t, requested)
+}
+
+func TestEntityResolution_Flags(t *testing.T) {
+	var query api.QueryInput
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/supervise/employee", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Employee{{Id: 12, DisplayName: "Jane Doe", Email: "jane@example.com"}})
+	})
+	mux.HandleFunc("/api/v1/resource/Timesheet/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&query))
+		_ = json.NewEncoder(w).Encode([]api.Timesheet{})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "", "timesheets", "list", "--employee", "email:jane@example.com")
+	require.NoError(t, err)
+	filter, ok := query.Search["f1"].(map[string]interface{})
+	require.True(t, ok, "expected an employee filter, got %v", query.Search)
+	assert.Equal(t, "Employee", filter["field"])
+	assert.Equal(t, "12", filter["data"])
+
+	_, _, err = runRoot(t, server, "", "timesheets", "list", "--employee", "jane")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid argument")
+}
@@ -17,14 +17,17 @@ func newResourceCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "resource",
 		Aliases: []string{"res"},
-		Short:   "Query any Deputy resource",
-		Long:    "Generic commands for querying any Deputy resource type using the QUERY API.",
+		Short:   "Query and modify any Deputy resource",
+		Long:    "Generic commands for querying any Deputy resource type using the QUERY API, and for creating, updating and deleting records of resources without a dedicated command.",
 	}
 
 	cmd.AddCommand(newResourceListCmd())
 	cmd.AddCommand(newResourceInfoCmd())
 	cmd.AddCommand(newResourceQueryCmd())
 	cmd.AddCommand(newResourceGetCmd())
+	cmd.AddCommand(newResourceCreateCmd())
+	cmd.AddCommand(newResourceUpdateCmd())
+	cmd.AddCommand(newResourceDeleteCmd())
 
 	return cmd
 }
@@ -248,16 +251,7 @@ func newResourceGetCmd() *cobra.Command {
 				return f.Output(result)
 			}
 
-			io := iocontext.FromContext(cmd.Context())
-			keys := make([]string, 0, len(result))
-			for k := range result {
-				keys = append(keys, k)
-			}
-			sort.Strings(keys)
-			for _, k := range keys {
-				_, _ = fmt.Fprintf(io.Out, "%s: %v\n", k, result[k])
-			}
-
+			printResourceRecord(iocontext.FromContext(cmd.Context()).Out, result)
 			return nil
 		},
 	}
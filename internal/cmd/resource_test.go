@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,6 +67,9 @@ func TestResourceCommand_HasAllSubcommands(t *testing.T) {
 		"info",
 		"query",
 		"get",
+		"create",
+		"update",
+		"delete",
 	}
 
 	for _, sub := range expectedSubcommands {
@@ -763,3 +767,108 @@ func TestResourceCommand_WithMockClient(t *testing.T) {
 		assert.Contains(t, output, `"FirstName": "Jane"`)
 	})
 }
+
+// resourceWriteServer serves Task INFO and records writes.
+func resourceWriteServer(t *testing.T, writes *[]string, bodies *[]map[string]interface{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/resource/Task/INFO" {
+			_, _ = w.Write([]byte(`{"name":"Task","fields":{
+				"Id":"Integer","Question":"VarChar","Employee":"Integer",
+				"Completed":"Bit","DueDate":"Date","Cost":{"type":"Float"}}}`))
+			return
+		}
+		*writes = append(*writes, r.Method+" "+r.URL.Path)
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		*bodies = append(*bodies, body)
+		if r.Method != http.MethodDelete {
+			result := map[string]interface{}{"Id": 77}
+			for k, v := range body {
+				result[k] = v
+			}
+			_ = json.NewEncoder(w).Encode(result)
+		}
+	}))
+}
+
+func runResourceWrite(t *testing.T, server *httptest.Server, stdin string, args ...string) (string, error) {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{In: strings.NewReader(stdin), Out: buf, ErrOut: buf})
+	root := NewRootCmd()
+	root.SetArgs(append([]string{"resource", "-o", "text"}, args...))
+	err := root.ExecuteContext(ctx)
+	return buf.String(), err
+}
+
+func TestResourceCreate_CoercesSetValues(t *testing.T) {
+	var writes []string
+	var bodies []map[string]interface{}
+	server := resourceWriteServer(t, &writes, &bodies)
+	defer server.Close()
+
+	out, err := runResourceWrite(t, server, "", "create", "Task",
+		"--data", `{"Question":"Check fridge","Cost":1.5}`,
+		"--set", "employee=5", "--set", "Completed=false", "--set", "Question=Check freezer")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST /api/v1/resource/Task"}, writes)
+	assert.Equal(t, map[string]interface{}{
+		"Question":  "Check freezer",
+		"Employee":  5.0,
+		"Completed": false,
+		"Cost":      1.5,
+	}, bodies[0])
+	assert.Contains(t, out, "Created Task 77")
+}
+
+func TestResourceCreate_RejectsInvalidPayload(t *testing.T) {
+	var writes []string
+	var bodies []map[string]interface{}
+	server := resourceWriteServer(t, &writes, &bodies)
+	defer server.Close()
+
+	_, err := runResourceWrite(t, server, "", "create", "Task",
+		"--data", `{"question":"x","Employee":"five","DueDate":"tomorrow","Nope":1}`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Employee must be an integer, got "five"`)
+	assert.Contains(t, err.Error(), `DueDate must be a date (YYYY-MM-DD)`)
+	assert.Contains(t, err.Error(), `unknown field "Nope"`)
+	assert.Contains(t, err.Error(), `unknown field "question" (did you mean "Question"?)`)
+
+	_, err = runResourceWrite(t, server, "", "create", "Task", "--set", "Employee=abc")
+	require.EqualError(t, err, `Employee must be an integer, got "abc"`)
+
+	_, err = runResourceWrite(t, server, "", "create", "Task")
+	require.EqualError(t, err, "nothing to send: use --data or --set")
+	assert.Empty(t, writes)
+}
+
+func TestResourceUpdate_ReadsDataFromStdin(t *testing.T) {
+	var writes []string
+	var bodies []map[string]interface{}
+	server := resourceWriteServer(t, &writes, &bodies)
+	defer server.Close()
+
+	_, err := runResourceWrite(t, server, `{"DueDate":"2024-03-01","Completed":1}`, "update", "Task", "12", "--data", "@-", "-o", "json")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST /api/v1/resource/Task/12"}, writes)
+	assert.Equal(t, map[string]interface{}{"DueDate": "2024-03-01", "Completed": 1.0}, bodies[0])
+}
+
+func TestResourceDelete_Confirms(t *testing.T) {
+	var writes []string
+	var bodies []map[string]interface{}
+	server := resourceWriteServer(t, &writes, &bodies)
+	defer server.Close()
+
+	_, err := runResourceWrite(t, server, "n\n", "delete", "Task", "12")
+	require.EqualError(t, err, "operation cancelled")
+	assert.Empty(t, writes)
+
+	out, err := runResourceWrite(t, server, "", "delete", "Task", "12", "--yes")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DELETE /api/v1/resource/Task/12"}, writes)
+	assert.Contains(t, out, "Deleted Task 12")
+}
@@ -0,0 +1,401 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// Field kinds used to check payloads against ResourceInfo.Fields. Deputy
+// reports SQL-ish type names (Integer, VarChar, Bit, Date, ...).
+const (
+	fieldKindAny      = ""
+	fieldKindInt      = "integer"
+	fieldKindFloat    = "number"
+	fieldKindBool     = "boolean"
+	fieldKindString   = "string"
+	fieldKindDate     = "date (YYYY-MM-DD)"
+	fieldKindDateTime = "date-time"
+)
+
+// resourceFieldKind maps a field's INFO entry, either "Integer" or
+// {"type": "Integer"}, to a field kind. Unknown types accept any value.
+func resourceFieldKind(spec interface{}) string {
+	typeName, _ := spec.(string)
+	if m, ok := spec.(map[string]interface{}); ok {
+		typeName, _ = m["type"].(string)
+	}
+	switch strings.ToLower(typeName) {
+	case "integer", "int", "bigint", "smallint", "tinyint":
+		return fieldKindInt
+	case "float", "decimal", "double", "number", "money":
+		return fieldKindFloat
+	case "bit", "bool", "boolean":
+		return fieldKindBool
+	case "varchar", "string", "text", "blob", "char":
+		return fieldKindString
+	case "date":
+		return fieldKindDate
+	case "datetime", "timestamp", "time":
+		return fieldKindDateTime
+	}
+	return fieldKindAny
+}
+
+// resourceReadOnlyFields are maintained by Deputy on every resource.
+var resourceReadOnlyFields = map[string]bool{"Id": true, "Creator": true, "Created": true, "Modified": true}
+
+// checkResourceValue reports whether a decoded JSON value fits a field kind.
+func checkResourceValue(kind string, v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch kind {
+	case fieldKindInt:
+		f, ok := v.(float64)
+		return ok && f == float64(int64(f))
+	case fieldKindFloat:
+		_, ok := v.(float64)
+		return ok
+	case fieldKindBool:
+		switch b := v.(type) {
+		case bool:
+			return true
+		case float64:
+			return b == 0 || b == 1
+		}
+		return false
+	case fieldKindString:
+		_, ok := v.(string)
+		return ok
+	case fieldKindDate:
+		s, ok := v.(string)
+		if !ok || len(s) < len("2006-01-02") {
+			return false
+		}
+		_, err := time.Parse("2006-01-02", s[:10])
+		return err == nil
+	case fieldKindDateTime:
+		switch v.(type) {
+		case string, float64:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// coerceResourceValue converts a --set value to the field's kind so
+// `--set Employee=5` sends a number and `--set Active=false` a boolean.
+func coerceResourceValue(field, kind, raw string) (interface{}, error) {
+	switch kind {
+	case fieldKindInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be an integer, got %q", field, raw)
+		}
+		return n, nil
+	case fieldKindFloat:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number, got %q", field, raw)
+		}
+		return f, nil
+	case fieldKindBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false, got %q", field, raw)
+		}
+		return b, nil
+	case fieldKindDateTime:
+		if n, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return n, nil
+		}
+	}
+	return raw, nil
+}
+
+// readResourceData parses --data: inline JSON, @file, or @- for stdin.
+func readResourceData(value string, stdin io.Reader) (map[string]interface{}, error) {
+	if value == "" {
+		return map[string]interface{}{}, nil
+	}
+	data := []byte(value)
+	if strings.HasPrefix(value, "@") {
+		path := strings.TrimPrefix(value, "@")
+		var err error
+		if path == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	var payload map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("--data must be a JSON object: %w", err)
+	}
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	return payload, nil
+}
+
+// buildResourcePayload merges --data and --set (which wins) and checks every
+// field against the resource's INFO schema before anything is sent.
+func buildResourcePayload(info *api.ResourceInfo, dataFlag string, sets []string, stdin io.Reader) (map[string]interface{}, error) {
+	payload, err := readResourceData(dataFlag, stdin)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyResourceSets(info, payload, sets); err != nil {
+		return nil, err
+	}
+	if len(payload) == 0 {
+		return nil, errors.New("nothing to send: use --data or --set")
+	}
+	if err := validateResourcePayload(info, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// applyResourceSets writes --set Field=value pairs into payload, matching
+// field names case-insensitively and coercing values to the INFO type.
+func applyResourceSets(info *api.ResourceInfo, payload map[string]interface{}, sets []string) error {
+	fields := info.Fields
+	lookup := make(map[string]string, len(fields))
+	for name := range fields {
+		lookup[strings.ToLower(name)] = name
+	}
+	for _, set := range sets {
+		field, raw, ok := strings.Cut(set, "=")
+		if !ok || field == "" {
+			return fmt.Errorf("invalid --set %q (expected Field=value)", set)
+		}
+		if canonical, ok := lookup[strings.ToLower(field)]; ok {
+			field = canonical
+		}
+		value, err := coerceResourceValue(field, resourceFieldKind(fields[field]), raw)
+		if err != nil {
+			return err
+		}
+		payload[field] = value
+	}
+	return nil
+}
+
+// validateResourcePayload checks field names and value types against the
+// resource's INFO schema, reporting every problem at once.
+func validateResourcePayload(info *api.ResourceInfo, payload map[string]interface{}) error {
+	fields := info.Fields
+	lookup := make(map[string]string, len(fields))
+	for name := range fields {
+		lookup[strings.ToLower(name)] = name
+	}
+
+	// Round-trip so --set values are checked the same way as JSON input.
+	normalized, ok := normalizeJSONValue(payload).(map[string]interface{})
+	if !ok {
+		return errors.New("payload must be a JSON object")
+	}
+
+	var problems []string
+	for _, name := range sortedKeys(normalized) {
+		spec, known := fields[name]
+		if !known {
+			msg := fmt.Sprintf("unknown field %q", name)
+			if canonical, ok := lookup[strings.ToLower(name)]; ok {
+				msg += fmt.Sprintf(" (did you mean %q?)", canonical)
+			}
+			problems = append(problems, msg)
+			continue
+		}
+		if resourceReadOnlyFields[name] {
+			problems = append(problems, name+" cannot be set")
+			continue
+		}
+		kind := resourceFieldKind(spec)
+		if !checkResourceValue(kind, normalized[name]) {
+			problems = append(problems, fmt.Sprintf("%s must be %s, got %s", name, kindArticle(kind), orgValue(normalized[name])))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid %s payload: %s", info.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func kindArticle(kind string) string {
+	if kind == fieldKindInt {
+		return "an " + kind
+	}
+	return "a " + kind
+}
+
+func printResourceRecord(w io.Writer, record map[string]interface{}) {
+	keys := make([]string, 0, len(record))
+	for k := range record {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(w, "%s: %v\n", k, record[k])
+	}
+}
+
+const resourcePayloadHelp = `Fields come from --data (inline JSON, @file.json, or @- for stdin) and
+--set Field=value (repeatable, applied on top of --data). Field names and
+value types are checked against 'deputy resource info <ResourceName>' before
+anything is sent; --set values are converted to the field's type.`
+
+func newResourceCreateCmd() *cobra.Command {
+	var dataFlag string
+	var sets []string
+
+	cmd := &cobra.Command{
+		Use:   "create <ResourceName>",
+		Short: "Create a record of any resource",
+		Long:  "Create a record of any Deputy resource.\n\n" + resourcePayloadHelp,
+		Example: `  deputy resource create Task --set Question="Check fridge temps" --set Employee=5
+  deputy resource create Contact --data @contact.json`,
+		Args: RequireArg("ResourceName"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			info, err := client.Resource(args[0]).Info(ctx)
+			if err != nil {
+				return err
+			}
+			payload, err := buildResourcePayload(info, dataFlag, sets, iocontext.FromContext(ctx).In)
+			if err != nil {
+				return err
+			}
+
+			result, err := client.Resource(args[0]).Create(ctx, payload)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).Output(result)
+			}
+			out := iocontext.FromContext(ctx).Out
+			_, _ = fmt.Fprintf(out, "Created %s %s\n", args[0], snapshotKeyLabel(result["Id"]))
+			printResourceRecord(out, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dataFlag, "data", "", "JSON object, @file.json, or @- for stdin")
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "Field=value (can be repeated)")
+
+	return cmd
+}
+
+func newResourceUpdateCmd() *cobra.Command {
+	var dataFlag string
+	var sets []string
+
+	cmd := &cobra.Command{
+		Use:   "update <ResourceName> <id>",
+		Short: "Update fields of any resource",
+		Long:  "Update a record of any Deputy resource. Fields not given are left unchanged.\n\n" + resourcePayloadHelp,
+		Example: `  deputy resource update Memo 12 --set Content="Updated text"
+  deputy resource update EmployeeAvailability 40 --data @availability.json`,
+		Args: RequireArgs("ResourceName", "id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid ID: %s", args[1])
+			}
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			info, err := client.Resource(args[0]).Info(ctx)
+			if err != nil {
+				return err
+			}
+			payload, err := buildResourcePayload(info, dataFlag, sets, iocontext.FromContext(ctx).In)
+			if err != nil {
+				return err
+			}
+
+			result, err := client.Resource(args[0]).Update(ctx, id, payload)
+			if err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).Output(result)
+			}
+			out := iocontext.FromContext(ctx).Out
+			_, _ = fmt.Fprintf(out, "Updated %s %d\n", args[0], id)
+			printResourceRecord(out, result)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dataFlag, "data", "", "JSON object, @file.json, or @- for stdin")
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "Field=value (can be repeated)")
+
+	return cmd
+}
+
+func newResourceDeleteCmd() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <ResourceName> <id>",
+		Short: "Delete a record of any resource",
+		Args:  RequireArgs("ResourceName", "id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid ID: %s", args[1])
+			}
+
+			if err := confirmDestructive(cmd.Context(), yes, fmt.Sprintf("Are you sure you want to delete %s %d?", args[0], id)); err != nil {
+				return err
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if err := client.Resource(args[0]).Delete(cmd.Context(), id); err != nil {
+				return err
+			}
+
+			io := iocontext.FromContext(cmd.Context())
+			_, _ = fmt.Fprintf(io.Out, "Deleted %s %d\n", args[0], id)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	_ "embed"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/audit"
 	"github.com/salmonumbrella/deputy-cli/internal/config"
 	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
 	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
@@ -41,14 +44,16 @@ type ExecuteResult struct {
 
 func NewRootCmd() *cobra.Command {
 	var fl struct {
-		Output     string
-		Debug      bool
-		Query      string
-		Raw        bool
-		NoColor    bool
-		NoKeychain bool
+		Output      string
+		Debug       bool
+		Query       string
+		Raw         bool
+		NoColor     bool
+		NoKeychain  bool
+		DryRun      bool
+		Rate        string
+		MaxInFlight int
 	}
-
 	cmd := &cobra.Command{
 		Use:     "deputy",
 		Short:   "CLI for Deputy workforce management API",
@@ -90,6 +95,37 @@ func NewRootCmd() *cobra.Command {
 			ctx = outfmt.WithRaw(ctx, fl.Raw)
 			ctx = WithDebug(ctx, fl.Debug)
 			ctx = WithNoKeychain(ctx, fl.NoKeychain)
+			if fl.DryRun && DryRunFromContext(ctx) == nil {
+				// Mutations are captured rather than sent, so the command's own
+				// success messages would be misleading. Hide them; executeRoot
+				// prints the plan to the real output afterwards. Shortcut
+				// commands re-enter this root and keep the outer recorder.
+				realIO := iocontext.FromContext(ctx)
+				ctx = WithDryRun(ctx, &api.DryRun{})
+				ctx = context.WithValue(ctx, dryRunIOKey{}, realIO)
+				ctx = iocontext.WithIO(ctx, &iocontext.IO{In: realIO.In, Out: io.Discard, ErrOut: realIO.ErrOut})
+			}
+			if RateLimiterFromContext(ctx) == nil {
+				rateFlag := fl.Rate
+				if !cmd.Flags().Changed("rate") {
+					rateFlag = os.Getenv("DEPUTY_RATE_LIMIT")
+				}
+				rate, err := parseRateLimit(rateFlag)
+				if err != nil {
+					return err
+				}
+				if fl.MaxInFlight < 0 {
+					return fmt.Errorf("invalid --max-in-flight %d (expected 0 or more)", fl.MaxInFlight)
+				}
+				limiter := api.NewRateLimiter(rate, fl.MaxInFlight)
+				if fl.Debug {
+					_, _ = fmt.Fprintf(iocontext.FromContext(ctx).ErrOut, "Debug: rate limiter: %s\n", limiter)
+				}
+				ctx = WithRateLimiter(ctx, limiter)
+			}
+			if AuditFromContext(ctx) == nil {
+				ctx = WithAudit(ctx, audit.NewRecorder(auditCommandLine(cmd, args), iocontext.FromContext(ctx).ErrOut))
+			}
 			cmd.SetContext(ctx)
 			return nil
 		},
@@ -105,6 +141,9 @@ func NewRootCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(&fl.Raw, "raw", false, "Output JSON Lines (one object per line)")
 	cmd.PersistentFlags().BoolVar(&fl.NoColor, "no-color", false, "Disable colored output")
 	cmd.PersistentFlags().BoolVar(&fl.NoKeychain, "no-keychain", false, "Do not read credentials from keychain (use env/.env only)")
+	cmd.PersistentFlags().StringVar(&fl.Rate, "rate", "", "Maximum API requests per second, or N/m per minute (env DEPUTY_RATE_LIMIT; default unlimited)")
+	cmd.PersistentFlags().IntVar(&fl.MaxInFlight, "max-in-flight", 8, "Maximum concurrent API requests (0 = unlimited)")
+	cmd.PersistentFlags().BoolVar(&fl.DryRun, "dry-run", false, "Show the create/update/delete requests a command would send without sending them")
 
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newCompletionCmd())
@@ -114,16 +153,28 @@ func NewRootCmd() *cobra.Command {
 	cmd.AddCommand(newRostersCmd())
 	cmd.AddCommand(newLocationsCmd())
 	cmd.AddCommand(newLeaveCmd())
+	cmd.AddCommand(newAvailabilityCmd())
+	cmd.AddCommand(newTrainingCmd())
 	cmd.AddCommand(newDepartmentsCmd())
 	cmd.AddCommand(newPayCmd())
 	cmd.AddCommand(newResourceCmd())
 	cmd.AddCommand(newMeCmd())
 	cmd.AddCommand(newWebhooksCmd())
 	cmd.AddCommand(newSalesCmd())
+	cmd.AddCommand(newReportsCmd())
 	cmd.AddCommand(newManagementCmd())
+	cmd.AddCommand(newAuditCmd())
+	cmd.AddCommand(newUndoCmd())
+	cmd.AddCommand(newPlanCmd())
+	cmd.AddCommand(newApplyCmd())
+	cmd.AddCommand(newSnapshotCmd())
+	cmd.AddCommand(newBatchCmd())
 	cmd.AddCommand(newListCmd())
 	cmd.AddCommand(newGetCmd())
 
+	registerDynamicCompletions(cmd)
+	registerEntityResolution(cmd)
+
 	defaultHelp := cmd.HelpFunc()
 	cmd.SetHelpFunc(func(c *cobra.Command, args []string) {
 		if c.Name() == cmd.Name() && !c.HasParent() {
@@ -159,9 +210,66 @@ func Execute() ExecuteResult {
 		return nil
 	}
 
-	result.Err = root.ExecuteContext(context.Background())
+	result.Err = executeRoot(context.Background(), root)
 	if result.Err != nil {
 		result.ExitCode = ExitCodeFromError(result.Err)
 	}
 	return result
 }
+
+// dryRunIOKey holds the real IO that --dry-run hid from the command.
+type dryRunIOKey struct{}
+
+// executeRoot runs root and then prints the --dry-run plan, if one was
+// captured. This happens here rather than in a PersistentPostRunE because
+// cobra skips post-run hooks when RunE fails, and a command that fails part
+// way through should still show the requests it had planned.
+func executeRoot(ctx context.Context, root *cobra.Command) error {
+	cmd, err := root.ExecuteContextC(ctx)
+	if cmd == nil || cmd.Context() == nil {
+		return err
+	}
+	plan := DryRunFromContext(cmd.Context())
+	realIO := dryRunIO(cmd.Context())
+	if plan == nil || realIO == nil {
+		return err
+	}
+	if printErr := printDryRunPlan(iocontext.WithIO(cmd.Context(), realIO), plan.Requests()); printErr != nil && err == nil {
+		err = printErr
+	}
+	return err
+}
+
+// dryRunIO returns the real IO that --dry-run hid from the command, or nil
+// outside a dry run. Commands use it for reports that stay meaningful when
+// nothing is sent.
+func dryRunIO(ctx context.Context) *iocontext.IO {
+	realIO, _ := ctx.Value(dryRunIOKey{}).(*iocontext.IO)
+	return realIO
+}
+
+// printDryRunPlan reports the requests captured by --dry-run. JSON output uses
+// the standard items/meta envelope so agents can inspect the plan.
+func printDryRunPlan(ctx context.Context, requests []api.PlannedRequest) error {
+	if outfmt.GetFormat(ctx) == "json" {
+		f := outfmt.New(ctx)
+		return f.OutputWithMeta(requests, map[string]any{
+			"dryRun": true,
+			"count":  len(requests),
+		})
+	}
+
+	out := iocontext.FromContext(ctx).Out
+	if len(requests) == 0 {
+		_, _ = fmt.Fprintln(out, "Dry run: no changes would be made")
+		return nil
+	}
+	_, _ = fmt.Fprintf(out, "Dry run: %d request(s) would be sent\n", len(requests))
+	for _, r := range requests {
+		_, _ = fmt.Fprintf(out, "\n%s %s\n", r.Method, r.URL)
+		if len(r.Body) > 0 {
+			_, _ = fmt.Fprintf(out, "  %s\n", r.Body)
+		}
+	}
+	return nil
+}
@@ -84,6 +84,15 @@ func TestNewRootCmd(t *testing.T) {
 			"webhooks",
 			"sales",
 			"management",
+			"reports",
+			"audit",
+			"undo",
+			"plan",
+			"apply",
+			"snapshot",
+			"batch",
+			"availability",
+			"training",
 		}
 		for _, expected := range expectedCmds {
 			assert.Contains(t, names, expected, "missing subcommand: %s", expected)
@@ -92,9 +101,10 @@ func TestNewRootCmd(t *testing.T) {
 
 	t.Run("has correct subcommand count", func(t *testing.T) {
 		cmd := NewRootCmd()
-		// 17 subcommands: version, completion, auth, employees, timesheets, rosters, locations,
-		// leave, departments, pay, resource, me, webhooks, sales, management, list, get
-		assert.Len(t, cmd.Commands(), 17)
+		// 26 subcommands: version, completion, auth, employees, timesheets, rosters, locations,
+		// leave, availability, training, departments, pay, resource, me, webhooks, sales, reports,
+		// management, audit, undo, plan, apply, snapshot, batch, list, get
+		assert.Len(t, cmd.Commands(), 26)
 	})
 
 	t.Run("help executes without error", func(t *testing.T) {
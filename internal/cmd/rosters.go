@@ -26,6 +26,7 @@ func newRostersCmd() *cobra.Command {
 	cmd.AddCommand(newRostersPublishCmd())
 	cmd.AddCommand(newRostersDiscardCmd())
 	cmd.AddCommand(newRostersSwapCmd())
+	cmd.AddCommand(newRostersTemplateCmd())
 
 	return cmd
 }
@@ -342,17 +343,19 @@ func newRostersDiscardCmd() *cobra.Command {
 }
 
 func newRostersSwapCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "swap <roster-id>",
-		Short: "List rosters available for swap",
+		Short: "List swap candidates or manage shift swaps",
 		Long: `List rosters that can be swapped with the specified roster.
 
-This command queries the Deputy API for shift swap candidates. It does not
-perform the actual swap - that must be done through the Deputy web interface
-or by using the resource API to update roster assignments.
+Use the subcommands to request a swap between two rosters and for managers
+to review, approve or reject pending swap requests.
 
 Example:
-  deputy rosters swap 12345`,
+  deputy rosters swap 12345
+  deputy rosters swap request 12345 --with 12399
+  deputy rosters swap pending
+  deputy rosters swap approve 77`,
 		Args: RequireArg("roster-id"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id, err := strconv.Atoi(args[0])
@@ -393,4 +396,11 @@ Example:
 			return nil
 		},
 	}
+
+	cmd.AddCommand(newRostersSwapRequestCmd())
+	cmd.AddCommand(newRostersSwapApproveCmd())
+	cmd.AddCommand(newRostersSwapRejectCmd())
+	cmd.AddCommand(newRostersSwapPendingCmd())
+
+	return cmd
 }
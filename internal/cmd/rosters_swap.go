@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+func newRostersSwapRequestCmd() *cobra.Command {
+	var withRoster int
+	var comment string
+
+	cmd := &cobra.Command{
+		Use:   "request <roster-id>",
+		Short: "Request a swap between two rosters",
+		Long: `Request that two rostered shifts be swapped.
+
+Use 'deputy rosters swap <roster-id>' to find rosters eligible for swapping.`,
+		Example: `  deputy rosters swap request 12345 --with 12399 --comment "Family event"`,
+		Args:    RequireArg("roster-id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rosterID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid roster ID: %s", args[0])
+			}
+			if withRoster == 0 {
+				return errors.New("--with is required")
+			}
+			if withRoster == rosterID {
+				return errors.New("--with must be a different roster")
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			swap, err := client.Rosters().RequestSwap(cmd.Context(), &api.CreateRosterSwapInput{
+				Roster:     rosterID,
+				SwapRoster: withRoster,
+				Comment:    comment,
+			})
+			if err != nil {
+				return err
+			}
+
+			return outputSwapResult(cmd, swap, fmt.Sprintf("Requested swap %d between rosters %d and %d", swap.Id, rosterID, withRoster))
+		},
+	}
+
+	cmd.Flags().IntVar(&withRoster, "with", 0, "Roster ID to swap with (required)")
+	cmd.Flags().StringVar(&comment, "comment", "", "Reason for the swap")
+
+	return cmd
+}
+
+func newRostersSwapApproveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve <swap-id>",
+		Short: "Approve a pending shift swap",
+		Args:  RequireArg("swap-id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid swap ID: %s", args[0])
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			swap, err := client.Rosters().ApproveSwap(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			return outputSwapResult(cmd, swap, fmt.Sprintf("Swap %d approved", id))
+		},
+	}
+}
+
+func newRostersSwapRejectCmd() *cobra.Command {
+	var comment string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "reject <swap-id>",
+		Short: "Reject a pending shift swap",
+		Args:  RequireArg("swap-id"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid swap ID: %s", args[0])
+			}
+
+			if err := confirmDestructive(cmd.Context(), yes, fmt.Sprintf("Are you sure you want to reject swap %d?", id)); err != nil {
+				return err
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			swap, err := client.Rosters().RejectSwap(cmd.Context(), id, comment)
+			if err != nil {
+				return err
+			}
+
+			return outputSwapResult(cmd, swap, fmt.Sprintf("Swap %d rejected", id))
+		},
+	}
+
+	cmd.Flags().StringVar(&comment, "comment", "", "Reason for rejecting")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func newRostersSwapPendingCmd() *cobra.Command {
+	var limit, offset int
+	var failEmpty bool
+
+	cmd := &cobra.Command{
+		Use:   "pending",
+		Short: "List swap requests awaiting approval",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			swaps, err := client.Rosters().PendingSwaps(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			swaps = applyPagination(swaps, offset, limit)
+
+			format := outfmt.GetFormat(cmd.Context())
+			if format == "json" {
+				ctx := outfmt.WithLimit(cmd.Context(), limit)
+				ctx = outfmt.WithOffset(ctx, offset)
+				ctx = outfmt.WithFailEmpty(ctx, failEmpty)
+				f := outfmt.New(ctx)
+				return f.OutputList(swaps)
+			}
+
+			f := outfmt.New(cmd.Context())
+			f.StartTable([]string{"ID", "ROSTER", "SWAP ROSTER", "EMPLOYEE", "SWAP EMPLOYEE", "STATUS", "COMMENT"})
+			for _, s := range swaps {
+				f.Row(
+					strconv.Itoa(s.Id),
+					strconv.Itoa(s.Roster),
+					strconv.Itoa(s.SwapRoster),
+					strconv.Itoa(s.Employee),
+					strconv.Itoa(s.SwapEmployee),
+					swapStatusText(s.Status),
+					s.Comment,
+				)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of results (0 = unlimited)")
+	cmd.Flags().IntVar(&offset, "offset", 0, "Number of results to skip")
+	cmd.Flags().BoolVar(&failEmpty, "fail-empty", false, "Exit 4 when results are empty (JSON mode)")
+
+	return cmd
+}
+
+// outputSwapResult reports a swap action. JSON output uses the same
+// items/meta envelope as list commands so agents can treat every swap
+// command uniformly.
+func outputSwapResult(cmd *cobra.Command, swap *api.RosterSwap, message string) error {
+	format := outfmt.GetFormat(cmd.Context())
+	if format == "json" {
+		f := outfmt.New(cmd.Context())
+		return f.OutputList([]api.RosterSwap{*swap})
+	}
+
+	io := iocontext.FromContext(cmd.Context())
+	_, _ = fmt.Fprintln(io.Out, message)
+	return nil
+}
+
+func swapStatusText(status int) string {
+	switch status {
+	case api.RosterSwapPending:
+		return "Pending"
+	case api.RosterSwapApproved:
+		return "Approved"
+	case api.RosterSwapRejected:
+		return "Rejected"
+	default:
+		return fmt.Sprintf("Unknown (%d)", status)
+	}
+}
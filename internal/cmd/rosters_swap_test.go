@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+func TestRostersSwapSubcommands_ViaRootCmd(t *testing.T) {
+	root := NewRootCmd()
+	buf := &bytes.Buffer{}
+	root.SetOut(buf)
+	root.SetErr(buf)
+	root.SetArgs([]string{"rosters", "swap", "--help"})
+
+	require.NoError(t, root.Execute())
+	for _, sub := range []string{"request", "approve", "reject", "pending"} {
+		assert.Contains(t, buf.String(), sub)
+	}
+}
+
+func TestRostersSwapRequestCommand(t *testing.T) {
+	t.Run("requires --with", func(t *testing.T) {
+		cmd := newRostersSwapRequestCmd()
+		cmd.SetContext(context.Background())
+		cmd.SetArgs([]string{"10"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--with is required")
+	})
+
+	t.Run("rejects swapping with itself", func(t *testing.T) {
+		cmd := newRostersSwapRequestCmd()
+		cmd.SetContext(context.Background())
+		cmd.SetArgs([]string{"10", "--with", "10"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "different roster")
+	})
+
+	t.Run("outputs list envelope in json", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/v1/resource/RosterSwap", r.URL.Path)
+			_ = json.NewEncoder(w).Encode(api.RosterSwap{Id: 3, Roster: 10, SwapRoster: 11})
+		}))
+		defer server.Close()
+
+		buf := &bytes.Buffer{}
+		ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+		ctx = outfmt.WithFormat(ctx, "json")
+		ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+		cmd := newRostersSwapRequestCmd()
+		cmd.SetContext(ctx)
+		cmd.SetArgs([]string{"10", "--with", "11"})
+		require.NoError(t, cmd.Execute())
+
+		var out struct {
+			Items []api.RosterSwap `json:"items"`
+			Meta  map[string]any   `json:"meta"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		require.Len(t, out.Items, 1)
+		assert.Equal(t, 3, out.Items[0].Id)
+		assert.Equal(t, float64(1), out.Meta["count"])
+	})
+}
+
+func TestRostersSwapApproveRejectCommands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/RosterSwap/7", r.URL.Path)
+		var input api.UpdateRosterSwapInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		_ = json.NewEncoder(w).Encode(api.RosterSwap{Id: 7, Status: input.Status})
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	approve := newRostersSwapApproveCmd()
+	approve.SetContext(ctx)
+	approve.SetArgs([]string{"7"})
+	require.NoError(t, approve.Execute())
+	assert.Contains(t, buf.String(), "Swap 7 approved")
+
+	reject := newRostersSwapRejectCmd()
+	reject.SetContext(ctx)
+	reject.SetArgs([]string{"7", "--yes", "--comment", "No cover"})
+	require.NoError(t, reject.Execute())
+	assert.Contains(t, buf.String(), "Swap 7 rejected")
+
+	t.Run("invalid id", func(t *testing.T) {
+		cmd := newRostersSwapApproveCmd()
+		cmd.SetContext(ctx)
+		cmd.SetArgs([]string{"abc"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid swap ID")
+	})
+}
+
+func TestRostersSwapPendingCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/resource/RosterSwap/QUERY", r.URL.Path)
+		_ = json.NewEncoder(w).Encode([]api.RosterSwap{{Id: 1, Roster: 10, SwapRoster: 11, Employee: 5, SwapEmployee: 6}})
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newRostersSwapPendingCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "SWAP ROSTER")
+	assert.Contains(t, buf.String(), "Pending")
+}
@@ -0,0 +1,592 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/config"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// RosterTemplate is the on-disk (YAML) representation of a saved roster week.
+// Shifts are stored relative to the first day of the source range so a
+// template can be re-applied to any week.
+type RosterTemplate struct {
+	Name     string          `yaml:"name" json:"name"`
+	Location int             `yaml:"location" json:"location"`
+	Timezone string          `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	Source   *TemplateSource `yaml:"source,omitempty" json:"source,omitempty"`
+	Shifts   []TemplateShift `yaml:"shifts" json:"shifts"`
+}
+
+// TemplateSource records the date range a template was saved from.
+type TemplateSource struct {
+	From string `yaml:"from" json:"from"`
+	To   string `yaml:"to" json:"to"`
+}
+
+// TemplateShift is a single shift within a roster template. Start and End are
+// wall-clock times (HH:MM) in the location's timezone; an End at or before
+// Start means the shift finishes the following day.
+type TemplateShift struct {
+	Day          int    `yaml:"day" json:"day"`
+	Start        string `yaml:"start" json:"start"`
+	End          string `yaml:"end" json:"end"`
+	Mealbreak    string `yaml:"mealbreak,omitempty" json:"mealbreak,omitempty"`
+	Employee     int    `yaml:"employee,omitempty" json:"employee,omitempty"`
+	EmployeeName string `yaml:"employeeName,omitempty" json:"employeeName,omitempty"`
+	Area         int    `yaml:"area" json:"area"`
+	AreaName     string `yaml:"areaName,omitempty" json:"areaName,omitempty"`
+	Open         bool   `yaml:"open,omitempty" json:"open,omitempty"`
+	Comment      string `yaml:"comment,omitempty" json:"comment,omitempty"`
+}
+
+// TemplateApplyResult describes what happened to each template shift on apply.
+type TemplateApplyResult struct {
+	Day       int    `json:"day"`
+	Date      string `json:"date"`
+	Employee  int    `json:"employee,omitempty"`
+	Area      int    `json:"area"`
+	StartTime int64  `json:"startTime"`
+	EndTime   int64  `json:"endTime"`
+	Roster    int    `json:"roster,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+var templateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func newRostersTemplateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "template",
+		Aliases: []string{"templates", "tpl"},
+		Short:   "Save and apply reusable roster weeks",
+		Long: `Save a week of shifts as an editable YAML template and re-apply it later.
+
+Templates are stored in ~/.config/deputy/templates/rosters/<name>.yaml
+(or under DEPUTY_CONFIG_DIR) and can be edited and versioned by hand.`,
+	}
+
+	cmd.AddCommand(newRostersTemplateSaveCmd())
+	cmd.AddCommand(newRostersTemplateApplyCmd())
+	cmd.AddCommand(newRostersTemplateListCmd())
+
+	return cmd
+}
+
+func newRostersTemplateSaveCmd() *cobra.Command {
+	var fromDate, toDate string
+	var locationID int
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "save <name>",
+		Short: "Save shifts from a date range as a template",
+		Example: `  deputy rosters template save weekday-core --from-date 2026-10-05 --to-date 2026-10-11 --location 3
+  deputy rosters template save weekday-core --from-date 2026-10-05 --to-date 2026-10-11 --location 3 --force`,
+		Args: RequireArg("name"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if !templateNamePattern.MatchString(name) {
+				return fmt.Errorf("invalid template name %q (use letters, digits, '.', '_' or '-')", name)
+			}
+			if fromDate == "" || toDate == "" {
+				return errors.New("--from-date and --to-date are required")
+			}
+			if locationID == 0 {
+				return errors.New("--location is required")
+			}
+			from, _, err := parseDateFlag(fromDate, "--from-date")
+			if err != nil {
+				return err
+			}
+			to, _, err := parseDateFlag(toDate, "--to-date")
+			if err != nil {
+				return err
+			}
+			if from.After(to) {
+				return errors.New("--from-date must be on or before --to-date")
+			}
+
+			path := rosterTemplatePath(name)
+			if _, err := os.Stat(path); err == nil && !force {
+				return fmt.Errorf("template %q already exists (use --force to overwrite)", name)
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			tz, err := locationTimezone(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+
+			areaNames, err := locationAreas(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+
+			search, err := dateRangeSearch(fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			rosters, err := client.Rosters().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+
+			employeeNames, err := employeeDisplayNames(ctx, client)
+			if err != nil {
+				return err
+			}
+
+			tpl := &RosterTemplate{
+				Name:     name,
+				Location: locationID,
+				Timezone: tz.String(),
+				Source:   &TemplateSource{From: fromDate, To: toDate},
+				Shifts:   rostersToTemplateShifts(rosters, from, tz, areaNames, employeeNames),
+			}
+
+			if err := writeRosterTemplate(path, tpl); err != nil {
+				return err
+			}
+
+			format := outfmt.GetFormat(ctx)
+			if format == "json" {
+				f := outfmt.New(ctx)
+				return f.Output(map[string]any{
+					"name":   name,
+					"path":   path,
+					"shifts": len(tpl.Shifts),
+				})
+			}
+
+			io := iocontext.FromContext(ctx)
+			_, _ = fmt.Fprintf(io.Out, "Saved template %q with %d shift(s) to %s\n", name, len(tpl.Shifts), path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fromDate, "from-date", "", "First day of the source range (YYYY-MM-DD, required)")
+	cmd.Flags().StringVar(&toDate, "to-date", "", "Last day of the source range (YYYY-MM-DD, required)")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Location ID (required)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing template")
+
+	return cmd
+}
+
+func newRostersTemplateApplyCmd() *cobra.Command {
+	var weekOf string
+	var locationID int
+	var publish bool
+
+	cmd := &cobra.Command{
+		Use:   "apply <name>",
+		Short: "Create shifts from a template for a given week",
+		Long: `Create shifts from a saved template.
+
+Day 0 of the template is placed on --week-of. Times are interpreted in the
+location's timezone. Employees with approved or pending leave on a shift's
+date are skipped and reported.
+
+When --location differs from the template's location, each template area is
+matched by name to an area at the new location; apply stops if any area has
+no match. Shifts that fail to create are reported and the rest still go
+ahead.`,
+		Example: `  deputy rosters template apply weekday-core --week-of 2026-11-02
+  deputy rosters template apply weekday-core --week-of 2026-11-02 --location 4 --publish`,
+		Args: RequireArg("name"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if weekOf == "" {
+				return errors.New("--week-of is required")
+			}
+			start, _, err := parseDateFlag(weekOf, "--week-of")
+			if err != nil {
+				return err
+			}
+
+			tpl, err := readRosterTemplate(rosterTemplatePath(args[0]))
+			if err != nil {
+				return err
+			}
+			if locationID == 0 {
+				locationID = tpl.Location
+			}
+			if locationID == 0 {
+				return errors.New("--location is required (template has no location)")
+			}
+			if err := validateTemplateShifts(tpl.Shifts); err != nil {
+				return err
+			}
+
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			tz, err := locationTimezone(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+
+			lastDay := 0
+			for _, s := range tpl.Shifts {
+				if s.Day > lastDay {
+					lastDay = s.Day
+				}
+			}
+			rangeStart := start.Format("2006-01-02")
+			rangeEnd := start.AddDate(0, 0, lastDay).Format("2006-01-02")
+
+			var areaMap map[int]int
+			if tpl.Location != 0 && locationID != tpl.Location {
+				targetAreas, err := locationAreas(ctx, client, locationID)
+				if err != nil {
+					return err
+				}
+				areaMap, err = templateAreaMap(tpl.Shifts, targetAreas, locationID)
+				if err != nil {
+					return err
+				}
+			}
+
+			leaves, err := client.Leave().QueryAll(ctx, &api.LeaveQueryInput{
+				Search: map[string]interface{}{
+					"s1": map[string]interface{}{"field": "DateStart", "type": "le", "data": rangeEnd},
+					"s2": map[string]interface{}{"field": "DateEnd", "type": "ge", "data": rangeStart},
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			results := make([]TemplateApplyResult, 0, len(tpl.Shifts))
+			for _, shift := range tpl.Shifts {
+				startTS, endTS, date, err := templateShiftTimes(shift, start, tz)
+				if err != nil {
+					return err
+				}
+				area := shift.Area
+				if areaMap != nil {
+					area = areaMap[shift.Area]
+				}
+				result := TemplateApplyResult{
+					Day:       shift.Day,
+					Date:      date,
+					Employee:  shift.Employee,
+					Area:      area,
+					StartTime: startTS,
+					EndTime:   endTS,
+				}
+
+				if shift.Employee != 0 {
+					if leave := employeeOnLeave(leaves, shift.Employee, date); leave != nil {
+						result.Status = "skipped"
+						result.Reason = "employee on approved leave"
+						if leave.Status == 0 {
+							result.Reason = "employee has leave awaiting approval"
+						}
+						results = append(results, result)
+						continue
+					}
+				}
+
+				roster, err := client.Rosters().Create(ctx, &api.CreateRosterInput{
+					Employee:        shift.Employee,
+					OperationalUnit: area,
+					StartTime:       startTS,
+					EndTime:         endTS,
+					Mealbreak:       shift.Mealbreak,
+					Comment:         shift.Comment,
+					Open:            shift.Open || shift.Employee == 0,
+					Publish:         publish,
+				})
+				if err != nil {
+					result.Status = "failed"
+					result.Reason = err.Error()
+					results = append(results, result)
+					continue
+				}
+				result.Roster = roster.Id
+				result.Status = "created"
+				results = append(results, result)
+			}
+
+			counts := map[string]int{}
+			for _, r := range results {
+				counts[r.Status]++
+			}
+			failedErr := func() error {
+				if counts["failed"] > 0 {
+					return fmt.Errorf("%d of %d shift(s) failed to create", counts["failed"], len(results))
+				}
+				return nil
+			}
+
+			format := outfmt.GetFormat(ctx)
+			if format == "json" {
+				f := outfmt.New(ctx)
+				if err := f.OutputList(results); err != nil {
+					return err
+				}
+				return failedErr()
+			}
+
+			io := iocontext.FromContext(ctx)
+			f := outfmt.New(ctx)
+			f.StartTable([]string{"DATE", "START", "END", "EMPLOYEE", "AREA", "STATUS", "ROSTER"})
+			for _, r := range results {
+				roster := "-"
+				status := r.Status
+				if r.Status == "created" {
+					roster = strconv.Itoa(r.Roster)
+				} else {
+					status = r.Status + " (" + r.Reason + ")"
+				}
+				f.Row(
+					r.Date,
+					time.Unix(r.StartTime, 0).In(tz).Format("15:04"),
+					time.Unix(r.EndTime, 0).In(tz).Format("15:04"),
+					strconv.Itoa(r.Employee),
+					strconv.Itoa(r.Area),
+					status,
+					roster,
+				)
+			}
+			f.EndTable()
+			_, _ = fmt.Fprintf(io.Out, "\nCreated %d shift(s), skipped %d, failed %d\n", counts["created"], counts["skipped"], counts["failed"])
+			return failedErr()
+		},
+	}
+
+	cmd.Flags().StringVar(&weekOf, "week-of", "", "Date that template day 0 maps to (YYYY-MM-DD, required)")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Location ID (defaults to the template's location)")
+	cmd.Flags().BoolVar(&publish, "publish", false, "Publish created shifts immediately")
+
+	return cmd
+}
+
+func newRostersTemplateListCmd() *cobra.Command {
+	var failEmpty bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved roster templates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := os.ReadDir(config.RosterTemplatesDir())
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+
+			templates := make([]RosterTemplate, 0, len(entries))
+			for _, entry := range entries {
+				if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+					continue
+				}
+				tpl, err := readRosterTemplate(filepath.Join(config.RosterTemplatesDir(), entry.Name()))
+				if err != nil {
+					return err
+				}
+				templates = append(templates, *tpl)
+			}
+
+			format := outfmt.GetFormat(cmd.Context())
+			if format == "json" {
+				ctx := outfmt.WithFailEmpty(cmd.Context(), failEmpty)
+				f := outfmt.New(ctx)
+				return f.OutputList(templates)
+			}
+
+			f := outfmt.New(cmd.Context())
+			f.StartTable([]string{"NAME", "LOCATION", "SHIFTS", "SOURCE"})
+			for _, t := range templates {
+				source := ""
+				if t.Source != nil {
+					source = t.Source.From + " to " + t.Source.To
+				}
+				f.Row(t.Name, strconv.Itoa(t.Location), strconv.Itoa(len(t.Shifts)), source)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&failEmpty, "fail-empty", false, "Exit 4 when results are empty (JSON mode)")
+
+	return cmd
+}
+
+func rosterTemplatePath(name string) string {
+	return filepath.Join(config.RosterTemplatesDir(), name+".yaml")
+}
+
+func writeRosterTemplate(path string, tpl *RosterTemplate) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(tpl)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func readRosterTemplate(path string) (*RosterTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			name := strings.TrimSuffix(filepath.Base(path), ".yaml")
+			return nil, fmt.Errorf("template %q not found (run 'deputy rosters template list')", name)
+		}
+		return nil, err
+	}
+	var tpl RosterTemplate
+	if err := yaml.Unmarshal(data, &tpl); err != nil {
+		return nil, fmt.Errorf("parse template %s: %w", path, err)
+	}
+	return &tpl, nil
+}
+
+// rostersToTemplateShifts converts rosters in the given areas into template
+// shifts with day offsets relative to from, sorted by day and start time.
+func rostersToTemplateShifts(rosters []api.Roster, from time.Time, tz *time.Location, areaNames, employeeNames map[int]string) []TemplateShift {
+	origin := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, tz)
+
+	shifts := make([]TemplateShift, 0, len(rosters))
+	for _, r := range rosters {
+		areaName, ok := areaNames[r.OperationalUnit]
+		if !ok {
+			continue
+		}
+		start := time.Unix(r.StartTime, 0).In(tz)
+		end := time.Unix(r.EndTime, 0).In(tz)
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, tz)
+		shifts = append(shifts, TemplateShift{
+			Day:          int(day.Sub(origin).Hours()+12) / 24,
+			Start:        start.Format("15:04"),
+			End:          end.Format("15:04"),
+			Mealbreak:    r.Mealbreak,
+			Employee:     r.Employee,
+			EmployeeName: employeeNames[r.Employee],
+			Area:         r.OperationalUnit,
+			AreaName:     areaName,
+			Open:         r.Open,
+			Comment:      r.Comment,
+		})
+	}
+
+	sort.SliceStable(shifts, func(i, j int) bool {
+		if shifts[i].Day != shifts[j].Day {
+			return shifts[i].Day < shifts[j].Day
+		}
+		return shifts[i].Start < shifts[j].Start
+	})
+	return shifts
+}
+
+func validateTemplateShifts(shifts []TemplateShift) error {
+	if len(shifts) == 0 {
+		return errors.New("template has no shifts")
+	}
+	for i, s := range shifts {
+		if s.Day < 0 {
+			return fmt.Errorf("shift %d: day must be 0 or greater", i+1)
+		}
+		if s.Area == 0 {
+			return fmt.Errorf("shift %d: area is required", i+1)
+		}
+		if _, err := time.Parse("15:04", s.Start); err != nil {
+			return fmt.Errorf("shift %d: invalid start %q (expected HH:MM)", i+1, s.Start)
+		}
+		if _, err := time.Parse("15:04", s.End); err != nil {
+			return fmt.Errorf("shift %d: invalid end %q (expected HH:MM)", i+1, s.End)
+		}
+	}
+	return nil
+}
+
+// templateShiftTimes resolves a template shift against the target week start,
+// returning Unix start/end timestamps and the shift's calendar date.
+func templateShiftTimes(shift TemplateShift, weekOf time.Time, tz *time.Location) (int64, int64, string, error) {
+	startClock, err := time.Parse("15:04", shift.Start)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("invalid start %q (expected HH:MM)", shift.Start)
+	}
+	endClock, err := time.Parse("15:04", shift.End)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("invalid end %q (expected HH:MM)", shift.End)
+	}
+
+	day := weekOf.AddDate(0, 0, shift.Day)
+	start := time.Date(day.Year(), day.Month(), day.Day(), startClock.Hour(), startClock.Minute(), 0, 0, tz)
+	end := time.Date(day.Year(), day.Month(), day.Day(), endClock.Hour(), endClock.Minute(), 0, 0, tz)
+	if !end.After(start) {
+		end = time.Date(day.Year(), day.Month(), day.Day()+1, endClock.Hour(), endClock.Minute(), 0, 0, tz)
+	}
+	return start.Unix(), end.Unix(), day.Format("2006-01-02"), nil
+}
+
+// employeeOnLeave returns the leave in leaves that covers date for the
+// employee, or nil. Declined and cancelled leave is ignored; leave still
+// awaiting approval counts, so a template does not roster over it.
+func employeeOnLeave(leaves []api.Leave, employeeID int, date string) *api.Leave {
+	for i, l := range leaves {
+		if l.Employee != employeeID || l.Status == 2 || l.Status == 3 {
+			continue
+		}
+		if datePart(l.DateStart) <= date && date <= datePart(l.DateEnd) {
+			return &leaves[i]
+		}
+	}
+	return nil
+}
+
+// templateAreaMap maps each template area to the area with the same name
+// (ignoring case) at locationID, for applying a template to another location.
+func templateAreaMap(shifts []TemplateShift, targetAreas map[int]string, locationID int) (map[int]int, error) {
+	byName := make(map[string]int, len(targetAreas))
+	for id, name := range targetAreas {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if _, dup := byName[key]; dup {
+			byName[key] = -1
+			continue
+		}
+		byName[key] = id
+	}
+
+	areaMap := make(map[int]int)
+	for i, s := range shifts {
+		if _, ok := areaMap[s.Area]; ok {
+			continue
+		}
+		if s.AreaName == "" {
+			return nil, fmt.Errorf("shift %d: area %d has no areaName, so it cannot be matched at location %d", i+1, s.Area, locationID)
+		}
+		id, ok := byName[strings.ToLower(strings.TrimSpace(s.AreaName))]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("shift %d: location %d has no area named %q", i+1, locationID, s.AreaName)
+		case id < 0:
+			return nil, fmt.Errorf("shift %d: location %d has more than one area named %q", i+1, locationID, s.AreaName)
+		}
+		areaMap[s.Area] = id
+	}
+	return areaMap, nil
+}
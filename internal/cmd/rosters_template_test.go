@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+func TestRostersTemplateSave_WritesRelativeShifts(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("DEPUTY_CONFIG_DIR", configDir)
+
+	tz, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+	monday9 := time.Date(2026, 10, 5, 9, 0, 0, 0, tz)
+	tuesday22 := time.Date(2026, 10, 6, 22, 0, 0, 0, tz)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Company/3", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 3, CompanyName: "Sydney", Timezone: "Australia/Sydney"})
+	})
+	mux.HandleFunc("/api/v1/resource/OperationalUnit", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Department{
+			{Id: 10, Company: 3, CompanyName: "Kitchen"},
+			{Id: 20, Company: 9, CompanyName: "Elsewhere"},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/Roster/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		_ = json.NewEncoder(w).Encode([]api.Roster{
+			{Id: 2, StartTime: tuesday22.Unix(), EndTime: tuesday22.Add(5 * time.Hour).Unix(), Employee: 7, OperationalUnit: 10},
+			{Id: 1, StartTime: monday9.Unix(), EndTime: monday9.Add(8 * time.Hour).Unix(), Employee: 7, OperationalUnit: 10, Mealbreak: "00:30"},
+			{Id: 3, StartTime: monday9.Unix(), EndTime: monday9.Add(8 * time.Hour).Unix(), Employee: 8, OperationalUnit: 20},
+		})
+	})
+	mux.HandleFunc("/api/v1/supervise/employee", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Employee{{Id: 7, DisplayName: "Jane Doe"}})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newRostersTemplateSaveCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"core", "--from-date", "2026-10-05", "--to-date", "2026-10-11", "--location", "3"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), `Saved template "core" with 2 shift(s)`)
+
+	tpl, err := readRosterTemplate(filepath.Join(configDir, "templates", "rosters", "core.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "Australia/Sydney", tpl.Timezone)
+	require.Len(t, tpl.Shifts, 2)
+	assert.Equal(t, TemplateShift{Day: 0, Start: "09:00", End: "17:00", Mealbreak: "00:30", Employee: 7, EmployeeName: "Jane Doe", Area: 10, AreaName: "Kitchen"}, tpl.Shifts[0])
+	assert.Equal(t, 1, tpl.Shifts[1].Day)
+	assert.Equal(t, "22:00", tpl.Shifts[1].Start)
+	assert.Equal(t, "03:00", tpl.Shifts[1].End)
+
+	t.Run("refuses to overwrite without --force", func(t *testing.T) {
+		cmd := newRostersTemplateSaveCmd()
+		cmd.SetContext(ctx)
+		cmd.SetArgs([]string{"core", "--from-date", "2026-10-05", "--to-date", "2026-10-11", "--location", "3"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+}
+
+func TestRostersTemplateApply_SkipsEmployeesOnLeave(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("DEPUTY_CONFIG_DIR", configDir)
+
+	require.NoError(t, writeRosterTemplate(rosterTemplatePath("core"), &RosterTemplate{
+		Name:     "core",
+		Location: 3,
+		Shifts: []TemplateShift{
+			{Day: 0, Start: "09:00", End: "17:00", Employee: 7, Area: 10},
+			{Day: 1, Start: "22:00", End: "03:00", Employee: 8, Area: 10},
+		},
+	}))
+
+	tz, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+
+	var created []api.CreateRosterInput
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Company/3", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 3, Timezone: "Australia/Sydney"})
+	})
+	mux.HandleFunc("/api/v1/resource/Leave/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Leave{
+			{Id: 1, Employee: 7, DateStart: "2026-11-01T00:00:00+11:00", DateEnd: "2026-11-02T00:00:00+11:00", Status: 1},
+		})
+	})
+	mux.HandleFunc("/api/v1/supervise/roster", func(w http.ResponseWriter, r *http.Request) {
+		var input api.CreateRosterInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		created = append(created, input)
+		_ = json.NewEncoder(w).Encode(api.Roster{Id: 100 + len(created)})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = outfmt.WithFormat(ctx, "json")
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newRostersTemplateApplyCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"core", "--week-of", "2026-11-02"})
+	require.NoError(t, cmd.Execute())
+
+	require.Len(t, created, 1)
+	assert.Equal(t, 8, created[0].Employee)
+	assert.Equal(t, time.Date(2026, 11, 3, 22, 0, 0, 0, tz).Unix(), created[0].StartTime)
+	assert.Equal(t, time.Date(2026, 11, 4, 3, 0, 0, 0, tz).Unix(), created[0].EndTime)
+
+	var out struct {
+		Items []TemplateApplyResult `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	require.Len(t, out.Items, 2)
+	assert.Equal(t, "skipped", out.Items[0].Status)
+	assert.Equal(t, "created", out.Items[1].Status)
+	assert.Equal(t, 101, out.Items[1].Roster)
+}
+
+func TestRostersTemplateApply_OtherLocation(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+
+	require.NoError(t, writeRosterTemplate(rosterTemplatePath("core"), &RosterTemplate{
+		Name:     "core",
+		Location: 3,
+		Shifts: []TemplateShift{
+			{Day: 0, Start: "09:00", End: "17:00", Employee: 7, Area: 10, AreaName: "Kitchen"},
+			{Day: 0, Start: "10:00", End: "18:00", Employee: 8, Area: 10, AreaName: "Kitchen"},
+			{Day: 1, Start: "09:00", End: "17:00", Employee: 9, Area: 11, AreaName: "Bar"},
+		},
+	}))
+
+	var created []api.CreateRosterInput
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Company/4", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 4, Timezone: "Australia/Sydney"})
+	})
+	mux.HandleFunc("/api/v1/resource/OperationalUnit", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Department{
+			{Id: 40, Company: 4, CompanyName: "kitchen"},
+			{Id: 41, Company: 4, CompanyName: "Bar"},
+			{Id: 11, Company: 3, CompanyName: "Bar"},
+		})
+	})
+	mux.HandleFunc("/api/v1/resource/Leave/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]api.Leave{
+			{Id: 1, Employee: 9, DateStart: "2026-11-03", DateEnd: "2026-11-03", Status: 0},
+			{Id: 2, Employee: 7, DateStart: "2026-11-02", DateEnd: "2026-11-02", Status: 2},
+		})
+	})
+	mux.HandleFunc("/api/v1/supervise/roster", func(w http.ResponseWriter, r *http.Request) {
+		var input api.CreateRosterInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		if input.Employee == 8 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"message":"Employee not trained"}}`))
+			return
+		}
+		created = append(created, input)
+		_ = json.NewEncoder(w).Encode(api.Roster{Id: 100 + len(created)})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = outfmt.WithFormat(ctx, "json")
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newRostersTemplateApplyCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{"core", "--week-of", "2026-11-02", "--location", "4"})
+	require.EqualError(t, cmd.Execute(), "1 of 3 shift(s) failed to create")
+
+	require.Len(t, created, 1, "declined leave does not block; pending leave does")
+	assert.Equal(t, 7, created[0].Employee)
+	assert.Equal(t, 40, created[0].OperationalUnit)
+
+	var out struct {
+		Items []TemplateApplyResult `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	require.Len(t, out.Items, 3)
+	assert.Equal(t, "created", out.Items[0].Status)
+	assert.Equal(t, "failed", out.Items[1].Status)
+	assert.Contains(t, out.Items[1].Reason, "Employee not trained")
+	assert.Equal(t, "skipped", out.Items[2].Status)
+	assert.Equal(t, "employee has leave awaiting approval", out.Items[2].Reason)
+	assert.Equal(t, 41, out.Items[2].Area)
+
+	t.Run("rejects areas with no match", func(t *testing.T) {
+		require.NoError(t, writeRosterTemplate(rosterTemplatePath("deli"), &RosterTemplate{
+			Name: "deli", Location: 3,
+			Shifts: []TemplateShift{{Day: 0, Start: "09:00", End: "17:00", Area: 12, AreaName: "Deli"}},
+		}))
+		cmd := newRostersTemplateApplyCmd()
+		cmd.SetContext(ctx)
+		cmd.SetArgs([]string{"deli", "--week-of", "2026-11-02", "--location", "4"})
+		require.EqualError(t, cmd.Execute(), `shift 1: location 4 has no area named "Deli"`)
+	})
+}
+
+func TestRostersTemplateApply_Validation(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+
+	t.Run("requires --week-of", func(t *testing.T) {
+		cmd := newRostersTemplateApplyCmd()
+		cmd.SetContext(context.Background())
+		cmd.SetArgs([]string{"core"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--week-of is required")
+	})
+
+	t.Run("missing template", func(t *testing.T) {
+		cmd := newRostersTemplateApplyCmd()
+		cmd.SetContext(context.Background())
+		cmd.SetArgs([]string{"nope", "--week-of", "2026-11-02"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `template "nope" not found`)
+	})
+
+	t.Run("invalid shift time", func(t *testing.T) {
+		require.NoError(t, writeRosterTemplate(rosterTemplatePath("bad"), &RosterTemplate{
+			Name: "bad", Location: 3,
+			Shifts: []TemplateShift{{Day: 0, Start: "9am", End: "17:00", Area: 10}},
+		}))
+		cmd := newRostersTemplateApplyCmd()
+		cmd.SetContext(context.Background())
+		cmd.SetArgs([]string{"bad", "--week-of", "2026-11-02"})
+		err := cmd.Execute()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid start "9am"`)
+	})
+}
+
+func TestRostersTemplateList(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	require.NoError(t, writeRosterTemplate(rosterTemplatePath("core"), &RosterTemplate{
+		Name: "core", Location: 3, Source: &TemplateSource{From: "2026-10-05", To: "2026-10-11"},
+		Shifts: []TemplateShift{{Day: 0, Start: "09:00", End: "17:00", Area: 10}},
+	}))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(rosterTemplatePath("core")), "README.txt"), []byte("ignored"), 0o600))
+
+	buf := &bytes.Buffer{}
+	ctx := iocontext.WithIO(context.Background(), &iocontext.IO{Out: buf, ErrOut: buf})
+	cmd := newRostersTemplateListCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, buf.String(), "core")
+	assert.Contains(t, buf.String(), "2026-10-05 to 2026-10-11")
+}
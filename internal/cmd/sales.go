@@ -22,6 +22,7 @@ func newSalesCmd() *cobra.Command {
 
 	cmd.AddCommand(newSalesListCmd())
 	cmd.AddCommand(newSalesAddCmd())
+	cmd.AddCommand(newSalesImportCmd())
 
 	return cmd
 }
@@ -0,0 +1,416 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// Sales import bucket statuses.
+const (
+	salesImportCreated = "created"
+	salesImportExists  = "exists"
+	salesImportFailed  = "failed"
+)
+
+// defaultSalesTimestampFormats are tried in order when --timestamp-format is
+// not given. Layouts without a zone are read in the location's timezone.
+var defaultSalesTimestampFormats = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+// SalesImportColumns names the CSV columns read by `deputy sales import`.
+type SalesImportColumns struct {
+	Timestamp string
+	Value     string
+	Area      string
+}
+
+// SalesBucket is the summed value for one area and interval.
+type SalesBucket struct {
+	Area      int     `json:"area,omitempty"`
+	AreaName  string  `json:"areaName,omitempty"`
+	Timestamp int64   `json:"timestamp"`
+	Time      string  `json:"time"`
+	Value     float64 `json:"value"`
+	Rows      int     `json:"rows"`
+	Status    string  `json:"status,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func newSalesImportCmd() *cobra.Command {
+	var locationID, intervalMinutes, concurrency int
+	var areaMapFile, salesType string
+	var columns SalesImportColumns
+	var timestampFormats []string
+
+	cmd := &cobra.Command{
+		Use:   "import <file.csv>",
+		Short: "Import sales from a POS CSV export",
+		Long: `Import sales from a POS CSV export.
+
+Rows are summed into --interval minute buckets (aligned to midnight in the
+location's timezone) per area, then uploaded through the metrics API.
+Buckets that already exist in Deputy for the same area, time and type are
+skipped, so re-running an import does not double-count.
+
+The area map is a YAML mapping of POS area names to Deputy area IDs:
+
+  Kitchen: 12
+  Bar: 13
+
+Timestamp formats are Go layouts (e.g. "02/01/2006 15:04"), or "unix" /
+"unixms" for epoch values. Use "-" as the file to read from stdin.`,
+		Example: `  deputy sales import pos.csv --location 3
+  deputy sales import pos.csv --location 3 --area-map areas.yaml --area-column Station
+  deputy sales import pos.csv --location 3 --timestamp-column "Closed At" --value-column Total \
+    --timestamp-format "02/01/2006 15:04" --type Sales --interval 30`,
+		Args: RequireArg("file"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if locationID == 0 {
+				return errors.New("--location is required")
+			}
+			if intervalMinutes <= 0 || 24*60%intervalMinutes != 0 {
+				return fmt.Errorf("invalid --interval %d (must divide a day evenly)", intervalMinutes)
+			}
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+
+			var areaMap map[string]int
+			if areaMapFile != "" {
+				if columns.Area == "" {
+					return errors.New("--area-map requires --area-column")
+				}
+				var err error
+				if areaMap, err = readSalesAreaMap(areaMapFile); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
+			streams := iocontext.FromContext(ctx)
+			var in io.Reader = streams.In
+			if args[0] != "-" {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer func() { _ = file.Close() }()
+				in = file
+			}
+
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			tz, err := locationTimezone(ctx, client, locationID)
+			if err != nil {
+				return err
+			}
+
+			buckets, err := readSalesCSV(in, columns, timestampFormats, areaMap, tz, time.Duration(intervalMinutes)*time.Minute)
+			if err != nil {
+				return err
+			}
+
+			if len(buckets) > 0 {
+				existing, err := client.Sales().Query(ctx, &api.SalesQueryInput{
+					Company:   locationID,
+					StartTime: buckets[0].Timestamp,
+					EndTime:   buckets[len(buckets)-1].Timestamp,
+				})
+				if err != nil {
+					return err
+				}
+				markExistingSales(buckets, existing, salesType)
+			}
+
+			forEachConcurrent(len(buckets), concurrency, func(i int) {
+				b := &buckets[i]
+				if b.Status == salesImportExists {
+					return
+				}
+				_, err := client.Sales().Add(ctx, &api.CreateSalesInput{
+					Company:   locationID,
+					Area:      b.Area,
+					Timestamp: b.Timestamp,
+					Value:     b.Value,
+					Type:      salesType,
+				})
+				if err != nil {
+					b.Status = salesImportFailed
+					b.Error = err.Error()
+					return
+				}
+				b.Status = salesImportCreated
+			})
+
+			counts := map[string]int{}
+			rows := 0
+			for _, b := range buckets {
+				counts[b.Status]++
+				rows += b.Rows
+			}
+
+			format := outfmt.GetFormat(ctx)
+			if format == "json" {
+				f := outfmt.New(ctx)
+				if err := f.OutputWithMeta(buckets, map[string]any{
+					"count":    len(buckets),
+					"rows":     rows,
+					"created":  counts[salesImportCreated],
+					"existing": counts[salesImportExists],
+					"failed":   counts[salesImportFailed],
+				}); err != nil {
+					return err
+				}
+			} else {
+				for _, b := range buckets {
+					if b.Status == salesImportFailed {
+						_, _ = fmt.Fprintf(streams.ErrOut, "failed %s area %d: %s\n", b.Time, b.Area, b.Error)
+					}
+				}
+				_, _ = fmt.Fprintf(streams.Out, "Imported %d rows into %d buckets: %d created, %d already present, %d failed\n",
+					rows, len(buckets), counts[salesImportCreated], counts[salesImportExists], counts[salesImportFailed])
+			}
+
+			if counts[salesImportFailed] > 0 {
+				return fmt.Errorf("%d of %d sales buckets failed to upload", counts[salesImportFailed], len(buckets))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&locationID, "location", 0, "Location ID (required)")
+	cmd.Flags().StringVar(&areaMapFile, "area-map", "", "YAML file mapping POS area names to Deputy area IDs")
+	cmd.Flags().StringVar(&salesType, "type", "Sales", "Sales metric type")
+	cmd.Flags().IntVar(&intervalMinutes, "interval", 15, "Bucket size in minutes")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Maximum concurrent uploads")
+	cmd.Flags().StringVar(&columns.Timestamp, "timestamp-column", "timestamp", "CSV column holding the sale time")
+	cmd.Flags().StringVar(&columns.Value, "value-column", "value", "CSV column holding the sale amount")
+	cmd.Flags().StringVar(&columns.Area, "area-column", "", "CSV column holding the POS area name")
+	cmd.Flags().StringArrayVar(&timestampFormats, "timestamp-format", nil, "Timestamp layout to try; repeatable (default: RFC3339 and common ISO variants)")
+
+	return cmd
+}
+
+// readSalesAreaMap loads a YAML mapping of POS area names to Deputy area IDs.
+func readSalesAreaMap(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var areaMap map[string]int
+	if err := yaml.Unmarshal(data, &areaMap); err != nil {
+		return nil, fmt.Errorf("invalid area map %s: %w", path, err)
+	}
+	return areaMap, nil
+}
+
+// readSalesCSV parses a POS export and sums values into interval buckets,
+// sorted by time then area.
+func readSalesCSV(r io.Reader, columns SalesImportColumns, formats []string, areaMap map[string]int, tz *time.Location, interval time.Duration) ([]SalesBucket, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	column := func(name string) (int, error) {
+		i, ok := index[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("CSV has no %q column (found: %s)", name, strings.Join(header, ", "))
+		}
+		return i, nil
+	}
+	tsCol, err := column(columns.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	valueCol, err := column(columns.Value)
+	if err != nil {
+		return nil, err
+	}
+	areaCol := -1
+	if columns.Area != "" {
+		if areaCol, err = column(columns.Area); err != nil {
+			return nil, err
+		}
+	}
+	if len(formats) == 0 {
+		formats = defaultSalesTimestampFormats
+	}
+
+	type bucketKey struct {
+		area int
+		ts   int64
+	}
+	buckets := make(map[bucketKey]*SalesBucket)
+	unmapped := make(map[string]bool)
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		at, err := parseSalesTimestamp(record[tsCol], formats, tz)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		value, err := parseSalesValue(record[valueCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		area, areaName := 0, ""
+		if areaCol >= 0 {
+			areaName = strings.TrimSpace(record[areaCol])
+			if areaMap != nil {
+				id, ok := areaMap[areaName]
+				if !ok {
+					unmapped[areaName] = true
+					continue
+				}
+				area = id
+			} else if area, err = strconv.Atoi(areaName); err != nil {
+				return nil, fmt.Errorf("line %d: area %q is not an ID (use --area-map to map names)", line, areaName)
+			}
+		}
+
+		start := salesBucketStart(at, interval)
+		key := bucketKey{area: area, ts: start.Unix()}
+		b, ok := buckets[key]
+		if !ok {
+			b = &SalesBucket{Area: area, AreaName: areaName, Timestamp: start.Unix(), Time: start.Format(time.RFC3339)}
+			buckets[key] = b
+		}
+		b.Value += value
+		b.Rows++
+	}
+
+	if len(unmapped) > 0 {
+		names := make([]string, 0, len(unmapped))
+		for name := range unmapped {
+			names = append(names, fmt.Sprintf("%q", name))
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("areas missing from --area-map: %s", strings.Join(names, ", "))
+	}
+
+	result := make([]SalesBucket, 0, len(buckets))
+	for _, b := range buckets {
+		result = append(result, *b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Timestamp != result[j].Timestamp {
+			return result[i].Timestamp < result[j].Timestamp
+		}
+		return result[i].Area < result[j].Area
+	})
+	return result, nil
+}
+
+func parseSalesTimestamp(value string, formats []string, tz *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range formats {
+		switch layout {
+		case "unix", "unixms":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			if layout == "unixms" {
+				return time.UnixMilli(n).In(tz), nil
+			}
+			return time.Unix(n, 0).In(tz), nil
+		default:
+			if t, err := time.ParseInLocation(layout, value, tz); err == nil {
+				return t.In(tz), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse timestamp %q (formats tried: %s)", value, strings.Join(formats, ", "))
+}
+
+// parseSalesValue parses an amount, tolerating currency symbols, thousands
+// separators and accounting-style negatives such as "(12.50)".
+func parseSalesValue(value string) (float64, error) {
+	cleaned := strings.TrimSpace(value)
+	negative := strings.HasPrefix(cleaned, "(") && strings.HasSuffix(cleaned, ")")
+	cleaned = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			return r
+		}
+		return -1
+	}, cleaned)
+	if cleaned == "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	n, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		n = -n
+	}
+	return n, nil
+}
+
+// salesBucketStart floors t to the start of its interval, counted from local
+// midnight so buckets line up with Deputy's day view.
+func salesBucketStart(t time.Time, interval time.Duration) time.Time {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	return midnight.Add(offset - offset%interval)
+}
+
+// markExistingSales flags buckets that Deputy already holds for the same area,
+// timestamp and type.
+func markExistingSales(buckets []SalesBucket, existing []api.SalesData, salesType string) {
+	type key struct {
+		area int
+		ts   int64
+	}
+	seen := make(map[key]bool, len(existing))
+	for _, s := range existing {
+		if s.Type == "" || strings.EqualFold(s.Type, salesType) {
+			seen[key{area: s.Area, ts: s.Timestamp}] = true
+		}
+	}
+	for i := range buckets {
+		if seen[key{area: buckets[i].Area, ts: buckets[i].Timestamp}] {
+			buckets[i].Status = salesImportExists
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+func TestReadSalesCSV_BucketsByIntervalAndArea(t *testing.T) {
+	input := `Closed At,Total,Station
+2024-03-04 09:02,$10.00,Kitchen
+2024-03-04 09:14,"1,005.50",Kitchen
+2024-03-04 09:15,5,Kitchen
+2024-03-04 09:03,(2.50),Bar
+`
+	columns := SalesImportColumns{Timestamp: "closed at", Value: "Total", Area: "Station"}
+	areaMap := map[string]int{"Kitchen": 12, "Bar": 13}
+
+	buckets, err := readSalesCSV(strings.NewReader(input), columns, nil, areaMap, time.UTC, 15*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, buckets, 3)
+
+	nine := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC).Unix()
+	assert.Equal(t, SalesBucket{Area: 12, AreaName: "Kitchen", Timestamp: nine, Time: "2024-03-04T09:00:00Z", Value: 1015.5, Rows: 2}, buckets[0])
+	assert.Equal(t, 13, buckets[1].Area)
+	assert.InDelta(t, -2.5, buckets[1].Value, 0.001)
+	assert.Equal(t, nine+15*60, buckets[2].Timestamp)
+}
+
+func TestReadSalesCSV_UTCTimestampsBucketInLocation(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	input := "timestamp,value,area\n2024-03-04T04:10:00Z,8,Kitchen\n"
+	columns := SalesImportColumns{Timestamp: "timestamp", Value: "value", Area: "area"}
+
+	buckets, err := readSalesCSV(strings.NewReader(input), columns, nil, map[string]int{"Kitchen": 12}, kolkata, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	// 04:10Z is 09:40 in Kolkata, so the bucket starts at 09:00 local.
+	assert.Equal(t, time.Date(2024, 3, 4, 9, 0, 0, 0, kolkata).Unix(), buckets[0].Timestamp)
+	assert.Equal(t, "2024-03-04T09:00:00+05:30", buckets[0].Time)
+}
+
+func TestReadSalesCSV_Errors(t *testing.T) {
+	columns := SalesImportColumns{Timestamp: "timestamp", Value: "value", Area: "area"}
+
+	_, err := readSalesCSV(strings.NewReader("when,value\n"), columns, nil, nil, time.UTC, 15*time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no "timestamp" column`)
+
+	_, err = readSalesCSV(strings.NewReader("timestamp,value,area\nyesterday,1,Kitchen\n"), columns, nil, map[string]int{"Kitchen": 1}, time.UTC, 15*time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 2")
+
+	_, err = readSalesCSV(strings.NewReader("timestamp,value,area\n1709542800,1,Patio\n"), columns, []string{"unix"}, map[string]int{"Kitchen": 1}, time.UTC, 15*time.Minute)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"Patio"`)
+}
+
+func TestSalesImportCommand(t *testing.T) {
+	nine := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC).Unix()
+
+	var mu sync.Mutex
+	var posted []api.CreateSalesInput
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Company/3", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.Location{Id: 3, Timezone: "UTC"})
+	})
+	mux.HandleFunc("/api/v1/resource/SalesData", func(w http.ResponseWriter, r *http.Request) {
+		// The 09:00 bucket for area 12 was imported by a previous run.
+		_ = json.NewEncoder(w).Encode([]api.SalesData{{Id: 1, Company: 3, Area: 12, Timestamp: nine, Value: 10, Type: "Sales"}})
+	})
+	mux.HandleFunc("/api/v2/metrics", func(w http.ResponseWriter, r *http.Request) {
+		var input api.CreateSalesInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		mu.Lock()
+		posted = append(posted, input)
+		mu.Unlock()
+		_ = json.NewEncoder(w).Encode(api.SalesData{Id: 2, Company: input.Company})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "pos.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("timestamp,value,station\n2024-03-04T09:05:00Z,10,Kitchen\n2024-03-04T09:20:00Z,7.5,Kitchen\n2024-03-04T09:21:00Z,4,Bar\n"), 0o600))
+	mapPath := filepath.Join(dir, "areas.yaml")
+	require.NoError(t, os.WriteFile(mapPath, []byte("Kitchen: 12\nBar: 13\n"), 0o600))
+
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = outfmt.WithFormat(ctx, "json")
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	cmd := newSalesImportCmd()
+	cmd.SetContext(ctx)
+	cmd.SetArgs([]string{csvPath, "--location", "3", "--area-map", mapPath, "--area-column", "station", "--concurrency", "2"})
+	require.NoError(t, cmd.Execute())
+
+	require.Len(t, posted, 2)
+	for _, p := range posted {
+		assert.Equal(t, 3, p.Company)
+		assert.Equal(t, "Sales", p.Type)
+		assert.Equal(t, nine+15*60, p.Timestamp)
+	}
+
+	var out struct {
+		Items []SalesBucket  `json:"items"`
+		Meta  map[string]any `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, float64(2), out.Meta["created"])
+	assert.Equal(t, float64(1), out.Meta["existing"])
+	assert.Equal(t, salesImportExists, out.Items[0].Status)
+}
+
+func TestSalesImportCommand_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing location", []string{"pos.csv"}, "--location is required"},
+		{"bad interval", []string{"pos.csv", "--location", "1", "--interval", "7"}, "invalid --interval"},
+		{"area map without column", []string{"pos.csv", "--location", "1", "--area-map", "m.yaml"}, "--area-map requires --area-column"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newSalesImportCmd()
+			cmd.SetContext(context.Background())
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -85,6 +85,7 @@ func TestSalesCommand_HasExpectedSubcommands(t *testing.T) {
 	}
 	assert.Contains(t, names, "list")
 	assert.Contains(t, names, "add")
+	assert.Contains(t, names, "import")
 }
 
 // TestSalesCommand_Aliases verifies the command aliases work
@@ -54,6 +54,7 @@ func persistentFlagArgs(root *cobra.Command) []string {
 	raw, _ := root.PersistentFlags().GetBool("raw")
 	debug, _ := root.PersistentFlags().GetBool("debug")
 	noColor, _ := root.PersistentFlags().GetBool("no-color")
+	dryRun, _ := root.PersistentFlags().GetBool("dry-run")
 
 	args := []string{"--output", out}
 	if query != "" {
@@ -68,6 +69,9 @@ func persistentFlagArgs(root *cobra.Command) []string {
 	if noColor {
 		args = append(args, "--no-color")
 	}
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
 
 	return args
 }
@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// defaultSnapshotResources covers the org configuration most often compared
+// between installs.
+var defaultSnapshotResources = []string{"Company", "OperationalUnit", "Employee", "PayRules", "LeaveRules"}
+
+// snapshotManifest is written next to the resource files so a diff can say
+// where each side came from.
+type snapshotManifest struct {
+	Host       string         `json:"host"`
+	ExportedAt time.Time      `json:"exportedAt"`
+	Resources  map[string]int `json:"resources"`
+}
+
+const snapshotManifestFile = "manifest.json"
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Export resources to JSON and diff snapshots",
+		Long: `Export resources as normalized JSON (one file per resource, records sorted by
+Id, keys sorted) and compare two snapshots field by field. Useful for
+comparing a sandbox with production or seeing what changed over time.`,
+	}
+
+	cmd.AddCommand(newSnapshotExportCmd())
+	cmd.AddCommand(newSnapshotDiffCmd())
+
+	return cmd
+}
+
+type snapshotExportResult struct {
+	Resource string `json:"resource"`
+	Records  int    `json:"records"`
+	File     string `json:"file"`
+}
+
+func newSnapshotExportCmd() *cobra.Command {
+	var resources []string
+	var outDir string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write every record of the given resources to a directory",
+		Example: `  deputy snapshot export --out snap/
+  deputy snapshot export --resources Company,OperationalUnit,Employee,PayRules,LeaveRules --out prod-2024-03/`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if outDir == "" {
+				return errors.New("--out is required")
+			}
+			if len(resources) == 0 {
+				return errors.New("--resources must name at least one resource")
+			}
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(outDir, 0o700); err != nil {
+				return err
+			}
+
+			manifest := snapshotManifest{ExportedAt: time.Now().UTC(), Resources: map[string]int{}}
+			if u, err := url.Parse(client.BaseURL()); err == nil {
+				manifest.Host = u.Host
+			}
+
+			results := make([]snapshotExportResult, 0, len(resources))
+			for _, name := range resources {
+				records, err := client.Resource(name).QueryAll(ctx, &api.QueryInput{
+					Sort: map[string]string{"Id": "asc"},
+				})
+				if err != nil {
+					return fmt.Errorf("export %s: %w", name, err)
+				}
+				records = normalizeSnapshotRecords(records)
+				path := filepath.Join(outDir, name+".json")
+				if err := writeSnapshotJSON(path, records); err != nil {
+					return err
+				}
+				manifest.Resources[name] = len(records)
+				results = append(results, snapshotExportResult{Resource: name, Records: len(records), File: path})
+			}
+			if err := writeSnapshotJSON(filepath.Join(outDir, snapshotManifestFile), manifest); err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputWithMeta(results, map[string]interface{}{
+					"count": len(results),
+					"out":   outDir,
+					"host":  manifest.Host,
+				})
+			}
+			f := outfmt.New(ctx)
+			f.StartTable([]string{"RESOURCE", "RECORDS", "FILE"})
+			for _, r := range results {
+				f.Row(r.Resource, fmt.Sprintf("%d", r.Records), r.File)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&resources, "resources", defaultSnapshotResources, "Resources to export (comma-separated)")
+	cmd.Flags().StringVar(&outDir, "out", "", "Directory to write the snapshot to (required)")
+
+	return cmd
+}
+
+// normalizeSnapshotRecords drops Deputy's per-response metadata and sorts by
+// Id so two exports of the same data are byte-identical. Map keys are sorted
+// by encoding/json.
+func normalizeSnapshotRecords(records []map[string]interface{}) []map[string]interface{} {
+	if records == nil {
+		records = []map[string]interface{}{}
+	}
+	for _, r := range records {
+		delete(r, "_DPMetaData")
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return snapshotKeyString(records[i]["Id"]) < snapshotKeyString(records[j]["Id"])
+	})
+	return records
+}
+
+func writeSnapshotJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// snapshotKeyString renders a key value so numeric IDs sort and match
+// numerically ("9" before "10") and everything else compares as text.
+func snapshotKeyString(v interface{}) string {
+	switch k := v.(type) {
+	case float64:
+		return fmt.Sprintf("%020.6f", k)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(k)
+	}
+}
+
+// snapshotChange is one added, removed or changed record.
+type snapshotChange struct {
+	Resource string           `json:"resource"`
+	Key      string           `json:"key"`
+	Name     string           `json:"name,omitempty"`
+	Change   string           `json:"change"`
+	Fields   []orgFieldChange `json:"fields,omitempty"`
+}
+
+func newSnapshotDiffCmd() *cobra.Command {
+	var keys []string
+	var ignore []string
+	var resources []string
+
+	cmd := &cobra.Command{
+		Use:   "diff <a> <b>",
+		Short: "Report added, removed and changed records between two snapshots",
+		Long: `Compare two snapshot directories written by 'deputy snapshot export'.
+
+Records are matched by Id. Installs have different IDs, so when comparing a
+sandbox with production match on a natural key instead, either for every
+resource (--key Code) or per resource (--key Employee=Email). Fields listed in
+--ignore are left out of the comparison.`,
+		Example: `  deputy snapshot diff snap-2024-02/ snap-2024-03/
+  deputy snapshot diff sandbox/ prod/ --key Company=Code --key OperationalUnit=CompanyCode --key Employee=Email
+  deputy snapshot diff a/ b/ --resources PayRules -o json`,
+		Args: RequireArgs("a", "b"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyFor, err := parseSnapshotKeys(keys)
+			if err != nil {
+				return err
+			}
+			names := resources
+			if len(names) == 0 {
+				names, err = snapshotResourceNames(args[0], args[1])
+				if err != nil {
+					return err
+				}
+			}
+			ignored := map[string]bool{}
+			for _, f := range ignore {
+				ignored[f] = true
+			}
+
+			changes := []snapshotChange{}
+			for _, name := range names {
+				a, err := readSnapshotResource(args[0], name)
+				if err != nil {
+					return err
+				}
+				b, err := readSnapshotResource(args[1], name)
+				if err != nil {
+					return err
+				}
+				changes = append(changes, diffSnapshotResource(name, keyFor(name), a, b, ignored)...)
+			}
+
+			summary := map[string]int{"added": 0, "removed": 0, "changed": 0}
+			for _, c := range changes {
+				summary[c.Change]++
+			}
+
+			ctx := cmd.Context()
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputWithMeta(changes, map[string]interface{}{
+					"count":   len(changes),
+					"summary": summary,
+					"a":       args[0],
+					"b":       args[1],
+				})
+			}
+
+			out := iocontext.FromContext(ctx).Out
+			if len(changes) == 0 {
+				_, _ = fmt.Fprintf(out, "No differences between %s and %s\n", args[0], args[1])
+				return nil
+			}
+			symbols := map[string]string{"added": "+", "removed": "-", "changed": "~"}
+			current := ""
+			for _, c := range changes {
+				if c.Resource != current {
+					if current != "" {
+						_, _ = fmt.Fprintln(out)
+					}
+					current = c.Resource
+					_, _ = fmt.Fprintf(out, "%s:\n", c.Resource)
+				}
+				line := fmt.Sprintf("  %s %s", symbols[c.Change], c.Key)
+				if c.Name != "" {
+					line += " " + c.Name
+				}
+				_, _ = fmt.Fprintln(out, line)
+				for _, fc := range c.Fields {
+					_, _ = fmt.Fprintf(out, "      %s: %s -> %s\n", fc.Field, fc.Old, fc.New)
+				}
+			}
+			_, _ = fmt.Fprintf(out, "\n%d added, %d removed, %d changed\n", summary["added"], summary["removed"], summary["changed"])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&keys, "key", nil, "Match records on FIELD, or RESOURCE=FIELD for one resource (can be repeated)")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", []string{"Created", "Modified"}, "Fields to leave out of the comparison")
+	cmd.Flags().StringSliceVar(&resources, "resources", nil, "Only compare these resources (default: all files in either snapshot)")
+
+	return cmd
+}
+
+// parseSnapshotKeys turns --key values into a lookup from resource name to
+// match field.
+func parseSnapshotKeys(keys []string) (func(string) string, error) {
+	fallback := "Id"
+	perResource := map[string]string{}
+	for _, k := range keys {
+		resource, field, ok := strings.Cut(k, "=")
+		if !ok {
+			if k == "" {
+				return nil, errors.New("--key must not be empty")
+			}
+			fallback = k
+			continue
+		}
+		if resource == "" || field == "" {
+			return nil, fmt.Errorf("invalid --key %q (expected FIELD or RESOURCE=FIELD)", k)
+		}
+		perResource[strings.ToLower(resource)] = field
+	}
+	return func(resource string) string {
+		if field, ok := perResource[strings.ToLower(resource)]; ok {
+			return field
+		}
+		return fallback
+	}, nil
+}
+
+// snapshotResourceNames lists the resource files present in either directory.
+func snapshotResourceNames(dirs ...string) ([]string, error) {
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || filepath.Ext(name) != ".json" || name == snapshotManifestFile {
+				continue
+			}
+			seen[strings.TrimSuffix(name, ".json")] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readSnapshotResource loads one resource file. A resource missing from a
+// snapshot reads as empty, so everything in the other side shows as added or
+// removed.
+func readSnapshotResource(dir, name string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filepath.Join(dir, name+".json"), err)
+	}
+	return records, nil
+}
+
+func diffSnapshotResource(resource, keyField string, a, b []map[string]interface{}, ignored map[string]bool) []snapshotChange {
+	index := func(records []map[string]interface{}) (map[string]map[string]interface{}, []string) {
+		byKey := make(map[string]map[string]interface{}, len(records))
+		var order []string
+		for _, r := range records {
+			k := snapshotKeyString(r[keyField])
+			if _, dup := byKey[k]; !dup {
+				order = append(order, k)
+			}
+			byKey[k] = r
+		}
+		return byKey, order
+	}
+	aByKey, aOrder := index(a)
+	bByKey, bOrder := index(b)
+
+	keys := append([]string{}, aOrder...)
+	for _, k := range bOrder {
+		if _, ok := aByKey[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []snapshotChange
+	for _, k := range keys {
+		before, inA := aByKey[k]
+		after, inB := bByKey[k]
+		switch {
+		case !inA:
+			changes = append(changes, snapshotChange{Resource: resource, Key: snapshotKeyLabel(after[keyField]), Name: snapshotRecordName(after), Change: "added"})
+		case !inB:
+			changes = append(changes, snapshotChange{Resource: resource, Key: snapshotKeyLabel(before[keyField]), Name: snapshotRecordName(before), Change: "removed"})
+		default:
+			fields := diffSnapshotFields(before, after, ignored)
+			if len(fields) > 0 {
+				changes = append(changes, snapshotChange{Resource: resource, Key: snapshotKeyLabel(after[keyField]), Name: snapshotRecordName(after), Change: "changed", Fields: fields})
+			}
+		}
+	}
+	return changes
+}
+
+func diffSnapshotFields(a, b map[string]interface{}, ignored map[string]bool) []orgFieldChange {
+	names := map[string]interface{}{}
+	for k := range a {
+		names[k] = nil
+	}
+	for k := range b {
+		names[k] = nil
+	}
+	var fields []orgFieldChange
+	for _, name := range sortedKeys(names) {
+		if ignored[name] {
+			continue
+		}
+		old, inA := a[name]
+		cur, inB := b[name]
+		if inA == inB && reflect.DeepEqual(old, cur) {
+			continue
+		}
+		change := orgFieldChange{Field: name, Old: "(absent)", New: "(absent)"}
+		if inA {
+			change.Old = orgValue(old)
+		}
+		if inB {
+			change.New = orgValue(cur)
+		}
+		fields = append(fields, change)
+	}
+	return fields
+}
+
+func snapshotKeyLabel(v interface{}) string {
+	if f, ok := v.(float64); ok && f == float64(int64(f)) {
+		return fmt.Sprintf("%d", int64(f))
+	}
+	if v == nil {
+		return "(none)"
+	}
+	return fmt.Sprint(v)
+}
+
+// snapshotRecordName picks a human-readable label for a record.
+func snapshotRecordName(r map[string]interface{}) string {
+	for _, field := range []string{"DisplayName", "CompanyName", "Name", "Title"} {
+		if s, ok := r[field].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
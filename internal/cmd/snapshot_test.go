@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+)
+
+func TestSnapshotExport_WritesNormalizedFiles(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		var input api.QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, api.QueryPageSize, input.Max)
+		switch r.URL.Path {
+		case "/api/v1/resource/Company/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":10,"CompanyName":"B","_DPMetaData":{"x":1}},{"Id":9,"CompanyName":"A"}]`))
+		case "/api/v1/resource/PayRules/QUERY":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "snap")
+	buf := &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{Out: buf, ErrOut: buf})
+
+	root := NewRootCmd()
+	root.SetArgs([]string{"snapshot", "export", "--resources", "Company,PayRules", "--out", dir})
+	require.NoError(t, root.ExecuteContext(ctx))
+	assert.Equal(t, []string{"/api/v1/resource/Company/QUERY", "/api/v1/resource/PayRules/QUERY"}, paths)
+	assert.Contains(t, buf.String(), "Company")
+
+	data, err := os.ReadFile(filepath.Join(dir, "Company.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "CompanyName": "A",
+    "Id": 9
+  },
+  {
+    "CompanyName": "B",
+    "Id": 10
+  }
+]
+`, string(data))
+
+	data, err = os.ReadFile(filepath.Join(dir, "PayRules.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "[]\n", string(data))
+
+	var manifest snapshotManifest
+	data, err = os.ReadFile(filepath.Join(dir, snapshotManifestFile))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &manifest))
+	assert.Equal(t, map[string]int{"Company": 2, "PayRules": 0}, manifest.Resources)
+	assert.NotEmpty(t, manifest.Host)
+}
+
+func writeSnapshotFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func runSnapshotDiff(t *testing.T, format string, args ...string) string {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	ctx := iocontext.WithIO(context.Background(), &iocontext.IO{Out: buf, ErrOut: buf})
+	root := NewRootCmd()
+	root.SetArgs(append([]string{"snapshot", "diff", "-o", format}, args...))
+	require.NoError(t, root.ExecuteContext(ctx))
+	return buf.String()
+}
+
+func TestSnapshotDiff(t *testing.T) {
+	base := t.TempDir()
+	a := filepath.Join(base, "a")
+	b := filepath.Join(base, "b")
+	writeSnapshotFile(t, a, "Employee.json", `[
+		{"Id": 1, "DisplayName": "Ann", "Active": true, "Modified": "2024-01-01"},
+		{"Id": 2, "DisplayName": "Bob", "Active": true},
+		{"Id": 9, "DisplayName": "Cat", "Active": true}
+	]`)
+	writeSnapshotFile(t, b, "Employee.json", `[
+		{"Id": 1, "DisplayName": "Ann", "Active": true, "Modified": "2024-02-01"},
+		{"Id": 9, "DisplayName": "Cat", "Active": false, "Role": 3},
+		{"Id": 10, "DisplayName": "Dan", "Active": true}
+	]`)
+	writeSnapshotFile(t, b, "PayRules.json", `[{"Id": 4, "PayTitle": "Base"}]`)
+	writeSnapshotFile(t, b, snapshotManifestFile, `{"host":"x"}`)
+
+	out := runSnapshotDiff(t, "text", a, b)
+	assert.Contains(t, out, "Employee:\n  - 2 Bob\n  ~ 9 Cat\n      Active: true -> false\n      Role: (absent) -> 3\n  + 10 Dan\n")
+	assert.Contains(t, out, "PayRules:\n  + 4\n")
+	assert.Contains(t, out, "2 added, 1 removed, 1 changed")
+	assert.NotContains(t, out, "Modified")
+	assert.NotContains(t, out, "manifest")
+
+	out = runSnapshotDiff(t, "json", a, b, "--resources", "Employee", "--ignore", "")
+	var result struct {
+		Items []snapshotChange `json:"items"`
+		Meta  struct {
+			Summary map[string]int `json:"summary"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	assert.Equal(t, map[string]int{"added": 1, "removed": 1, "changed": 2}, result.Meta.Summary)
+	assert.Equal(t, "changed", result.Items[0].Change)
+	assert.Equal(t, "1", result.Items[0].Key)
+	assert.Equal(t, "Modified", result.Items[0].Fields[0].Field)
+}
+
+func TestSnapshotDiff_MatchOnNaturalKey(t *testing.T) {
+	base := t.TempDir()
+	a := filepath.Join(base, "sandbox")
+	b := filepath.Join(base, "prod")
+	writeSnapshotFile(t, a, "Company.json", `[{"Id": 1, "Code": "MST", "CompanyName": "Main"}]`)
+	writeSnapshotFile(t, b, "Company.json", `[{"Id": 7, "Code": "MST", "CompanyName": "Main St"}]`)
+
+	out := runSnapshotDiff(t, "text", a, b, "--key", "Company=Code", "--ignore", "Id")
+	assert.Contains(t, out, "~ MST Main St\n      CompanyName: \"Main\" -> \"Main St\"\n")
+	assert.Equal(t, 1, strings.Count(out, "~"))
+	assert.Contains(t, out, "0 added, 0 removed, 1 changed")
+}
+
+func TestParseSnapshotKeys(t *testing.T) {
+	keyFor, err := parseSnapshotKeys([]string{"Code", "Employee=Email"})
+	require.NoError(t, err)
+	assert.Equal(t, "Email", keyFor("employee"))
+	assert.Equal(t, "Code", keyFor("Company"))
+
+	_, err = parseSnapshotKeys([]string{"=Email"})
+	assert.Error(t, err)
+}
@@ -1,13 +1,33 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
+	"testing"
 
 	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
 	"github.com/salmonumbrella/deputy-cli/internal/secrets"
 )
 
+// TestMain points the config directory at a temporary one so commands that
+// write local state (such as the audit log) never touch the real one.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "deputy-cmd-test")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("DEPUTY_CONFIG_DIR", dir)
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 // testServerTransport redirects API requests to a test server.
 type testServerTransport struct {
 	testServerURL string
@@ -38,3 +58,18 @@ func newTestClient(serverURL, token string) *api.Client {
 	})
 	return client
 }
+
+// runRoot runs the root command with args against server, the way Execute
+// does, feeding stdin to prompts. It returns what was written to stdout and
+// stderr.
+func runRoot(t *testing.T, server *httptest.Server, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	ctx := WithClientFactory(context.Background(), &MockClientFactory{client: newTestClient(server.URL, "test-token")})
+	ctx = iocontext.WithIO(ctx, &iocontext.IO{In: strings.NewReader(stdin), Out: out, ErrOut: errOut})
+
+	root := NewRootCmd()
+	root.SetArgs(args)
+	err := executeRoot(ctx, root)
+	return out.String(), errOut.String(), err
+}
@@ -23,6 +23,7 @@ func newTimesheetsCmd() *cobra.Command {
 Pay Rule Commands:
   list-pay-rules    List available pay rules (filter by --hourly-rate)
   select-pay-rule   Assign a pay rule to an approved timesheet
+  assign-pay-rules  Assign pay rules to a date range of timesheets from a rules file
 
 Example workflow for setting pay rates:
   # Find pay rules with $190/hr rate
@@ -35,8 +36,11 @@ Example workflow for setting pay rates:
 	cmd.AddCommand(newTimesheetsListCmd())
 	cmd.AddCommand(newTimesheetsGetCmd())
 	cmd.AddCommand(newTimesheetsUpdateCmd())
+	cmd.AddCommand(newTimesheetsAddCmd())
 	cmd.AddCommand(newTimesheetsListPayRulesCmd())
 	cmd.AddCommand(newTimesheetsSetPayRuleCmd())
+	cmd.AddCommand(newTimesheetsAssignPayRulesCmd())
+	cmd.AddCommand(newTimesheetsAuditCmd())
 	cmd.AddCommand(newTimesheetsClockInCmd())
 	cmd.AddCommand(newTimesheetsClockOutCmd())
 	cmd.AddCommand(newTimesheetsStartBreakCmd())
@@ -181,34 +185,63 @@ func newTimesheetsGetCmd() *cobra.Command {
 
 func newTimesheetsUpdateCmd() *cobra.Command {
 	var cost float64
+	var edit timesheetEditFlags
+	var yes bool
 
 	cmd := &cobra.Command{
 		Use:   "update <id>",
 		Short: "Update a timesheet",
-		Long: `Update a timesheet's properties.
-
-Currently supports updating the cost/pay amount for a timesheet.`,
+		Long: `Update a timesheet's times, meal break, area, comment or cost.
+
+Times are read in the timezone of the location the timesheet's operational
+unit belongs to. A bare HH:MM falls on the timesheet's date, and an --end
+before the start runs past midnight. Changes other than --cost alone are
+shown before asking for confirmation.`,
+		Example: `  deputy timesheets update 19379 --cost 250
+  deputy timesheets update 19379 --start 08:30 --end 17:00 --mealbreak 30m
+  deputy timesheets update 19379 --opunit 7 --comment "Covered the bar"`,
 		Args: RequireArg("id"),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			id, err := strconv.Atoi(args[0])
 			if err != nil {
 				return fmt.Errorf("invalid timesheet ID: %s", args[0])
 			}
+			editing := edit.changed(cmd)
+			if !editing && !cmd.Flags().Changed("cost") {
+				return errors.New("nothing to update: set --start, --end, --mealbreak, --opunit, --comment or --cost")
+			}
 
 			client, err := getClientFromContext(cmd.Context())
 			if err != nil {
 				return err
 			}
+			if editing {
+				var costPtr *float64
+				if cmd.Flags().Changed("cost") {
+					costPtr = &cost
+				}
+				return editTimesheet(cmd, client, id, &edit, costPtr, yes)
+			}
 
 			input := &api.UpdateTimesheetInput{}
+			var before *api.Timesheet
+			var snapped bool
 			if cmd.Flags().Changed("cost") {
 				input.Cost = &cost
+				before, snapped = snapshotForUndo(cmd.Context(), fmt.Sprintf("timesheet %d", id), func() (*api.Timesheet, error) {
+					return client.Timesheets().Get(cmd.Context(), id)
+				})
 			}
 
 			timesheet, err := client.Timesheets().Update(cmd.Context(), id, input)
 			if err != nil {
 				return err
 			}
+			if snapped {
+				recordUndo(cmd.Context(), client, undoTimesheetCost, id,
+					fmt.Sprintf("set timesheet %d cost %.2f -> %.2f", id, before.Cost, cost),
+					timesheetCostSnapshot{Cost: before.Cost})
+			}
 
 			format := outfmt.GetFormat(cmd.Context())
 			if format == "json" {
@@ -223,6 +256,8 @@ Currently supports updating the cost/pay amount for a timesheet.`,
 	}
 
 	cmd.Flags().Float64Var(&cost, "cost", 0, "Total cost/pay amount for the timesheet")
+	edit.register(cmd)
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
 
 	return cmd
 }
@@ -311,10 +346,20 @@ Example:
 				return err
 			}
 
-			result, err := client.Timesheets().SetPayRule(cmd.Context(), timesheetID, payRuleID)
+			change, err := client.Timesheets().ChangePayRule(cmd.Context(), timesheetID, payRuleID)
 			if err != nil {
 				return err
 			}
+			result := &change.Result
+			recordUndo(cmd.Context(), client, undoTimesheetPayRule, timesheetID,
+				fmt.Sprintf("select pay rule %d for timesheet %d (was %d)", payRuleID, timesheetID, change.Previous.PayRule),
+				payRuleSnapshot{
+					PayReturn:     change.Previous.Id,
+					PayRule:       change.Previous.PayRule,
+					Cost:          change.Previous.Cost,
+					Overridden:    change.Previous.Overridden,
+					TimesheetCost: change.PreviousCost,
+				})
 
 			format := outfmt.GetFormat(cmd.Context())
 			if format == "json" {
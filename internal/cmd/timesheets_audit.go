@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// Audit severities, most serious first.
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityInfo    = "info"
+)
+
+var severityRank = map[string]int{severityError: 0, severityWarning: 1, severityInfo: 2}
+
+// Timesheet audit checks and the severity each is reported at.
+const (
+	auditInProgress   = "in-progress-too-long"
+	auditOverlap      = "overlap"
+	auditZeroCost     = "zero-cost"
+	auditOverDailyMax = "over-daily-max"
+	auditNoMealbreak  = "missing-mealbreak"
+	auditShortMeal    = "short-mealbreak"
+	auditNoRoster     = "no-roster"
+	auditBadMealbreak = "unreadable-mealbreak"
+)
+
+var auditSeverity = map[string]string{
+	auditInProgress:   severityError,
+	auditOverlap:      severityError,
+	auditZeroCost:     severityError,
+	auditOverDailyMax: severityWarning,
+	auditNoMealbreak:  severityWarning,
+	auditShortMeal:    severityWarning,
+	auditBadMealbreak: severityWarning,
+	auditNoRoster:     severityInfo,
+}
+
+// TimesheetFinding is one problem the audit found with a timesheet.
+type TimesheetFinding struct {
+	Severity     string `json:"severity"`
+	Check        string `json:"check"`
+	Date         string `json:"date"`
+	Employee     int    `json:"employee"`
+	EmployeeName string `json:"employeeName,omitempty"`
+	Timesheet    int    `json:"timesheet"`
+	// Related is the other timesheet of an overlap.
+	Related int    `json:"related,omitempty"`
+	Message string `json:"message"`
+}
+
+// TimesheetAuditLimits are the thresholds the audit checks against.
+type TimesheetAuditLimits struct {
+	MaxInProgress time.Duration
+	MaxDailyHours float64
+	// Timesheets longer than MealAfterHours need a meal break of at least
+	// MinMealbreak minutes.
+	MealAfterHours float64
+	MinMealbreak   int
+}
+
+// auditTimesheets checks leave-free timesheets against limits and the
+// rosters for the same period. In-progress timesheets are treated as ending
+// at now.
+func auditTimesheets(timesheets []api.Timesheet, rosters []api.Roster, limits TimesheetAuditLimits, tz *time.Location, now time.Time) []TimesheetFinding {
+	var findings []TimesheetFinding
+	add := func(t api.Timesheet, check, msg string) *TimesheetFinding {
+		findings = append(findings, TimesheetFinding{
+			Severity:  auditSeverity[check],
+			Check:     check,
+			Date:      reportDay(t.StartTime, t.Date, tz).Format("2006-01-02"),
+			Employee:  t.Employee,
+			Timesheet: t.Id,
+			Message:   msg,
+		})
+		return &findings[len(findings)-1]
+	}
+	end := func(t api.Timesheet) int64 {
+		if t.IsInProgress || t.EndTime == 0 {
+			return now.Unix()
+		}
+		return t.EndTime
+	}
+
+	byEmployee := make(map[int][]api.Timesheet)
+	daily := make(map[string]float64)
+	for _, t := range timesheets {
+		if t.IsLeave {
+			continue
+		}
+		byEmployee[t.Employee] = append(byEmployee[t.Employee], t)
+
+		if t.IsInProgress {
+			if open := now.Sub(time.Unix(t.StartTime, 0)); open > limits.MaxInProgress {
+				add(t, auditInProgress, fmt.Sprintf("in progress for %.1fh (limit %s)", open.Hours(), limits.MaxInProgress))
+			}
+			continue
+		}
+
+		daily[fmt.Sprintf("%d|%s", t.Employee, reportDay(t.StartTime, t.Date, tz).Format("2006-01-02"))] += t.TotalTime
+		if t.TotalTime > 0 && t.Cost == 0 {
+			add(t, auditZeroCost, fmt.Sprintf("%.2fh worked with no cost", t.TotalTime))
+		}
+		if t.TotalTime > limits.MealAfterHours {
+			minutes, ok := mealbreakMinutes(t.Mealbreak)
+			switch {
+			case !ok:
+				add(t, auditBadMealbreak, fmt.Sprintf("meal break %q could not be read", t.Mealbreak))
+			case minutes == 0:
+				add(t, auditNoMealbreak, fmt.Sprintf("no meal break in %.2fh (required after %gh)", t.TotalTime, limits.MealAfterHours))
+			case minutes < limits.MinMealbreak:
+				add(t, auditShortMeal, fmt.Sprintf("%d min meal break in %.2fh (minimum %d min)", minutes, t.TotalTime, limits.MinMealbreak))
+			}
+		}
+	}
+
+	// Daily totals are reported against the first timesheet of the day.
+	reported := make(map[string]bool)
+	for _, t := range timesheets {
+		key := fmt.Sprintf("%d|%s", t.Employee, reportDay(t.StartTime, t.Date, tz).Format("2006-01-02"))
+		if t.IsLeave || t.IsInProgress || reported[key] || daily[key] <= limits.MaxDailyHours {
+			continue
+		}
+		reported[key] = true
+		add(t, auditOverDailyMax, fmt.Sprintf("%.2fh worked this day (limit %gh)", daily[key], limits.MaxDailyHours))
+	}
+
+	for _, list := range byEmployee {
+		sort.Slice(list, func(i, j int) bool { return list[i].StartTime < list[j].StartTime })
+		for i := range list {
+			for j := i + 1; j < len(list) && list[j].StartTime < end(list[i]); j++ {
+				f := add(list[j], auditOverlap, fmt.Sprintf("overlaps timesheet %d", list[i].Id))
+				f.Related = list[i].Id
+			}
+		}
+	}
+
+	rostered := make(map[int][]api.Roster)
+	for _, r := range rosters {
+		if !r.Open && r.Employee != 0 {
+			rostered[r.Employee] = append(rostered[r.Employee], r)
+		}
+	}
+	for _, t := range timesheets {
+		if t.IsLeave {
+			continue
+		}
+		matched := false
+		for _, r := range rostered[t.Employee] {
+			if r.StartTime < end(t) && t.StartTime < r.EndTime {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			add(t, auditNoRoster, "no rostered shift overlaps this timesheet")
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if severityRank[a.Severity] != severityRank[b.Severity] {
+			return severityRank[a.Severity] < severityRank[b.Severity]
+		}
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Employee != b.Employee {
+			return a.Employee < b.Employee
+		}
+		return a.Timesheet < b.Timesheet
+	})
+	return findings
+}
+
+func newTimesheetsAuditCmd() *cobra.Command {
+	var fromDate, toDate, failOn string
+	var locationID int
+	var maxInProgress time.Duration
+	var limits TimesheetAuditLimits
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Check timesheets for problems before payroll",
+		Long: `Check the timesheets in a date range for problems worth fixing before
+payroll closes.
+
+Errors:
+  in-progress-too-long  still clocked in after --max-in-progress
+  overlap               overlaps another timesheet of the same employee
+  zero-cost             hours worked but no cost
+Warnings:
+  over-daily-max        an employee's hours for a day exceed --max-daily
+  missing-mealbreak     no meal break on a timesheet over --meal-after hours
+  short-mealbreak       meal break shorter than --min-mealbreak minutes
+  unreadable-mealbreak  meal break value could not be read
+Info:
+  no-roster             no rostered shift for the employee overlaps it
+
+Leave timesheets are skipped. The command exits with code 7 when it finds
+anything at or above --fail-on (error, warning, info or none).`,
+		Example: `  deputy timesheets audit --from 2024-07-01 --to 2024-07-14
+  deputy timesheets audit --from 2024-07-01 --to 2024-07-14 --location 3 --max-daily 10 -o json
+  deputy timesheets audit --from 2024-07-01 --to 2024-07-14 --fail-on warning || echo "fix before payroll"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromDate == "" || toDate == "" {
+				return errors.New("--from and --to are required")
+			}
+			from, _, err := parseDateFlag(fromDate, "--from")
+			if err != nil {
+				return err
+			}
+			to, _, err := parseDateFlag(toDate, "--to")
+			if err != nil {
+				return err
+			}
+			if from.After(to) {
+				return errors.New("--from must be on or before --to")
+			}
+			if _, ok := severityRank[failOn]; !ok && failOn != "none" {
+				return fmt.Errorf("invalid --fail-on %q (expected error, warning, info or none)", failOn)
+			}
+			limits.MaxInProgress = maxInProgress
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			tz := time.Local
+			var areas map[int]string
+			if locationID != 0 {
+				if tz, err = locationTimezone(ctx, client, locationID); err != nil {
+					return err
+				}
+				if areas, err = locationAreas(ctx, client, locationID); err != nil {
+					return err
+				}
+			}
+
+			search, err := dateRangeSearch(fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			timesheets, err := client.Timesheets().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+			rosters, err := client.Rosters().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+			rosters, timesheets = filterAttendanceRecords(rosters, timesheets, areas, 0)
+
+			findings := auditTimesheets(timesheets, rosters, limits, tz, time.Now())
+			names, err := employeeDisplayNames(ctx, client)
+			if err != nil {
+				return err
+			}
+			counts := map[string]int{}
+			failing := 0
+			for i := range findings {
+				findings[i].EmployeeName = names[findings[i].Employee]
+				counts[findings[i].Severity]++
+				if failOn != "none" && severityRank[findings[i].Severity] <= severityRank[failOn] {
+					failing++
+				}
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				if err := outfmt.New(ctx).OutputWithMeta(findings, map[string]any{
+					"count":      len(findings),
+					"from":       fromDate,
+					"to":         toDate,
+					"location":   locationID,
+					"timesheets": len(timesheets),
+					"errors":     counts[severityError],
+					"warnings":   counts[severityWarning],
+					"info":       counts[severityInfo],
+				}); err != nil {
+					return err
+				}
+			} else {
+				io := iocontext.FromContext(ctx)
+				if len(findings) == 0 {
+					_, _ = fmt.Fprintf(io.Out, "No problems found in %d timesheets\n", len(timesheets))
+					return nil
+				}
+				f := outfmt.New(ctx)
+				f.StartTable([]string{"SEVERITY", "DATE", "EMPLOYEE", "TIMESHEET", "CHECK", "DETAIL"})
+				for _, finding := range findings {
+					f.Row(
+						finding.Severity,
+						finding.Date,
+						employeeLabel(finding.Employee, finding.EmployeeName),
+						strconv.Itoa(finding.Timesheet),
+						finding.Check,
+						finding.Message,
+					)
+				}
+				f.EndTable()
+				_, _ = fmt.Fprintf(io.Out, "\n%d timesheets checked: %d errors, %d warnings, %d info\n",
+					len(timesheets), counts[severityError], counts[severityWarning], counts[severityInfo])
+			}
+
+			if failing > 0 {
+				return fmt.Errorf("%w: %d findings at or above %s", ErrFindings, failing, failOn)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fromDate, "from", "", "Start date (YYYY-MM-DD, required)")
+	cmd.Flags().StringVar(&toDate, "to", "", "End date (YYYY-MM-DD, required)")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Only check timesheets in this location's areas")
+	cmd.Flags().DurationVar(&maxInProgress, "max-in-progress", 16*time.Hour, "Flag timesheets in progress for longer than this")
+	cmd.Flags().Float64Var(&limits.MaxDailyHours, "max-daily", 12, "Flag employees working more hours than this in a day")
+	cmd.Flags().Float64Var(&limits.MealAfterHours, "meal-after", 5, "Hours after which a meal break is required")
+	cmd.Flags().IntVar(&limits.MinMealbreak, "min-mealbreak", 30, "Shortest acceptable meal break in minutes")
+	cmd.Flags().StringVar(&failOn, "fail-on", severityError, "Exit with code 7 on findings at or above: error, warning, info or none")
+
+	return cmd
+}
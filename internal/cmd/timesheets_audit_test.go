@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+func TestAuditTimesheets(t *testing.T) {
+	at := func(day, hour, minute int) int64 {
+		return time.Date(2024, 7, day, hour, minute, 0, 0, time.UTC).Unix()
+	}
+	now := time.Date(2024, 7, 3, 12, 0, 0, 0, time.UTC)
+	limits := TimesheetAuditLimits{MaxInProgress: 16 * time.Hour, MaxDailyHours: 12, MealAfterHours: 5, MinMealbreak: 30}
+	timesheets := []api.Timesheet{
+		// Rostered, costed, 30 min break; with 2 the day runs over 12h.
+		{Id: 1, Employee: 42, StartTime: at(1, 9, 0), EndTime: at(1, 17, 30), TotalTime: 8, Cost: 200, Mealbreak: "2024-07-01T00:30:00Z"},
+		// Overlaps 1, no roster, no break and no cost.
+		{Id: 2, Employee: 42, StartTime: at(1, 17, 0), EndTime: at(1, 23, 0), TotalTime: 6, Mealbreak: "2024-07-01T00:00:00Z"},
+		// Short break; rostered.
+		{Id: 3, Employee: 43, StartTime: at(2, 8, 0), EndTime: at(2, 14, 0), TotalTime: 5.75, Cost: 150, Mealbreak: "2024-07-02T00:15:00Z"},
+		// Left clocked in for a day.
+		{Id: 4, Employee: 43, StartTime: at(2, 15, 0), IsInProgress: true},
+		// Leave is never checked.
+		{Id: 5, Employee: 44, StartTime: at(1, 9, 0), EndTime: at(1, 17, 0), TotalTime: 8, IsLeave: true},
+	}
+	rosters := []api.Roster{
+		{Id: 70, Employee: 42, StartTime: at(1, 9, 0), EndTime: at(1, 17, 0)},
+		{Id: 71, Employee: 43, StartTime: at(2, 8, 0), EndTime: at(2, 14, 0)},
+		{Id: 72, Employee: 43, StartTime: at(2, 15, 0), EndTime: at(2, 20, 0)},
+		{Id: 73, Open: true, StartTime: at(1, 17, 0), EndTime: at(1, 23, 0)},
+	}
+
+	findings := auditTimesheets(timesheets, rosters, limits, time.UTC, now)
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Severity+" "+f.Check+" "+f.Date+" "+strconv.Itoa(f.Timesheet))
+	}
+	assert.Equal(t, []string{
+		"error zero-cost 2024-07-01 2",
+		"error overlap 2024-07-01 2",
+		"error in-progress-too-long 2024-07-02 4",
+		"warning over-daily-max 2024-07-01 1",
+		"warning missing-mealbreak 2024-07-01 2",
+		"warning short-mealbreak 2024-07-02 3",
+		"info no-roster 2024-07-01 2",
+	}, got)
+	assert.Equal(t, 1, findings[1].Related)
+	assert.Equal(t, "in progress for 21.0h (limit 16h0m0s)", findings[2].Message)
+	assert.Equal(t, "14.00h worked this day (limit 12h)", findings[3].Message)
+}
+
+func auditServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/resource/Timesheet/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":1,"Employee":42,"Date":"2024-07-01","StartTime":1719824400,"EndTime":1719853200,"TotalTime":8,"Cost":0,"Mealbreak":"2024-07-01T00:30:00Z","OperationalUnit":7},` +
+				`{"Id":2,"Employee":43,"Date":"2024-07-01","StartTime":1719824400,"EndTime":1719838800,"TotalTime":4,"Cost":80,"OperationalUnit":9}]`))
+		case "/api/v1/resource/Roster/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":70,"Employee":42,"StartTime":1719824400,"EndTime":1719853200,"OperationalUnit":7}]`))
+		case "/api/v1/resource/Company/3":
+			_, _ = w.Write([]byte(`{"Id":3,"Timezone":"UTC"}`))
+		case "/api/v1/resource/OperationalUnit":
+			_, _ = w.Write([]byte(`[{"Id":7,"Company":3,"CompanyName":"Bar"}]`))
+		case "/api/v1/supervise/employee":
+			_, _ = w.Write([]byte(`[{"Id":42,"DisplayName":"Jane Doe"}]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+}
+
+func TestTimesheetsAudit(t *testing.T) {
+	server := auditServer(t)
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "timesheets", "audit", "--from", "2024-07-01", "--to", "2024-07-01", "--location", "3", "-o", "json")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrFindings))
+	assert.Equal(t, ExitFindings, ExitCodeFromError(err))
+	var parsed struct {
+		Items []TimesheetFinding `json:"items"`
+		Meta  map[string]any     `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 1, "timesheet 2 is outside the location")
+	assert.Equal(t, TimesheetFinding{
+		Severity: severityError, Check: auditZeroCost, Date: "2024-07-01", Employee: 42, EmployeeName: "Jane Doe",
+		Timesheet: 1, Message: "8.00h worked with no cost",
+	}, parsed.Items[0])
+	assert.Equal(t, 1.0, parsed.Meta["timesheets"])
+
+	out, _, err = runRoot(t, server, "", "timesheets", "audit", "--from", "2024-07-01", "--to", "2024-07-01", "--fail-on", "none", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Jane Doe (42)")
+	assert.Contains(t, out, "2 timesheets checked: 1 errors, 0 warnings, 1 info\n")
+
+	_, _, err = runRoot(t, server, "", "timesheets", "audit", "--from", "2024-07-01", "--to", "2024-07-01", "--fail-on", "critical")
+	require.EqualError(t, err, `invalid --fail-on "critical" (expected error, warning, info or none)`)
+}
@@ -89,6 +89,11 @@ func TestTimesheetsGetCommand_WithMockClient(t *testing.T) {
 func TestTimesheetsUpdateCommand_WithMockClient(t *testing.T) {
 	t.Run("updates cost and outputs text", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				// Snapshot for undo.
+				_ = json.NewEncoder(w).Encode(api.Timesheet{Id: 123, Cost: 100})
+				return
+			}
 			assert.Equal(t, http.MethodPost, r.Method)
 			assert.Equal(t, "/api/v1/resource/Timesheet/123", r.URL.Path)
 			w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+const timesheetTimeLayout = "2006-01-02 15:04"
+
+// timesheetEditFlags are the fields shared by timesheets add and update.
+type timesheetEditFlags struct {
+	start, end, mealbreak, comment string
+	opunit                         int
+}
+
+func (e *timesheetEditFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&e.start, "start", "", `Start time, "YYYY-MM-DD HH:MM" or "HH:MM" in the location's timezone`)
+	cmd.Flags().StringVar(&e.end, "end", "", `End time, "YYYY-MM-DD HH:MM" or "HH:MM" (an earlier HH:MM runs past midnight)`)
+	cmd.Flags().StringVar(&e.mealbreak, "mealbreak", "", "Meal break in minutes or as a duration like 30m")
+	cmd.Flags().IntVar(&e.opunit, "opunit", 0, "Operational unit (area) ID")
+	cmd.Flags().StringVar(&e.comment, "comment", "", "Timesheet comment")
+}
+
+func (e *timesheetEditFlags) changed(cmd *cobra.Command) bool {
+	for _, name := range []string{"start", "end", "mealbreak", "opunit", "comment"} {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply merges the flags that were set into input. HH:MM times fall on the
+// date input already starts on.
+func (e *timesheetEditFlags) apply(cmd *cobra.Command, input *api.SaveTimesheetInput, tz *time.Location) error {
+	date := ""
+	if input.StartTime != 0 {
+		date = time.Unix(input.StartTime, 0).In(tz).Format("2006-01-02")
+	}
+	if cmd.Flags().Changed("start") {
+		start, _, err := parseTimesheetTime(e.start, date, tz, "--start")
+		if err != nil {
+			return err
+		}
+		input.StartTime = start.Unix()
+		date = start.Format("2006-01-02")
+	}
+	if cmd.Flags().Changed("end") {
+		end, dated, err := parseTimesheetTime(e.end, date, tz, "--end")
+		if err != nil {
+			return err
+		}
+		if !dated && end.Unix() <= input.StartTime {
+			end = end.AddDate(0, 0, 1)
+		}
+		input.EndTime = end.Unix()
+	}
+	if input.EndTime != 0 && input.EndTime <= input.StartTime {
+		return errors.New("--end must be after --start")
+	}
+	if cmd.Flags().Changed("mealbreak") {
+		minutes, err := parseMealbreak(e.mealbreak)
+		if err != nil {
+			return err
+		}
+		input.MealbreakMinutes = &minutes
+	}
+	if cmd.Flags().Changed("comment") {
+		comment := e.comment
+		input.Comment = &comment
+	}
+	return nil
+}
+
+// parseTimesheetTime parses "YYYY-MM-DD HH:MM" (or with a T) in tz. A bare
+// "HH:MM" falls on date; dated reports whether value carried its own date.
+func parseTimesheetTime(value, date string, tz *time.Location, flagName string) (t time.Time, dated bool, err error) {
+	value = strings.TrimSpace(value)
+	if day, clock, ok := strings.Cut(strings.Replace(value, "T", " ", 1), " "); ok {
+		if validateDateFormat(day) != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s %q: expected YYYY-MM-DD HH:MM or HH:MM", flagName, value)
+		}
+		minutes, err := parseClock(strings.TrimSpace(clock), flagName)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return atClock(day, minutes, tz), true, nil
+	}
+	if !strings.Contains(value, ":") {
+		return time.Time{}, false, fmt.Errorf("invalid %s %q: expected YYYY-MM-DD HH:MM or HH:MM", flagName, value)
+	}
+	if date == "" {
+		return time.Time{}, false, fmt.Errorf("%s %q needs a date: use YYYY-MM-DD HH:MM", flagName, value)
+	}
+	minutes, err := parseClock(value, flagName)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return atClock(date, minutes, tz), false, nil
+}
+
+// parseMealbreak accepts whole minutes ("30") or a duration ("30m", "1h").
+func parseMealbreak(s string) (int, error) {
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+		return n, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 || d%time.Minute != 0 {
+		return 0, fmt.Errorf("invalid --mealbreak %q: expected minutes or a duration like 30m", s)
+	}
+	return int(d / time.Minute), nil
+}
+
+// mealbreakMinutes reads the meal break Deputy reports on a timesheet, a
+// time of day such as "2024-07-01T00:30:00+10:00" whose clock is the length.
+func mealbreakMinutes(s string) (int, bool) {
+	if s == "" {
+		return 0, true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Hour()*60 + t.Minute(), true
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Hour()*60 + t.Minute(), true
+		}
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	return 0, false
+}
+
+// opunitTimezone returns the timezone of the location an operational unit
+// belongs to, falling back to local time when there is no unit.
+func opunitTimezone(ctx context.Context, client *api.Client, opunitID int) (*time.Location, error) {
+	if opunitID == 0 {
+		return time.Local, nil
+	}
+	dept, err := client.Departments().Get(ctx, opunitID)
+	if err != nil {
+		return nil, err
+	}
+	if dept.Company == 0 {
+		return time.Local, nil
+	}
+	return locationTimezone(ctx, client, dept.Company)
+}
+
+func formatTimesheetTime(ts int64, tz *time.Location) string {
+	if ts == 0 {
+		return "(none)"
+	}
+	return time.Unix(ts, 0).In(tz).Format(timesheetTimeLayout)
+}
+
+// timesheetChange is one field an edit changes, shown before confirming.
+type timesheetChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// timesheetChanges lists the fields input changes on before.
+func timesheetChanges(before *api.Timesheet, input *api.SaveTimesheetInput, tz *time.Location) []timesheetChange {
+	var changes []timesheetChange
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, timesheetChange{Field: field, Before: from, After: to})
+		}
+	}
+	add("Start", formatTimesheetTime(before.StartTime, tz), formatTimesheetTime(input.StartTime, tz))
+	add("End", formatTimesheetTime(before.EndTime, tz), formatTimesheetTime(input.EndTime, tz))
+	if input.MealbreakMinutes != nil {
+		current := "(unknown)"
+		if m, ok := mealbreakMinutes(before.Mealbreak); ok {
+			current = fmt.Sprintf("%d min", m)
+		}
+		add("Mealbreak", current, fmt.Sprintf("%d min", *input.MealbreakMinutes))
+	}
+	add("OpUnit", strconv.Itoa(before.OperationalUnit), strconv.Itoa(input.OperationalUnit))
+	if input.Comment != nil {
+		add("Comment", before.Comment, *input.Comment)
+	}
+	return changes
+}
+
+func printTimesheetChanges(w io.Writer, changes []timesheetChange) {
+	for _, c := range changes {
+		_, _ = fmt.Fprintf(w, "  %s: %s -> %s\n", c.Field, c.Before, c.After)
+	}
+}
+
+// editTimesheet saves the edit flags (and cost, when set) onto timesheet id
+// after previewing the changes and confirming.
+func editTimesheet(cmd *cobra.Command, client *api.Client, id int, edit *timesheetEditFlags, cost *float64, yes bool) error {
+	ctx := cmd.Context()
+	before, err := client.Timesheets().Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if before.IsLeave {
+		return fmt.Errorf("timesheet %d is a leave timesheet; edit the leave request instead", id)
+	}
+
+	input := &api.SaveTimesheetInput{
+		Timesheet:       id,
+		Employee:        before.Employee,
+		StartTime:       before.StartTime,
+		EndTime:         before.EndTime,
+		OperationalUnit: before.OperationalUnit,
+		Comment:         &before.Comment,
+	}
+	// The endpoint saves the whole timesheet, so keep the current meal
+	// break unless it is being changed.
+	if minutes, ok := mealbreakMinutes(before.Mealbreak); ok {
+		input.MealbreakMinutes = &minutes
+	}
+	if cmd.Flags().Changed("opunit") {
+		input.OperationalUnit = edit.opunit
+	}
+	tz, err := opunitTimezone(ctx, client, input.OperationalUnit)
+	if err != nil {
+		return err
+	}
+	if err := edit.apply(cmd, input, tz); err != nil {
+		return err
+	}
+
+	changes := timesheetChanges(before, input, tz)
+	if cost != nil && *cost != before.Cost {
+		changes = append(changes, timesheetChange{Field: "Cost", Before: fmt.Sprintf("%.2f", before.Cost), After: fmt.Sprintf("%.2f", *cost)})
+	}
+
+	format := outfmt.GetFormat(ctx)
+	out := iocontext.FromContext(ctx).Out
+	if len(changes) == 0 {
+		if format == "json" {
+			return outfmt.New(ctx).Output(before)
+		}
+		_, _ = fmt.Fprintf(out, "Timesheet %d already matches; nothing to update\n", id)
+		return nil
+	}
+	if format != "json" {
+		_, _ = fmt.Fprintf(out, "Timesheet %d (employee %d):\n", id, before.Employee)
+		printTimesheetChanges(out, changes)
+	}
+	if err := confirmDestructive(ctx, yes, fmt.Sprintf("Update timesheet %d?", id)); err != nil {
+		return err
+	}
+
+	timesheet, err := client.Timesheets().Save(ctx, input)
+	if err != nil {
+		return err
+	}
+	snapshot := timesheetEditSnapshot{
+		Employee:        before.Employee,
+		StartTime:       before.StartTime,
+		EndTime:         before.EndTime,
+		OperationalUnit: before.OperationalUnit,
+		Comment:         before.Comment,
+		Timezone:        tz.String(),
+	}
+	if minutes, ok := mealbreakMinutes(before.Mealbreak); ok {
+		snapshot.Mealbreak = &minutes
+	}
+	if cost != nil {
+		snapshot.Cost = &before.Cost
+	}
+	// The save has landed, so record the undo before the cost update: if that
+	// fails the edit can still be rolled back, and restoring an unchanged
+	// cost is harmless.
+	recordUndo(ctx, client, undoTimesheetEdit, id, fmt.Sprintf("edit timesheet %d (%d fields)", id, len(changes)), snapshot)
+	if cost != nil {
+		timesheet, err = client.Timesheets().Update(ctx, id, &api.UpdateTimesheetInput{Cost: cost})
+		if err != nil {
+			return err
+		}
+	}
+
+	if format == "json" {
+		return outfmt.New(ctx).Output(timesheet)
+	}
+	_, _ = fmt.Fprintf(out, "Updated timesheet %d\n", id)
+	return nil
+}
+
+func newTimesheetsAddCmd() *cobra.Command {
+	var employeeID int
+	var edit timesheetEditFlags
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a timesheet for a missed punch",
+		Long: `Create a timesheet for an employee who did not clock in or out.
+
+Times are read in the timezone of the location the operational unit belongs
+to. An --end given as HH:MM before --start runs past midnight.`,
+		Example: `  deputy timesheets add --employee 42 --opunit 7 --start "2024-07-01 09:00" --end 17:30 --mealbreak 30m
+  deputy timesheets add --employee email:jane@example.com --opunit 7 --start "2024-07-01 22:00" --end 06:00`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if employeeID == 0 {
+				return errors.New("--employee is required")
+			}
+			if edit.opunit == 0 {
+				return errors.New("--opunit is required")
+			}
+			if edit.start == "" || edit.end == "" {
+				return errors.New("--start and --end are required")
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			tz, err := opunitTimezone(ctx, client, edit.opunit)
+			if err != nil {
+				return err
+			}
+			input := &api.SaveTimesheetInput{Employee: employeeID, OperationalUnit: edit.opunit}
+			if err := edit.apply(cmd, input, tz); err != nil {
+				return err
+			}
+
+			format := outfmt.GetFormat(ctx)
+			out := iocontext.FromContext(ctx).Out
+			if format != "json" {
+				_, _ = fmt.Fprintf(out, "New timesheet for employee %d:\n", employeeID)
+				_, _ = fmt.Fprintf(out, "  Start: %s\n", formatTimesheetTime(input.StartTime, tz))
+				_, _ = fmt.Fprintf(out, "  End: %s\n", formatTimesheetTime(input.EndTime, tz))
+				if input.MealbreakMinutes != nil {
+					_, _ = fmt.Fprintf(out, "  Mealbreak: %d min\n", *input.MealbreakMinutes)
+				}
+				_, _ = fmt.Fprintf(out, "  OpUnit: %d\n", input.OperationalUnit)
+				if input.Comment != nil {
+					_, _ = fmt.Fprintf(out, "  Comment: %s\n", *input.Comment)
+				}
+			}
+			if err := confirmDestructive(ctx, yes, "Add this timesheet?"); err != nil {
+				return err
+			}
+
+			timesheet, err := client.Timesheets().Save(ctx, input)
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				return outfmt.New(ctx).Output(timesheet)
+			}
+			_, _ = fmt.Fprintf(out, "Added timesheet %d for employee %d\n", timesheet.Id, employeeID)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&employeeID, "employee", 0, "Employee ID (required)")
+	edit.register(cmd)
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
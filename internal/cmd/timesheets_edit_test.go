@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/undo"
+)
+
+func TestParseTimesheetTime(t *testing.T) {
+	tz, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+
+	got, dated, err := parseTimesheetTime("2024-07-01 09:15", "", tz, "--start")
+	require.NoError(t, err)
+	assert.True(t, dated)
+	assert.Equal(t, time.Date(2024, 7, 1, 9, 15, 0, 0, tz), got)
+
+	got, dated, err = parseTimesheetTime("17:30", "2024-07-01", tz, "--end")
+	require.NoError(t, err)
+	assert.False(t, dated)
+	assert.Equal(t, time.Date(2024, 7, 1, 17, 30, 0, 0, tz), got)
+
+	_, _, err = parseTimesheetTime("17:30", "", tz, "--start")
+	require.EqualError(t, err, `--start "17:30" needs a date: use YYYY-MM-DD HH:MM`)
+	_, _, err = parseTimesheetTime("tomorrow", "2024-07-01", tz, "--end")
+	require.EqualError(t, err, `invalid --end "tomorrow": expected YYYY-MM-DD HH:MM or HH:MM`)
+}
+
+func TestParseMealbreak(t *testing.T) {
+	for in, want := range map[string]int{"30": 30, "45m": 45, "1h": 60, "0": 0} {
+		got, err := parseMealbreak(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	_, err := parseMealbreak("half an hour")
+	require.EqualError(t, err, `invalid --mealbreak "half an hour": expected minutes or a duration like 30m`)
+
+	minutes, ok := mealbreakMinutes("2024-07-01T00:30:00+10:00")
+	assert.True(t, ok)
+	assert.Equal(t, 30, minutes)
+}
+
+// timesheetEditServer fakes timesheet 5 in an area of a Sydney location and
+// applies each save to it.
+func timesheetEditServer(t *testing.T) (*httptest.Server, *api.Timesheet, *[]api.SaveTimesheetInput) {
+	t.Helper()
+	tz, err := time.LoadLocation("Australia/Sydney")
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	timesheet := &api.Timesheet{
+		Id:              5,
+		Employee:        42,
+		StartTime:       time.Date(2024, 7, 1, 9, 0, 0, 0, tz).Unix(),
+		EndTime:         time.Date(2024, 7, 1, 17, 0, 0, 0, tz).Unix(),
+		Mealbreak:       "2024-07-01T00:30:00+10:00",
+		OperationalUnit: 7,
+		Comment:         "Late bus",
+		Cost:            200,
+	}
+	var saves []api.SaveTimesheetInput
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/supervise/timesheet/5", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode(timesheet)
+	})
+	mux.HandleFunc("/api/v1/resource/OperationalUnit/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Id":7,"Company":3}`))
+	})
+	mux.HandleFunc("/api/v1/resource/Company/3", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Id":3,"Timezone":"Australia/Sydney"}`))
+	})
+	mux.HandleFunc("/api/v1/supervise/timesheet/update", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var input api.SaveTimesheetInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		saves = append(saves, input)
+		if input.Timesheet == 0 {
+			_ = json.NewEncoder(w).Encode(api.Timesheet{Id: 600, Employee: input.Employee, StartTime: input.StartTime, EndTime: input.EndTime})
+			return
+		}
+		timesheet.StartTime, timesheet.EndTime = input.StartTime, input.EndTime
+		timesheet.OperationalUnit = input.OperationalUnit
+		if input.MealbreakMinutes != nil {
+			timesheet.Mealbreak = time.Date(2024, 7, 1, 0, *input.MealbreakMinutes, 0, 0, tz).Format(time.RFC3339)
+		}
+		if input.Comment != nil {
+			timesheet.Comment = *input.Comment
+		}
+		_ = json.NewEncoder(w).Encode(timesheet)
+	})
+	return httptest.NewServer(mux), timesheet, &saves
+}
+
+func TestTimesheetsUpdate_EditAndUndo(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	server, timesheet, saves := timesheetEditServer(t)
+	defer server.Close()
+	tz, _ := time.LoadLocation("Australia/Sydney")
+	originalStart, originalEnd := timesheet.StartTime, timesheet.EndTime
+
+	// Declining the preview sends nothing.
+	out, _, err := runRoot(t, server, "n\n", "timesheets", "update", "5", "--start", "08:30", "--mealbreak", "45m", "-o", "text")
+	require.EqualError(t, err, "operation cancelled")
+	assert.Contains(t, out, "Start: 2024-07-01 09:00 -> 2024-07-01 08:30\n")
+	assert.Contains(t, out, "Mealbreak: 30 min -> 45 min\n")
+	assert.NotContains(t, out, "End:")
+	assert.Empty(t, *saves)
+
+	out, _, err = runRoot(t, server, "y\n", "timesheets", "update", "5", "--start", "08:30", "--end", "01:00", "--comment", "", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "End: 2024-07-01 17:00 -> 2024-07-02 01:00\n")
+	assert.Contains(t, out, "Updated timesheet 5\n")
+	require.Len(t, *saves, 1)
+	saved := (*saves)[0]
+	assert.Equal(t, 5, saved.Timesheet)
+	assert.Equal(t, 42, saved.Employee)
+	assert.Equal(t, time.Date(2024, 7, 1, 8, 30, 0, 0, tz).Unix(), saved.StartTime)
+	assert.Equal(t, time.Date(2024, 7, 2, 1, 0, 0, 0, tz).Unix(), saved.EndTime)
+	assert.Equal(t, 30, *saved.MealbreakMinutes, "unchanged meal break is kept")
+	assert.Equal(t, "", *saved.Comment)
+
+	out, _, err = runRoot(t, server, "", "undo", "--yes", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Comment:  -> Late bus\n")
+	assert.Equal(t, originalStart, timesheet.StartTime)
+	assert.Equal(t, originalEnd, timesheet.EndTime)
+	assert.Equal(t, "Late bus", timesheet.Comment)
+
+	_, _, err = runRoot(t, server, "", "timesheets", "update", "5", "--start", "2024-07-01 09:00", "--end", "2024-07-01 08:00")
+	require.EqualError(t, err, "--end must be after --start")
+
+	_, _, err = runRoot(t, server, "", "timesheets", "update", "5")
+	require.EqualError(t, err, "nothing to update: set --start, --end, --mealbreak, --opunit, --comment or --cost")
+}
+
+func TestTimesheetsUpdate_CostFailureStillRecordsUndo(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	// The fixture has no cost endpoint, so the save lands and the cost update fails.
+	server, timesheet, saves := timesheetEditServer(t)
+	defer server.Close()
+	originalStart := timesheet.StartTime
+
+	_, _, err := runRoot(t, server, "", "timesheets", "update", "5", "--start", "08:30", "--cost", "250", "--yes", "-o", "text")
+	require.Error(t, err)
+	require.Len(t, *saves, 1)
+	assert.NotEqual(t, originalStart, timesheet.StartTime)
+
+	ops, err := undo.List()
+	require.NoError(t, err)
+	require.Len(t, ops, 1)
+	assert.Equal(t, undoTimesheetEdit, ops[0].Kind)
+	var snapshot timesheetEditSnapshot
+	require.NoError(t, json.Unmarshal(ops[0].Before, &snapshot))
+	assert.Equal(t, originalStart, snapshot.StartTime)
+	require.NotNil(t, snapshot.Cost)
+	assert.Equal(t, 200.0, *snapshot.Cost)
+}
+
+func TestTimesheetsAdd(t *testing.T) {
+	server, _, saves := timesheetEditServer(t)
+	defer server.Close()
+	tz, _ := time.LoadLocation("Australia/Sydney")
+
+	out, _, err := runRoot(t, server, "", "timesheets", "add", "--employee", "42", "--opunit", "7",
+		"--start", "2024-07-01 22:00", "--end", "06:00", "--mealbreak", "30", "--yes", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "  End: 2024-07-02 06:00\n")
+	assert.Contains(t, out, "Added timesheet 600 for employee 42\n")
+	require.Len(t, *saves, 1)
+	assert.Equal(t, 0, (*saves)[0].Timesheet)
+	assert.Equal(t, time.Date(2024, 7, 1, 22, 0, 0, 0, tz).Unix(), (*saves)[0].StartTime)
+	assert.Equal(t, time.Date(2024, 7, 2, 6, 0, 0, 0, tz).Unix(), (*saves)[0].EndTime)
+
+	_, _, err = runRoot(t, server, "", "timesheets", "add", "--employee", "42", "--opunit", "7", "--start", "22:00", "--end", "06:00")
+	require.EqualError(t, err, `--start "22:00" needs a date: use YYYY-MM-DD HH:MM`)
+
+	_, _, err = runRoot(t, server, "", "timesheets", "add", "--employee", "42", "--start", "2024-07-01 22:00", "--end", "06:00")
+	require.EqualError(t, err, "--opunit is required")
+}
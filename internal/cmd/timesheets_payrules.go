@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// PayRulesFile is the rules file read by `timesheets assign-pay-rules`.
+// Rules are tried in order and the first whose conditions all hold picks the
+// pay rule; a rule without conditions matches every timesheet.
+type PayRulesFile struct {
+	// Holidays are extra public holiday dates (YYYY-MM-DD) on top of the
+	// PublicHoliday records in Deputy.
+	Holidays []string       `yaml:"holidays"`
+	Rules    []PayRuleMatch `yaml:"rules"`
+}
+
+// PayRuleMatch maps timesheet conditions to a pay rule.
+type PayRuleMatch struct {
+	Name          string   `yaml:"name"`
+	Days          []string `yaml:"days"`
+	PublicHoliday *bool    `yaml:"publicHoliday"`
+	HoursOver     float64  `yaml:"hoursOver"`
+	Areas         []int    `yaml:"areas"`
+	Roles         []int    `yaml:"roles"`
+	PayRule       int      `yaml:"payRule"`
+
+	days map[time.Weekday]bool
+}
+
+func (m *PayRuleMatch) label(i int) string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return fmt.Sprintf("rule %d", i+1)
+}
+
+// matches reports whether the timesheet, worked on a day that is or is not a
+// public holiday by an employee with the given role, meets every condition.
+func (m *PayRuleMatch) matches(t api.Timesheet, day time.Time, holiday bool, role int) bool {
+	if len(m.days) > 0 && !m.days[day.Weekday()] {
+		return false
+	}
+	if m.PublicHoliday != nil && *m.PublicHoliday != holiday {
+		return false
+	}
+	if m.HoursOver > 0 && t.TotalTime <= m.HoursOver {
+		return false
+	}
+	if len(m.Areas) > 0 && !containsInt(m.Areas, t.OperationalUnit) {
+		return false
+	}
+	if len(m.Roles) > 0 && !containsInt(m.Roles, role) {
+		return false
+	}
+	return true
+}
+
+func (f *PayRulesFile) validate() error {
+	if len(f.Rules) == 0 {
+		return errors.New("no rules defined")
+	}
+	for _, d := range f.Holidays {
+		if err := validateDateFormat(d); err != nil {
+			return fmt.Errorf("holidays: %w", err)
+		}
+	}
+	for i := range f.Rules {
+		m := &f.Rules[i]
+		if m.PayRule == 0 {
+			return fmt.Errorf("%s: payRule is required", m.label(i))
+		}
+		if m.HoursOver < 0 {
+			return fmt.Errorf("%s: hoursOver must not be negative", m.label(i))
+		}
+		if len(m.Days) > 0 {
+			days, err := parseWeekdays(strings.Join(m.Days, ","))
+			if err != nil {
+				return fmt.Errorf("%s: %w", m.label(i), err)
+			}
+			m.days = make(map[time.Weekday]bool, len(days))
+			for _, d := range days {
+				m.days[d] = true
+			}
+		}
+	}
+	return nil
+}
+
+func (f *PayRulesFile) usesHolidays() bool {
+	for _, m := range f.Rules {
+		if m.PublicHoliday != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// publicHolidayDates returns the dates in [from, to] with a PublicHoliday
+// record.
+func publicHolidayDates(ctx context.Context, client *api.Client, from, to string) (map[string]bool, error) {
+	search, err := dateRangeSearch(from, to)
+	if err != nil {
+		return nil, err
+	}
+	records, err := client.Resource("PublicHoliday").QueryAll(ctx, &api.QueryInput{Search: search})
+	if err != nil {
+		return nil, err
+	}
+	dates := make(map[string]bool, len(records))
+	for _, r := range records {
+		if date, _ := r["Date"].(string); date != "" {
+			dates[datePart(date)] = true
+		}
+	}
+	return dates, nil
+}
+
+// PayRuleAssignment is the pay rule a rules file picks for one timesheet and
+// what it does to the cost.
+type PayRuleAssignment struct {
+	Timesheet      int     `json:"timesheet"`
+	Date           string  `json:"date"`
+	Employee       int     `json:"employee"`
+	EmployeeName   string  `json:"employeeName,omitempty"`
+	Hours          float64 `json:"hours"`
+	Rule           string  `json:"rule"`
+	PayReturn      int     `json:"payReturn"`
+	CurrentPayRule int     `json:"currentPayRule"`
+	PayRule        int     `json:"payRule"`
+	CurrentCost    float64 `json:"currentCost"`
+	Cost           float64 `json:"cost"`
+	Difference     float64 `json:"difference"`
+	Error          string  `json:"error,omitempty"`
+
+	previous api.TimesheetPayReturn
+}
+
+// matchPayRules picks a pay rule for each finished, non-leave timesheet with
+// hours. Timesheets no rule matches are left out.
+func matchPayRules(file *PayRulesFile, timesheets []api.Timesheet, holidays map[string]bool, roles map[int]int, names map[int]string, rates map[int]api.PayRule) []PayRuleAssignment {
+	var out []PayRuleAssignment
+	for _, t := range timesheets {
+		if t.IsLeave || t.IsInProgress || t.TotalTime <= 0 {
+			continue
+		}
+		date := datePart(t.Date)
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		for i := range file.Rules {
+			m := &file.Rules[i]
+			if !m.matches(t, day, holidays[date], roles[t.Employee]) {
+				continue
+			}
+			out = append(out, PayRuleAssignment{
+				Timesheet:    t.Id,
+				Date:         date,
+				Employee:     t.Employee,
+				EmployeeName: names[t.Employee],
+				Hours:        t.TotalTime,
+				Rule:         m.label(i),
+				PayRule:      m.PayRule,
+				CurrentCost:  t.Cost,
+				Cost:         roundCents(rates[m.PayRule].HourlyRate * t.TotalTime),
+			})
+			break
+		}
+	}
+	return out
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func newTimesheetsAssignPayRulesCmd() *cobra.Command {
+	var fromDate, toDate, rulesPath string
+	var concurrency int
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "assign-pay-rules",
+		Short: "Assign pay rules to timesheets by rule",
+		Long: `Pick a pay rule for every timesheet in a date range from a rules file.
+
+Each rule lists conditions and the pay rule to use when they all hold. Rules
+are tried in order and the first match wins; timesheets no rule matches, and
+in-progress or leave timesheets, are left alone.
+
+  holidays: [2024-12-26]      # extra public holidays (optional)
+  rules:
+    - name: Public holiday
+      publicHoliday: true
+      payRule: 310
+    - name: Overtime
+      hoursOver: 10           # TotalTime above this many hours
+      payRule: 320
+    - name: Weekend bar
+      days: [sat, sun]
+      areas: [7, 8]           # operational unit IDs
+      payRule: 330
+    - name: Supervisors
+      roles: [50]             # employee role IDs
+      payRule: 340
+
+Pay rules are read once, the new cost is the pay rule's hourly rate times
+the timesheet's hours, and the cost difference is shown before asking for
+confirmation. The selections can be reversed with 'deputy undo'.`,
+		Example: `  deputy timesheets assign-pay-rules --from 2024-07-01 --to 2024-07-14 --rules rules.yaml
+  deputy timesheets assign-pay-rules --from 2024-07-01 --to 2024-07-14 --rules rules.yaml --dry-run`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromDate == "" || toDate == "" {
+				return errors.New("--from and --to are required")
+			}
+			if rulesPath == "" {
+				return errors.New("--rules is required")
+			}
+			from, _, err := parseDateFlag(fromDate, "--from")
+			if err != nil {
+				return err
+			}
+			to, _, err := parseDateFlag(toDate, "--to")
+			if err != nil {
+				return err
+			}
+			if from.After(to) {
+				return errors.New("--from must be on or before --to")
+			}
+			if concurrency < 1 {
+				return errors.New("--concurrency must be at least 1")
+			}
+
+			ctx := cmd.Context()
+			io := iocontext.FromContext(ctx)
+			file := &PayRulesFile{}
+			if err := readStrictYAML(rulesPath, io.In, file); err != nil {
+				return err
+			}
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			payRules, err := client.Timesheets().ListPayRules(ctx, nil)
+			if err != nil {
+				return err
+			}
+			rates := make(map[int]api.PayRule, len(payRules))
+			for _, r := range payRules {
+				rates[r.Id] = r
+			}
+			for i, m := range file.Rules {
+				if _, ok := rates[m.PayRule]; !ok {
+					return fmt.Errorf("%s: pay rule %d not found", m.label(i), m.PayRule)
+				}
+			}
+
+			search, err := dateRangeSearch(fromDate, toDate)
+			if err != nil {
+				return err
+			}
+			timesheets, err := client.Timesheets().QueryAll(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+
+			holidays := map[string]bool{}
+			if file.usesHolidays() {
+				if holidays, err = publicHolidayDates(ctx, client, fromDate, toDate); err != nil {
+					return err
+				}
+			}
+			for _, d := range file.Holidays {
+				holidays[d] = true
+			}
+			employees, err := client.Employees().List(ctx, nil)
+			if err != nil {
+				return err
+			}
+			names := make(map[int]string, len(employees))
+			roles := make(map[int]int, len(employees))
+			for _, e := range employees {
+				names[e.Id] = e.DisplayName
+				roles[e.Id] = e.Role
+			}
+
+			matched := matchPayRules(file, timesheets, holidays, roles, names, rates)
+			forEachConcurrent(len(matched), concurrency, func(i int) {
+				a := &matched[i]
+				existing, err := client.Timesheets().GetPayReturn(ctx, a.Timesheet)
+				if err != nil {
+					a.Error = err.Error()
+					return
+				}
+				a.previous = *existing
+				a.PayReturn = existing.Id
+				a.CurrentPayRule = existing.PayRule
+				a.Difference = roundCents(a.Cost - a.CurrentCost)
+			})
+
+			var changes, failed []PayRuleAssignment
+			var before, after float64
+			for _, a := range matched {
+				switch {
+				case a.Error != "":
+					failed = append(failed, a)
+				case a.CurrentPayRule != a.PayRule || a.Difference != 0:
+					changes = append(changes, a)
+					before += a.CurrentCost
+					after += a.Cost
+				}
+			}
+			sort.Slice(changes, func(i, j int) bool {
+				if changes[i].Date != changes[j].Date {
+					return changes[i].Date < changes[j].Date
+				}
+				return changes[i].Timesheet < changes[j].Timesheet
+			})
+
+			format := outfmt.GetFormat(ctx)
+			if format != "json" {
+				for _, a := range failed {
+					_, _ = fmt.Fprintf(io.ErrOut, "warning: timesheet %d skipped: %s\n", a.Timesheet, a.Error)
+				}
+				if len(changes) == 0 {
+					_, _ = fmt.Fprintf(io.Out, "No pay rule changes for %d timesheets matched by %s\n", len(matched)-len(failed), rulesPath)
+					return nil
+				}
+				printPayRuleAssignments(ctx, changes, rates)
+				_, _ = fmt.Fprintf(io.Out, "\n%d timesheets, cost $%.2f -> $%.2f (%+.2f)\n", len(changes), before, after, after-before)
+			}
+			if len(changes) > 0 {
+				if err := confirmDestructive(ctx, yes, fmt.Sprintf("Assign pay rules to %d timesheets?", len(changes))); err != nil {
+					return err
+				}
+			}
+
+			forEachConcurrent(len(changes), concurrency, func(i int) {
+				a := &changes[i]
+				_, err := client.Timesheets().UpdatePayReturn(ctx, a.PayReturn, a.Timesheet, &api.SetPayRuleInput{
+					PayRule:    a.PayRule,
+					Cost:       a.Cost,
+					Overridden: true,
+				})
+				if err != nil {
+					a.Error = err.Error()
+				}
+			})
+
+			var applied []payRuleBatchEntry
+			var errs []error
+			for _, a := range changes {
+				if a.Error != "" {
+					errs = append(errs, fmt.Errorf("timesheet %d: %s", a.Timesheet, a.Error))
+					continue
+				}
+				applied = append(applied, payRuleBatchEntry{
+					Timesheet: a.Timesheet,
+					payRuleSnapshot: payRuleSnapshot{
+						PayReturn:     a.previous.Id,
+						PayRule:       a.previous.PayRule,
+						Cost:          a.previous.Cost,
+						Overridden:    a.previous.Overridden,
+						TimesheetCost: a.CurrentCost,
+					},
+				})
+			}
+			if len(applied) > 0 {
+				recordUndo(ctx, client, undoTimesheetPayRules, 0,
+					fmt.Sprintf("assign pay rules to %d timesheets from %s to %s", len(applied), fromDate, toDate),
+					payRuleBatchSnapshot{Timesheets: applied})
+			}
+
+			if format == "json" {
+				if err := outfmt.New(ctx).OutputWithMeta(changes, map[string]any{
+					"count":       len(changes),
+					"from":        fromDate,
+					"to":          toDate,
+					"matched":     len(matched),
+					"skipped":     failed,
+					"currentCost": roundCents(before),
+					"cost":        roundCents(after),
+					"difference":  roundCents(after - before),
+				}); err != nil {
+					return err
+				}
+			} else {
+				_, _ = fmt.Fprintf(io.Out, "Assigned pay rules to %d of %d timesheets\n", len(applied), len(changes))
+			}
+			return errors.Join(errs...)
+		},
+	}
+
+	cmd.Flags().StringVar(&fromDate, "from", "", "Start date (YYYY-MM-DD, required)")
+	cmd.Flags().StringVar(&toDate, "to", "", "End date (YYYY-MM-DD, required)")
+	cmd.Flags().StringVar(&rulesPath, "rules", "", "Rules file (YAML), or - for stdin (required)")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Timesheets read and updated at once")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func printPayRuleAssignments(ctx context.Context, changes []PayRuleAssignment, rates map[int]api.PayRule) {
+	f := outfmt.New(ctx)
+	f.StartTable([]string{"TIMESHEET", "DATE", "EMPLOYEE", "HOURS", "RULE", "PAY RULE", "COST", "DIFF"})
+	for _, a := range changes {
+		payRule := strconv.Itoa(a.PayRule)
+		if title := rates[a.PayRule].PayTitle; title != "" {
+			payRule = fmt.Sprintf("%s (%d)", title, a.PayRule)
+		}
+		if a.CurrentPayRule != a.PayRule {
+			payRule = fmt.Sprintf("%d -> %s", a.CurrentPayRule, payRule)
+		}
+		f.Row(
+			strconv.Itoa(a.Timesheet),
+			a.Date,
+			employeeLabel(a.Employee, a.EmployeeName),
+			fmt.Sprintf("%.2f", a.Hours),
+			a.Rule,
+			payRule,
+			fmt.Sprintf("%.2f -> %.2f", a.CurrentCost, a.Cost),
+			fmt.Sprintf("%+.2f", a.Difference),
+		)
+	}
+	f.EndTable()
+}
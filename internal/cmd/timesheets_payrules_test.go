@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+const testPayRules = `holidays: [2024-07-02]
+rules:
+  - name: Public holiday
+    publicHoliday: true
+    payRule: 310
+  - name: Long shift
+    hoursOver: 10
+    payRule: 320
+  - name: Weekend bar
+    days: [sat, sun]
+    areas: [7]
+    payRule: 330
+  - name: Supervisors
+    roles: [50]
+    payRule: 340
+`
+
+func writePayRules(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestMatchPayRules(t *testing.T) {
+	file := &PayRulesFile{}
+	require.NoError(t, readStrictYAML(writePayRules(t, testPayRules), nil, file))
+	rates := map[int]api.PayRule{310: {Id: 310, HourlyRate: 50}, 320: {Id: 320, HourlyRate: 40}, 330: {Id: 330, HourlyRate: 35}, 340: {Id: 340, HourlyRate: 30}}
+	timesheets := []api.Timesheet{
+		{Id: 1, Employee: 42, Date: "2024-07-02T00:00:00+10:00", TotalTime: 8},      // holiday
+		{Id: 2, Employee: 42, Date: "2024-07-03", TotalTime: 11},                    // long shift
+		{Id: 3, Employee: 43, Date: "2024-07-06", TotalTime: 6, OperationalUnit: 7}, // Saturday in the bar
+		{Id: 4, Employee: 43, Date: "2024-07-06", TotalTime: 6, OperationalUnit: 8}, // Saturday elsewhere
+		{Id: 5, Employee: 50, Date: "2024-07-04", TotalTime: 5},                     // supervisor
+		{Id: 6, Employee: 50, Date: "2024-07-04", TotalTime: 5, IsInProgress: true}, // still running
+	}
+
+	got := matchPayRules(file, timesheets, map[string]bool{"2024-07-02": true}, map[int]int{50: 50}, nil, rates)
+	require.Len(t, got, 4)
+	assert.Equal(t, []int{1, 2, 3, 5}, []int{got[0].Timesheet, got[1].Timesheet, got[2].Timesheet, got[3].Timesheet})
+	assert.Equal(t, "Public holiday", got[0].Rule)
+	assert.Equal(t, 400.0, got[0].Cost)
+	assert.Equal(t, 320, got[1].PayRule)
+	assert.Equal(t, 330, got[2].PayRule)
+	assert.Equal(t, 150.0, got[3].Cost)
+
+	err := readStrictYAML(writePayRules(t, "rules:\n  - days: [funday]\n    payRule: 1\n"), nil, &PayRulesFile{})
+	require.ErrorContains(t, err, `rule 1: invalid weekday "funday"`)
+	err = readStrictYAML(writePayRules(t, "rules:\n  - name: x\n    payrule: 1\n"), nil, &PayRulesFile{})
+	require.ErrorContains(t, err, "field payrule not found")
+}
+
+// payRulesServer fakes two timesheets with pay returns and records the
+// pay return writes.
+func payRulesServer(t *testing.T) (*httptest.Server, map[int]*api.TimesheetPayReturn) {
+	t.Helper()
+	var mu sync.Mutex
+	returns := map[int]*api.TimesheetPayReturn{
+		11: {Id: 901, Timesheet: 11, PayRule: 300, Cost: 200},
+		12: {Id: 902, Timesheet: 12, PayRule: 330, Cost: 210},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/PayRules/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"Id":300,"PayTitle":"Ordinary","HourlyRate":25},{"Id":320,"PayTitle":"Overtime","HourlyRate":40},{"Id":330,"PayTitle":"Weekend","HourlyRate":35},{"Id":310,"HourlyRate":50},{"Id":340,"HourlyRate":30}]`))
+	})
+	mux.HandleFunc("/api/v1/resource/Timesheet/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"Id":11,"Employee":42,"Date":"2024-07-03","TotalTime":11,"Cost":200},{"Id":12,"Employee":43,"Date":"2024-07-06","TotalTime":6,"OperationalUnit":7,"Cost":210},{"Id":13,"Employee":43,"Date":"2024-07-04","TotalTime":6,"Cost":150}]`))
+	})
+	mux.HandleFunc("/api/v1/resource/PublicHoliday/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("/api/v1/supervise/employee", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"Id":42,"DisplayName":"Jane Doe"},{"Id":43,"DisplayName":"Bob Ray"}]`))
+	})
+	mux.HandleFunc("/api/v1/resource/TimesheetPayReturn/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		var body api.QueryInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		id := int(body.Search["s1"].(map[string]interface{})["data"].(float64))
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode([]api.TimesheetPayReturn{*returns[id]})
+	})
+	mux.HandleFunc("/api/v1/resource/TimesheetPayReturn/", func(w http.ResponseWriter, r *http.Request) {
+		var input api.SetPayRuleInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		mu.Lock()
+		defer mu.Unlock()
+		for _, pr := range returns {
+			if fmt.Sprintf("/api/v1/resource/TimesheetPayReturn/%d", pr.Id) == r.URL.Path {
+				pr.PayRule, pr.Cost, pr.Overridden = input.PayRule, input.Cost, input.Overridden
+				_ = json.NewEncoder(w).Encode(pr)
+				return
+			}
+		}
+		t.Errorf("unexpected pay return %s", r.URL.Path)
+	})
+	mux.HandleFunc("/api/v1/resource/Timesheet/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	})
+	return httptest.NewServer(mux), returns
+}
+
+func TestTimesheetsAssignPayRules(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	server, returns := payRulesServer(t)
+	defer server.Close()
+	rules := writePayRules(t, testPayRules)
+
+	out, _, err := runRoot(t, server, "n\n", "timesheets", "assign-pay-rules", "--from", "2024-07-01", "--to", "2024-07-07", "--rules", rules, "-o", "text")
+	require.EqualError(t, err, "operation cancelled")
+	assert.Contains(t, out, "300 -> Overtime (320)")
+	assert.Contains(t, out, "200.00 -> 440.00")
+	assert.NotContains(t, out, "Weekend (330)", "timesheet 12 already has its pay rule and cost")
+	assert.Contains(t, out, "1 timesheets, cost $200.00 -> $440.00 (+240.00)")
+	assert.Equal(t, 300, returns[11].PayRule)
+
+	out, _, err = runRoot(t, server, "", "timesheets", "assign-pay-rules", "--from", "2024-07-01", "--to", "2024-07-07", "--rules", rules, "-o", "json")
+	require.NoError(t, err)
+	var parsed struct {
+		Items []PayRuleAssignment `json:"items"`
+		Meta  struct {
+			Difference float64 `json:"difference"`
+		} `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 1)
+	assert.Equal(t, 240.0, parsed.Meta.Difference)
+	assert.Equal(t, 320, returns[11].PayRule)
+	assert.Equal(t, 440.0, returns[11].Cost)
+	assert.True(t, returns[11].Overridden)
+
+	out, _, err = runRoot(t, server, "", "undo", "--yes", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Timesheet 11 PayRule: 320 (440.00) -> 300 (200.00)")
+	assert.Equal(t, 300, returns[11].PayRule)
+	assert.Equal(t, 200.0, returns[11].Cost)
+
+	_, _, err = runRoot(t, server, "", "timesheets", "assign-pay-rules", "--from", "2024-07-01", "--to", "2024-07-07",
+		"--rules", writePayRules(t, "rules:\n  - payRule: 999\n"))
+	require.EqualError(t, err, "rule 1: pay rule 999 not found")
+
+	_, _, err = runRoot(t, server, "", "timesheets", "assign-pay-rules", "--from", "2024-07-01", "--to", "2024-07-07")
+	require.EqualError(t, err, "--rules is required")
+}
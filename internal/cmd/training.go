@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+)
+
+// Training expiry statuses.
+const (
+	trainingExpired  = "expired"
+	trainingExpiring = "expiring"
+)
+
+// TrainingExpiry is an employee whose latest record of a module has lapsed
+// or lapses within the report window.
+type TrainingExpiry struct {
+	Employee     int    `json:"employee"`
+	EmployeeName string `json:"employeeName,omitempty"`
+	Module       int    `json:"module"`
+	ModuleTitle  string `json:"moduleTitle,omitempty"`
+	Record       int    `json:"record"`
+	ExpiryDate   string `json:"expiryDate"`
+	DaysLeft     int    `json:"daysLeft"`
+	Status       string `json:"status"`
+	// RosteredAfterExpiry lists the employee's shifts in the window that
+	// fall after the expiry date (with --check-rosters).
+	RosteredAfterExpiry []string `json:"rosteredAfterExpiry,omitempty"`
+}
+
+func newTrainingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "training",
+		Short: "Manage training modules, records and expiring certifications",
+	}
+
+	modules := &cobra.Command{
+		Use:   "modules",
+		Short: "Manage training modules",
+	}
+	modules.AddCommand(newTrainingModulesListCmd())
+
+	cmd.AddCommand(modules)
+	cmd.AddCommand(newTrainingAssignCmd())
+	cmd.AddCommand(newTrainingRecordsCmd())
+	cmd.AddCommand(newTrainingExpiringCmd())
+
+	return cmd
+}
+
+// findTrainingModule matches s against a module's ID or, ignoring case, its
+// title.
+func findTrainingModule(modules []api.TrainingModule, s string) (api.TrainingModule, error) {
+	id, idErr := strconv.Atoi(s)
+	for _, m := range modules {
+		if (idErr == nil && m.Id == id) || strings.EqualFold(m.Title, s) {
+			return m, nil
+		}
+	}
+	return api.TrainingModule{}, fmt.Errorf("no training module matches %q", s)
+}
+
+func moduleTitles(modules []api.TrainingModule) map[int]string {
+	titles := make(map[int]string, len(modules))
+	for _, m := range modules {
+		titles[m.Id] = m.Title
+	}
+	return titles
+}
+
+func newTrainingModulesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List training modules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := getClientFromContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			modules, err := client.Training().ListModules(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			format := outfmt.GetFormat(cmd.Context())
+			if format == "json" {
+				f := outfmt.New(cmd.Context())
+				return f.OutputList(modules)
+			}
+
+			f := outfmt.New(cmd.Context())
+			f.StartTable([]string{"ID", "TITLE", "ACTIVE"})
+			for _, m := range modules {
+				active := "Yes"
+				if !m.Active {
+					active = "No"
+				}
+				f.Row(strconv.Itoa(m.Id), m.Title, active)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+}
+
+// trainingExpiryDate resolves --expires, either a date or a duration after
+// the training date such as 365d or 52w.
+func trainingExpiryDate(expires, trainingDate string) (string, error) {
+	if expires == "" {
+		return "", nil
+	}
+	if validateDateFormat(expires) == nil {
+		return expires, nil
+	}
+	d, err := parseRelativeDuration(expires)
+	if err != nil {
+		return "", fmt.Errorf("invalid --expires %q: expected YYYY-MM-DD or a duration like 365d", expires)
+	}
+	start, _ := time.Parse("2006-01-02", trainingDate)
+	return start.Add(d).Format("2006-01-02"), nil
+}
+
+func newTrainingAssignCmd() *cobra.Command {
+	var date, expires, comment string
+
+	cmd := &cobra.Command{
+		Use:   "assign <employee-id> <module>",
+		Short: "Record that an employee completed a training module",
+		Long: `Record that an employee completed a training module on --date (default
+today). The module is an ID or a title. --expires is the date the
+certification lapses, or a duration after --date such as 365d.`,
+		Example: `  deputy training assign 42 "First Aid" --expires 2027-07-01
+  deputy training assign email:jane@example.com 3 --date 2024-07-01 --expires 156w`,
+		Args: RequireArgs("employee-id", "module"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			employeeID, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid employee ID: %s", args[0])
+			}
+			if date == "" {
+				date = time.Now().Format("2006-01-02")
+			} else if err := validateDateFormat(date); err != nil {
+				return err
+			}
+			expiry, err := trainingExpiryDate(expires, date)
+			if err != nil {
+				return err
+			}
+			if expiry != "" && expiry <= date {
+				return errors.New("--expires must be after the training date")
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			modules, err := client.Training().ListModules(ctx)
+			if err != nil {
+				return err
+			}
+			module, err := findTrainingModule(modules, args[1])
+			if err != nil {
+				return err
+			}
+
+			record, err := client.Training().AddRecord(ctx, &api.CreateTrainingRecordInput{
+				Employee:     employeeID,
+				Module:       module.Id,
+				TrainingDate: date,
+				ExpiryDate:   expiry,
+				Comment:      comment,
+				Active:       true,
+			})
+			if err != nil {
+				return err
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).Output(record)
+			}
+			io := iocontext.FromContext(ctx)
+			msg := fmt.Sprintf("Recorded %s for employee %d (record %d)", module.Title, employeeID, record.Id)
+			if expiry != "" {
+				msg += ", expires " + expiry
+			}
+			_, _ = fmt.Fprintln(io.Out, msg)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&date, "date", "", "Date the training was completed YYYY-MM-DD (default: today)")
+	cmd.Flags().StringVar(&expires, "expires", "", "Expiry date YYYY-MM-DD or a duration after --date (e.g. 365d)")
+	cmd.Flags().StringVar(&comment, "comment", "", "Comment")
+
+	return cmd
+}
+
+func newTrainingRecordsCmd() *cobra.Command {
+	var employeeID int
+	var module string
+
+	cmd := &cobra.Command{
+		Use:   "records",
+		Short: "List training records",
+		Example: `  deputy training records --employee 42
+  deputy training records --module "First Aid" -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			modules, err := client.Training().ListModules(ctx)
+			if err != nil {
+				return err
+			}
+
+			search := map[string]interface{}{}
+			if employeeID != 0 {
+				search["s1"] = map[string]interface{}{"field": "Employee", "type": "eq", "data": employeeID}
+			}
+			if module != "" {
+				m, err := findTrainingModule(modules, module)
+				if err != nil {
+					return err
+				}
+				search["s2"] = map[string]interface{}{"field": "Module", "type": "eq", "data": m.Id}
+			}
+			records, err := client.Training().QueryRecords(ctx, &api.QueryInput{Search: search})
+			if err != nil {
+				return err
+			}
+			sort.SliceStable(records, func(i, j int) bool { return records[i].TrainingDate > records[j].TrainingDate })
+
+			if outfmt.GetFormat(ctx) == "json" {
+				return outfmt.New(ctx).OutputList(records)
+			}
+			titles := moduleTitles(modules)
+			f := outfmt.New(ctx)
+			f.StartTable([]string{"ID", "EMPLOYEE", "MODULE", "COMPLETED", "EXPIRES"})
+			for _, r := range records {
+				expires := datePart(r.ExpiryDate)
+				if expires == "" {
+					expires = "-"
+				}
+				f.Row(strconv.Itoa(r.Id), strconv.Itoa(r.Employee), titles[r.Module], datePart(r.TrainingDate), expires)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&employeeID, "employee", 0, "Only this employee's records")
+	cmd.Flags().StringVar(&module, "module", "", "Only records of this module (ID or title)")
+
+	return cmd
+}
+
+// findExpiringTraining reports, for each employee and module, the latest
+// active record when it has lapsed or lapses on or before horizon. A later
+// record (or one that never expires) supersedes an earlier one; deactivated
+// records are ignored.
+func findExpiringTraining(records []api.TrainingRecord, employees map[int]string, modules map[int]bool, today, horizon time.Time) []TrainingExpiry {
+	type key struct{ employee, module int }
+	latest := map[key]api.TrainingRecord{}
+	for _, r := range records {
+		if !r.Active {
+			continue
+		}
+		if _, ok := employees[r.Employee]; !ok || (len(modules) > 0 && !modules[r.Module]) {
+			continue
+		}
+		k := key{r.Employee, r.Module}
+		prev, seen := latest[k]
+		switch {
+		case !seen:
+			latest[k] = r
+		case prev.ExpiryDate == "":
+		case r.ExpiryDate == "" || datePart(r.ExpiryDate) > datePart(prev.ExpiryDate):
+			latest[k] = r
+		}
+	}
+
+	todayDate, horizonDate := today.Format("2006-01-02"), horizon.Format("2006-01-02")
+	var out []TrainingExpiry
+	for k, r := range latest {
+		expiry := datePart(r.ExpiryDate)
+		if expiry == "" || expiry > horizonDate {
+			continue
+		}
+		expiresAt, err := time.ParseInLocation("2006-01-02", expiry, today.Location())
+		if err != nil {
+			continue
+		}
+		status := trainingExpiring
+		if expiry < todayDate {
+			status = trainingExpired
+		}
+		out = append(out, TrainingExpiry{
+			Employee:     k.employee,
+			EmployeeName: employees[k.employee],
+			Module:       k.module,
+			Record:       r.Id,
+			ExpiryDate:   expiry,
+			DaysLeft:     int(expiresAt.Sub(today).Round(24*time.Hour) / (24 * time.Hour)),
+			Status:       status,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ExpiryDate != out[j].ExpiryDate {
+			return out[i].ExpiryDate < out[j].ExpiryDate
+		}
+		if out[i].Employee != out[j].Employee {
+			return out[i].Employee < out[j].Employee
+		}
+		return out[i].Module < out[j].Module
+	})
+	return out
+}
+
+// trainingRosterConflicts fills in each expiry's shifts dated after its
+// expiry date.
+func trainingRosterConflicts(expiries []TrainingExpiry, rosters []api.Roster) {
+	for i := range expiries {
+		for _, r := range rosters {
+			if r.Employee == expiries[i].Employee && datePart(r.Date) > expiries[i].ExpiryDate {
+				expiries[i].RosteredAfterExpiry = append(expiries[i].RosteredAfterExpiry, datePart(r.Date))
+			}
+		}
+		sort.Strings(expiries[i].RosteredAfterExpiry)
+	}
+}
+
+func newTrainingExpiringCmd() *cobra.Command {
+	var within string
+	var locationID int
+	var moduleArgs []string
+	var checkRosters bool
+
+	cmd := &cobra.Command{
+		Use:   "expiring",
+		Short: "List certifications that have lapsed or lapse soon",
+		Long: `List active employees whose latest record of a training module has expired
+or expires within --within. Limit the report to the modules your staff must
+hold with --module, and to a location's employees with --location.
+
+With --check-rosters, each employee's shifts in the window are checked and
+any scheduled after their certification lapses are listed.`,
+		Example: `  deputy training expiring --within 30d
+  deputy training expiring --within 8w --location code:CBD --module "First Aid" --check-rosters`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			window, err := parseRelativeDuration(within)
+			if err != nil {
+				return fmt.Errorf("invalid --within %q: expected a duration like 30d or 8w", within)
+			}
+
+			ctx := cmd.Context()
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			modules, err := client.Training().ListModules(ctx)
+			if err != nil {
+				return err
+			}
+			wanted := map[int]bool{}
+			for _, s := range moduleArgs {
+				m, err := findTrainingModule(modules, s)
+				if err != nil {
+					return err
+				}
+				wanted[m.Id] = true
+			}
+
+			tz := time.Local
+			var staff []api.Employee
+			if locationID != 0 {
+				if tz, err = locationTimezone(ctx, client, locationID); err != nil {
+					return err
+				}
+				if staff, err = locationEmployees(ctx, client, locationID); err != nil {
+					return err
+				}
+			} else {
+				all, err := client.Employees().List(ctx, nil)
+				if err != nil {
+					return err
+				}
+				for _, e := range all {
+					if e.Active {
+						staff = append(staff, e)
+					}
+				}
+			}
+			names := make(map[int]string, len(staff))
+			for _, e := range staff {
+				names[e.Id] = e.DisplayName
+			}
+
+			records, err := client.Training().QueryRecords(ctx, nil)
+			if err != nil {
+				return err
+			}
+			today := atClock(time.Now().In(tz).Format("2006-01-02"), 0, tz)
+			horizon := today.Add(window)
+			expiries := findExpiringTraining(records, names, wanted, today, horizon)
+			titles := moduleTitles(modules)
+			for i := range expiries {
+				expiries[i].ModuleTitle = titles[expiries[i].Module]
+			}
+
+			if checkRosters && len(expiries) > 0 {
+				search, err := dateRangeSearch(today.Format("2006-01-02"), horizon.Format("2006-01-02"))
+				if err != nil {
+					return err
+				}
+				rosters, err := client.Rosters().QueryAll(ctx, &api.QueryInput{Search: search})
+				if err != nil {
+					return err
+				}
+				trainingRosterConflicts(expiries, rosters)
+			}
+
+			if outfmt.GetFormat(ctx) == "json" {
+				if expiries == nil {
+					expiries = []TrainingExpiry{}
+				}
+				return outfmt.New(ctx).OutputWithMeta(expiries, map[string]interface{}{
+					"count":   len(expiries),
+					"today":   today.Format("2006-01-02"),
+					"horizon": horizon.Format("2006-01-02"),
+				})
+			}
+
+			headers := []string{"EMPLOYEE", "MODULE", "EXPIRES", "DAYS", "STATUS"}
+			if checkRosters {
+				headers = append(headers, "ROSTERED AFTER EXPIRY")
+			}
+			f := outfmt.New(ctx)
+			f.StartTable(headers)
+			for _, e := range expiries {
+				row := []string{
+					employeeLabel(e.Employee, e.EmployeeName),
+					e.ModuleTitle,
+					e.ExpiryDate,
+					strconv.Itoa(e.DaysLeft),
+					e.Status,
+				}
+				if checkRosters {
+					row = append(row, strings.Join(e.RosteredAfterExpiry, ", "))
+				}
+				f.Row(row...)
+			}
+			f.EndTable()
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&within, "within", "30d", "Report certifications expiring within this duration (e.g. 30d, 8w)")
+	cmd.Flags().IntVar(&locationID, "location", 0, "Only employees whose main location is this one")
+	cmd.Flags().StringSliceVar(&moduleArgs, "module", nil, "Only these modules (ID or title, repeatable)")
+	cmd.Flags().BoolVar(&checkRosters, "check-rosters", false, "List shifts in the window scheduled after a certification lapses")
+
+	return cmd
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+)
+
+const trainingModules = `[{"Id":1,"Title":"First Aid","Active":true},{"Id":2,"Title":"RSA","Active":true},{"Id":3,"Title":"Induction","Active":false}]`
+
+func TestFindExpiringTraining(t *testing.T) {
+	today := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	horizon := today.AddDate(0, 0, 30)
+	records := []api.TrainingRecord{
+		{Id: 1, Employee: 42, Module: 1, ExpiryDate: "2024-06-20", Active: true},           // expired...
+		{Id: 2, Employee: 42, Module: 1, ExpiryDate: "2025-06-20T00:00:00Z", Active: true}, // ...but renewed
+		{Id: 3, Employee: 42, Module: 2, ExpiryDate: "2024-07-15", Active: true},           // expiring
+		{Id: 4, Employee: 43, Module: 2, ExpiryDate: "2024-06-30", Active: true},           // expired
+		{Id: 5, Employee: 43, Module: 1, Active: true},                                     // never expires
+		{Id: 6, Employee: 43, Module: 1, ExpiryDate: "2024-06-01", Active: true},           // superseded by Id 5
+		{Id: 7, Employee: 99, Module: 2, ExpiryDate: "2024-06-01", Active: true},           // not in the employee set
+		{Id: 8, Employee: 42, Module: 3, ExpiryDate: "2024-08-15", Active: true},           // beyond the window
+		{Id: 9, Employee: 42, Module: 2, ExpiryDate: "2025-07-15"},                         // deactivated, so Id 3 still stands
+		{Id: 10, Employee: 43, Module: 3, ExpiryDate: "2024-06-10"},                        // deactivated, not reported
+	}
+	employees := map[int]string{42: "Jane Doe", 43: "Bob Ray"}
+
+	got := findExpiringTraining(records, employees, nil, today, horizon)
+	require.Len(t, got, 2)
+	assert.Equal(t, TrainingExpiry{Employee: 43, EmployeeName: "Bob Ray", Module: 2, Record: 4, ExpiryDate: "2024-06-30", DaysLeft: -1, Status: trainingExpired}, got[0])
+	assert.Equal(t, 3, got[1].Record)
+	assert.Equal(t, 14, got[1].DaysLeft)
+	assert.Equal(t, trainingExpiring, got[1].Status)
+
+	got = findExpiringTraining(records, employees, map[int]bool{1: true}, today, horizon)
+	assert.Empty(t, got)
+}
+
+func TestTrainingAssign(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/resource/TrainingModule":
+			_, _ = w.Write([]byte(trainingModules))
+		case "POST /api/v1/resource/TrainingRecord":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			_, _ = w.Write([]byte(`{"Id":9,"Employee":42,"Module":1}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "training", "assign", "42", "first aid", "--date", "2024-07-01", "--expires", "365d", "-o", "text")
+	require.NoError(t, err)
+	assert.Equal(t, "Recorded First Aid for employee 42 (record 9), expires 2025-07-01\n", out)
+	assert.Equal(t, 1.0, body["Module"])
+	assert.Equal(t, "2024-07-01", body["TrainingDate"])
+	assert.Equal(t, "2025-07-01", body["ExpiryDate"])
+
+	_, _, err = runRoot(t, server, "", "training", "assign", "42", "Forklift")
+	require.EqualError(t, err, `no training module matches "Forklift"`)
+
+	_, _, err = runRoot(t, server, "", "training", "assign", "42", "1", "--date", "2024-07-01", "--expires", "2024-06-01")
+	require.EqualError(t, err, "--expires must be after the training date")
+}
+
+func TestTrainingExpiring_ChecksRosters(t *testing.T) {
+	soon := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+	later := time.Now().AddDate(0, 0, 10).Format("2006-01-02")
+	earlier := time.Now().AddDate(0, 0, 2).Format("2006-01-02")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/resource/TrainingModule":
+			_, _ = w.Write([]byte(trainingModules))
+		case "/api/v1/resource/Company/3":
+			_, _ = w.Write([]byte(`{"Id":3}`))
+		case "/api/v1/supervise/employee":
+			_, _ = w.Write([]byte(`[{"Id":42,"DisplayName":"Jane Doe","Company":3,"Active":true},{"Id":50,"DisplayName":"Elsewhere","Company":4,"Active":true}]`))
+		case "/api/v1/resource/TrainingRecord/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":1,"Employee":42,"Module":2,"ExpiryDate":"` + soon + `","Active":true},{"Id":2,"Employee":50,"Module":2,"ExpiryDate":"` + soon + `","Active":true}]`))
+		case "/api/v1/resource/Roster/QUERY":
+			_, _ = w.Write([]byte(`[{"Id":7,"Employee":42,"Date":"` + later + `"},{"Id":8,"Employee":42,"Date":"` + earlier + `"}]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	out, _, err := runRoot(t, server, "", "training", "expiring", "--within", "2w", "--location", "3", "--module", "RSA", "--check-rosters", "-o", "json")
+	require.NoError(t, err)
+	var parsed struct {
+		Items []TrainingExpiry `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &parsed), out)
+	require.Len(t, parsed.Items, 1)
+	assert.Equal(t, "RSA", parsed.Items[0].ModuleTitle)
+	assert.Equal(t, 5, parsed.Items[0].DaysLeft)
+	assert.Equal(t, []string{later}, parsed.Items[0].RosteredAfterExpiry)
+
+	_, _, err = runRoot(t, server, "", "training", "expiring", "--within", "soon")
+	require.EqualError(t, err, `invalid --within "soon": expected a duration like 30d or 8w`)
+}
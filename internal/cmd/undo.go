@@ -0,0 +1,609 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/audit"
+	"github.com/salmonumbrella/deputy-cli/internal/iocontext"
+	"github.com/salmonumbrella/deputy-cli/internal/outfmt"
+	"github.com/salmonumbrella/deputy-cli/internal/undo"
+)
+
+// Kinds of reversible operation. Each has an entry in undoHandlers.
+const (
+	undoEmployeeTerminate      = "employee.terminate"
+	undoEmployeeReactivate     = "employee.reactivate"
+	undoEmployeeAssignLocation = "employee.assign-location"
+	undoEmployeeRemoveLocation = "employee.remove-location"
+	undoTimesheetCost          = "timesheet.cost"
+	undoTimesheetEdit          = "timesheet.edit"
+	undoTimesheetPayRule       = "timesheet.pay-rule"
+	undoTimesheetPayRules      = "timesheet.pay-rules"
+	undoLocationSettings       = "location.settings"
+	undoAgreementUpdate        = "agreement.update"
+)
+
+// Snapshots of the state each kind of operation replaced.
+type employeeStatusSnapshot struct {
+	Active          bool   `json:"active"`
+	TerminationDate string `json:"terminationDate,omitempty"`
+}
+
+type employeeLocationSnapshot struct {
+	Location int  `json:"location"`
+	Assigned bool `json:"assigned"`
+}
+
+type timesheetCostSnapshot struct {
+	Cost float64 `json:"cost"`
+}
+
+type timesheetEditSnapshot struct {
+	Employee        int      `json:"employee"`
+	StartTime       int64    `json:"startTime"`
+	EndTime         int64    `json:"endTime"`
+	OperationalUnit int      `json:"operationalUnit"`
+	Mealbreak       *int     `json:"mealbreak,omitempty"`
+	Comment         string   `json:"comment"`
+	Cost            *float64 `json:"cost,omitempty"`
+	// Timezone is only used to show the times when previewing.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type payRuleSnapshot struct {
+	PayReturn     int     `json:"payReturn"`
+	PayRule       int     `json:"payRule"`
+	Cost          float64 `json:"cost"`
+	Overridden    bool    `json:"overridden"`
+	TimesheetCost float64 `json:"timesheetCost"`
+}
+
+// payRuleBatchSnapshot holds the pay returns one assign-pay-rules run
+// replaced.
+type payRuleBatchSnapshot struct {
+	Timesheets []payRuleBatchEntry `json:"timesheets"`
+}
+
+type payRuleBatchEntry struct {
+	Timesheet int `json:"timesheet"`
+	payRuleSnapshot
+}
+
+type locationSettingsSnapshot struct {
+	// Settings holds the previous values of keys the update changed.
+	Settings map[string]interface{} `json:"settings"`
+	// Added lists keys that did not exist before; they cannot be removed.
+	Added []string `json:"added,omitempty"`
+}
+
+type agreementSnapshot struct {
+	BaseRate *float64        `json:"baseRate,omitempty"`
+	Config   json.RawMessage `json:"config,omitempty"`
+}
+
+// undoChange is one field undo would change, shown before confirming.
+type undoChange struct {
+	Field    string `json:"field"`
+	Current  string `json:"current"`
+	Restored string `json:"restored"`
+}
+
+// undoHandler knows how to preview and reverse one kind of operation.
+type undoHandler struct {
+	preview func(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error)
+	restore func(ctx context.Context, client *api.Client, op undo.Operation) error
+}
+
+var undoHandlers = map[string]undoHandler{
+	undoEmployeeTerminate:      {previewEmployeeStatus, restoreEmployeeStatus},
+	undoEmployeeReactivate:     {previewEmployeeStatus, restoreEmployeeStatus},
+	undoEmployeeAssignLocation: {previewEmployeeLocation, restoreEmployeeLocation},
+	undoEmployeeRemoveLocation: {previewEmployeeLocation, restoreEmployeeLocation},
+	undoTimesheetCost:          {previewTimesheetCost, restoreTimesheetCost},
+	undoTimesheetEdit:          {previewTimesheetEdit, restoreTimesheetEdit},
+	undoTimesheetPayRule:       {previewPayRule, restorePayRule},
+	undoTimesheetPayRules:      {previewPayRuleBatch, restorePayRuleBatch},
+	undoLocationSettings:       {previewLocationSettings, restoreLocationSettings},
+	undoAgreementUpdate:        {previewAgreement, restoreAgreement},
+}
+
+func newUndoCmd() *cobra.Command {
+	var yes, list bool
+
+	cmd := &cobra.Command{
+		Use:   "undo [op-id]",
+		Short: "Reverse a recent change",
+		Long: `Restore the state saved before a reversible change.
+
+These commands save what they change so it can be put back:
+  employees terminate / reactivate
+  employees assign-location / remove-location
+  timesheets update
+  timesheets select-pay-rule / assign-pay-rules
+  locations settings-update
+  pay agreements update
+
+Without an op-id the most recent change that has not been undone is used.
+The fields that would change are shown before asking for confirmation.`,
+		Example: `  deputy undo --list
+  deputy undo
+  deputy undo 3fa2c1 --yes`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if list {
+				return listUndoOperations(ctx)
+			}
+
+			var op undo.Operation
+			var err error
+			if len(args) == 1 {
+				op, err = undo.Find(args[0])
+			} else {
+				op, err = undo.Latest()
+			}
+			if err != nil {
+				return err
+			}
+			if op.Undone() {
+				return fmt.Errorf("operation %s was already undone at %s", op.ID, op.UndoneAt.Local().Format(time.RFC3339))
+			}
+			handler, ok := undoHandlers[op.Kind]
+			if !ok {
+				return fmt.Errorf("operation %s has unknown kind %q", op.ID, op.Kind)
+			}
+
+			client, err := getClientFromContext(ctx)
+			if err != nil {
+				return err
+			}
+			if profile := audit.ProfileFromBaseURL(client.BaseURL()); op.Profile != "" && op.Profile != profile {
+				return fmt.Errorf("operation %s was made against %s, but the current credentials are for %s", op.ID, op.Profile, profile)
+			}
+
+			changes, err := handler.preview(ctx, client, op)
+			if err != nil {
+				return err
+			}
+
+			format := outfmt.GetFormat(ctx)
+			io := iocontext.FromContext(ctx)
+			if format != "json" {
+				_, _ = fmt.Fprintf(io.Out, "Undo %s: %s\n", op.ID, op.Summary)
+				for _, c := range changes {
+					_, _ = fmt.Fprintf(io.Out, "  %s: %s -> %s\n", c.Field, c.Current, c.Restored)
+				}
+			}
+			if err := confirmDestructive(ctx, yes, "Restore the previous state?"); err != nil {
+				return err
+			}
+
+			if err := handler.restore(ctx, client, op); err != nil {
+				return err
+			}
+			if DryRunFromContext(ctx) == nil {
+				if err := undo.MarkUndone(&op); err != nil {
+					return err
+				}
+			}
+
+			if format == "json" {
+				return outfmt.New(ctx).Output(map[string]interface{}{
+					"operation": op,
+					"changes":   changes,
+				})
+			}
+			_, _ = fmt.Fprintf(io.Out, "Undone %s\n", op.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List recorded operations instead of undoing one")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func listUndoOperations(ctx context.Context) error {
+	ops, err := undo.List()
+	if err != nil {
+		return err
+	}
+	if outfmt.GetFormat(ctx) == "json" {
+		return outfmt.New(ctx).OutputList(ops)
+	}
+
+	f := outfmt.New(ctx)
+	f.StartTable([]string{"ID", "TIME", "KIND", "TARGET", "SUMMARY", "STATUS"})
+	for _, op := range ops {
+		status := "available"
+		if op.Undone() {
+			status = "undone"
+		}
+		f.Row(
+			op.ID,
+			op.Time.Local().Format("2006-01-02 15:04:05"),
+			op.Kind,
+			strconv.Itoa(op.Target),
+			op.Summary,
+			status,
+		)
+	}
+	f.EndTable()
+	return nil
+}
+
+// recordUndo saves the state a command replaced so `deputy undo` can restore
+// it. Nothing is saved in dry-run mode, and a failure to save only warns:
+// the change itself has already been made.
+func recordUndo(ctx context.Context, client *api.Client, kind string, target int, summary string, before interface{}) {
+	if DryRunFromContext(ctx) != nil {
+		return
+	}
+	io := iocontext.FromContext(ctx)
+	data, err := json.Marshal(before)
+	if err != nil {
+		_, _ = fmt.Fprintf(io.ErrOut, "warning: could not save undo state: %v\n", err)
+		return
+	}
+	op := &undo.Operation{
+		Profile: audit.ProfileFromBaseURL(client.BaseURL()),
+		Kind:    kind,
+		Target:  target,
+		Summary: summary,
+		Before:  data,
+	}
+	if rec := AuditFromContext(ctx); rec != nil {
+		op.Command = rec.Command
+	}
+	if err := undo.Save(op); err != nil {
+		_, _ = fmt.Fprintf(io.ErrOut, "warning: could not save undo state: %v\n", err)
+		return
+	}
+	if outfmt.GetFormat(ctx) != "json" {
+		_, _ = fmt.Fprintf(io.ErrOut, "Undo with: deputy undo %s\n", op.ID)
+	}
+}
+
+// snapshotForUndo fetches the state a command is about to change. It reports
+// false in dry-run mode, where nothing changes, and when the fetch fails; a
+// failed snapshot only warns, since the change can still be made.
+func snapshotForUndo[T any](ctx context.Context, what string, fetch func() (T, error)) (T, bool) {
+	var zero T
+	if DryRunFromContext(ctx) != nil {
+		return zero, false
+	}
+	v, err := fetch()
+	if err != nil {
+		_, _ = fmt.Fprintf(iocontext.FromContext(ctx).ErrOut, "warning: could not snapshot %s for undo: %v\n", what, err)
+		return zero, false
+	}
+	return v, true
+}
+
+func decodeSnapshot(op undo.Operation, v interface{}) error {
+	if err := json.Unmarshal(op.Before, v); err != nil {
+		return fmt.Errorf("operation %s has an unreadable snapshot: %w", op.ID, err)
+	}
+	return nil
+}
+
+func previewEmployeeStatus(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before employeeStatusSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	employee, err := client.Employees().Get(ctx, op.Target)
+	if err != nil {
+		return nil, err
+	}
+	changes := []undoChange{{Field: "Active", Current: strconv.FormatBool(employee.Active), Restored: strconv.FormatBool(before.Active)}}
+	if !before.Active {
+		changes = append(changes, undoChange{Field: "TerminationDate", Current: undoValue(employee.TerminationDate), Restored: undoValue(datePart(before.TerminationDate))})
+	}
+	return changes, nil
+}
+
+func restoreEmployeeStatus(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before employeeStatusSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	if before.Active {
+		return client.Employees().Reactivate(ctx, op.Target)
+	}
+	date := datePart(before.TerminationDate)
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	return client.Employees().Terminate(ctx, op.Target, date)
+}
+
+func previewEmployeeLocation(_ context.Context, _ *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before employeeLocationSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	state := map[bool]string{true: "assigned", false: "not assigned"}
+	return []undoChange{{
+		Field:    fmt.Sprintf("Location %d", before.Location),
+		Current:  state[!before.Assigned],
+		Restored: state[before.Assigned],
+	}}, nil
+}
+
+func restoreEmployeeLocation(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before employeeLocationSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	if before.Assigned {
+		return client.Employees().AssignLocation(ctx, op.Target, before.Location)
+	}
+	return client.Employees().RemoveLocation(ctx, op.Target, before.Location)
+}
+
+func previewTimesheetCost(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before timesheetCostSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	timesheet, err := client.Timesheets().Get(ctx, op.Target)
+	if err != nil {
+		return nil, err
+	}
+	return []undoChange{{Field: "Cost", Current: fmt.Sprintf("%.2f", timesheet.Cost), Restored: fmt.Sprintf("%.2f", before.Cost)}}, nil
+}
+
+func restoreTimesheetCost(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before timesheetCostSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	_, err := client.Timesheets().Update(ctx, op.Target, &api.UpdateTimesheetInput{Cost: &before.Cost})
+	return err
+}
+
+func previewTimesheetEdit(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before timesheetEditSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	timesheet, err := client.Timesheets().Get(ctx, op.Target)
+	if err != nil {
+		return nil, err
+	}
+	tz, err := time.LoadLocation(before.Timezone)
+	if err != nil || before.Timezone == "" {
+		tz = time.Local
+	}
+	changes := []undoChange{
+		{Field: "Start", Current: formatTimesheetTime(timesheet.StartTime, tz), Restored: formatTimesheetTime(before.StartTime, tz)},
+		{Field: "End", Current: formatTimesheetTime(timesheet.EndTime, tz), Restored: formatTimesheetTime(before.EndTime, tz)},
+	}
+	if before.Mealbreak != nil {
+		current := timesheet.Mealbreak
+		if m, ok := mealbreakMinutes(timesheet.Mealbreak); ok {
+			current = fmt.Sprintf("%d min", m)
+		}
+		changes = append(changes, undoChange{Field: "Mealbreak", Current: current, Restored: fmt.Sprintf("%d min", *before.Mealbreak)})
+	}
+	changes = append(changes,
+		undoChange{Field: "OpUnit", Current: strconv.Itoa(timesheet.OperationalUnit), Restored: strconv.Itoa(before.OperationalUnit)},
+		undoChange{Field: "Comment", Current: timesheet.Comment, Restored: before.Comment},
+	)
+	if before.Cost != nil {
+		changes = append(changes, undoChange{Field: "Cost", Current: fmt.Sprintf("%.2f", timesheet.Cost), Restored: fmt.Sprintf("%.2f", *before.Cost)})
+	}
+	return changes, nil
+}
+
+func restoreTimesheetEdit(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before timesheetEditSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	_, err := client.Timesheets().Save(ctx, &api.SaveTimesheetInput{
+		Timesheet:        op.Target,
+		Employee:         before.Employee,
+		StartTime:        before.StartTime,
+		EndTime:          before.EndTime,
+		MealbreakMinutes: before.Mealbreak,
+		OperationalUnit:  before.OperationalUnit,
+		Comment:          &before.Comment,
+	})
+	if err != nil {
+		return err
+	}
+	if before.Cost != nil {
+		_, err = client.Timesheets().Update(ctx, op.Target, &api.UpdateTimesheetInput{Cost: before.Cost})
+	}
+	return err
+}
+
+func previewPayRule(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before payRuleSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	current, err := client.Timesheets().GetPayReturn(ctx, op.Target)
+	if err != nil {
+		return nil, err
+	}
+	timesheet, err := client.Timesheets().Get(ctx, op.Target)
+	if err != nil {
+		return nil, err
+	}
+	return []undoChange{
+		{Field: "PayRule", Current: strconv.Itoa(current.PayRule), Restored: strconv.Itoa(before.PayRule)},
+		{Field: "PayReturn.Cost", Current: fmt.Sprintf("%.2f", current.Cost), Restored: fmt.Sprintf("%.2f", before.Cost)},
+		{Field: "Overridden", Current: strconv.FormatBool(current.Overridden), Restored: strconv.FormatBool(before.Overridden)},
+		{Field: "Timesheet.Cost", Current: fmt.Sprintf("%.2f", timesheet.Cost), Restored: fmt.Sprintf("%.2f", before.TimesheetCost)},
+	}, nil
+}
+
+func restorePayRule(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before payRuleSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	return restorePayRuleSnapshot(ctx, client, op.Target, before)
+}
+
+func previewPayRuleBatch(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before payRuleBatchSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	changes := make([]undoChange, 0, len(before.Timesheets))
+	for _, e := range before.Timesheets {
+		current, err := client.Timesheets().GetPayReturn(ctx, e.Timesheet)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, undoChange{
+			Field:    fmt.Sprintf("Timesheet %d PayRule", e.Timesheet),
+			Current:  fmt.Sprintf("%d (%.2f)", current.PayRule, current.Cost),
+			Restored: fmt.Sprintf("%d (%.2f)", e.PayRule, e.Cost),
+		})
+	}
+	return changes, nil
+}
+
+func restorePayRuleBatch(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before payRuleBatchSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	var errs []error
+	for _, e := range before.Timesheets {
+		if err := restorePayRuleSnapshot(ctx, client, e.Timesheet, e.payRuleSnapshot); err != nil {
+			errs = append(errs, fmt.Errorf("timesheet %d: %w", e.Timesheet, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restorePayRuleSnapshot puts back a timesheet's pay return and cost.
+func restorePayRuleSnapshot(ctx context.Context, client *api.Client, timesheetID int, before payRuleSnapshot) error {
+	_, err := client.Timesheets().UpdatePayReturn(ctx, before.PayReturn, timesheetID, &api.SetPayRuleInput{
+		PayRule:    before.PayRule,
+		Cost:       before.Cost,
+		Overridden: before.Overridden,
+	})
+	if err != nil {
+		return err
+	}
+	if before.TimesheetCost != before.Cost {
+		_, err = client.Timesheets().Update(ctx, timesheetID, &api.UpdateTimesheetInput{Cost: &before.TimesheetCost})
+	}
+	return err
+}
+
+func previewLocationSettings(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before locationSettingsSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	current, err := client.Locations().GetSettings(ctx, op.Target)
+	if err != nil {
+		return nil, err
+	}
+	var changes []undoChange
+	for _, key := range sortedKeys(before.Settings) {
+		changes = append(changes, undoChange{Field: key, Current: undoValue(current.Settings[key]), Restored: undoValue(before.Settings[key])})
+	}
+	for _, key := range before.Added {
+		changes = append(changes, undoChange{Field: key, Current: undoValue(current.Settings[key]), Restored: "(not set before; left as is)"})
+	}
+	return changes, nil
+}
+
+func restoreLocationSettings(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before locationSettingsSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	if len(before.Settings) == 0 {
+		return nil
+	}
+	return client.Locations().UpdateSettings(ctx, op.Target, before.Settings)
+}
+
+func previewAgreement(ctx context.Context, client *api.Client, op undo.Operation) ([]undoChange, error) {
+	var before agreementSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return nil, err
+	}
+	current, err := client.Agreements().Get(ctx, op.Target)
+	if err != nil {
+		return nil, err
+	}
+	var changes []undoChange
+	if before.BaseRate != nil {
+		changes = append(changes, undoChange{Field: "BaseRate", Current: undoValue(current.BaseRate), Restored: undoValue(before.BaseRate)})
+	}
+	if before.Config != nil {
+		changes = append(changes, undoChange{Field: "Config", Current: undoValue(current.Config), Restored: undoValue(before.Config)})
+	}
+	return changes, nil
+}
+
+func restoreAgreement(ctx context.Context, client *api.Client, op undo.Operation) error {
+	var before agreementSnapshot
+	if err := decodeSnapshot(op, &before); err != nil {
+		return err
+	}
+	input := &api.UpdateAgreementInput{BaseRate: before.BaseRate}
+	if before.Config != nil {
+		input.Config = &before.Config
+	}
+	_, err := client.Agreements().Update(ctx, op.Target, input)
+	return err
+}
+
+// undoValue renders a field value compactly for the undo preview.
+func undoValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if t == "" {
+			return "(none)"
+		}
+		return t
+	case *float64:
+		if t == nil {
+			return "(none)"
+		}
+		return strconv.FormatFloat(*t, 'f', -1, 64)
+	case json.RawMessage:
+		if len(t) == 0 {
+			return "(none)"
+		}
+		return string(t)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/salmonumbrella/deputy-cli/internal/api"
+	"github.com/salmonumbrella/deputy-cli/internal/undo"
+)
+
+// undoTestServer fakes one timesheet and one employee whose state changes
+// with each write, so undo can be checked end to end.
+func undoTestServer(t *testing.T) (*httptest.Server, *api.Timesheet, *api.Employee) {
+	t.Helper()
+	var mu sync.Mutex
+	timesheet := &api.Timesheet{Id: 5, Cost: 100}
+	employee := &api.Employee{Id: 12, DisplayName: "Jane Doe", Active: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/Timesheet/5", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPost {
+			var input api.UpdateTimesheetInput
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+			timesheet.Cost = *input.Cost
+		}
+		_ = json.NewEncoder(w).Encode(timesheet)
+	})
+	mux.HandleFunc("/api/v1/supervise/timesheet/5", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode(timesheet)
+	})
+	mux.HandleFunc("/api/v1/supervise/employee/12", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode(employee)
+	})
+	mux.HandleFunc("/api/v1/supervise/employee/12/terminate", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		employee.Active = false
+		employee.TerminationDate = "2024-03-01T00:00:00+11:00"
+	})
+	mux.HandleFunc("/api/v1/resource/Employee/12", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		employee.Active = true
+		employee.TerminationDate = ""
+		_ = json.NewEncoder(w).Encode(employee)
+	})
+	return httptest.NewServer(mux), timesheet, employee
+}
+
+func TestUndo_TimesheetCost(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	server, timesheet, _ := undoTestServer(t)
+	defer server.Close()
+
+	_, errOut, err := runRoot(t, server, "", "timesheets", "update", "5", "--cost", "250", "-o", "text")
+	require.NoError(t, err)
+	assert.Equal(t, 250.0, timesheet.Cost)
+	assert.Contains(t, errOut, "Undo with: deputy undo ")
+
+	// Declining the prompt leaves everything as it is.
+	out, _, err := runRoot(t, server, "n\n", "undo", "-o", "text")
+	require.EqualError(t, err, "operation cancelled")
+	assert.Contains(t, out, "Cost: 250.00 -> 100.00")
+	assert.Equal(t, 250.0, timesheet.Cost)
+
+	out, _, err = runRoot(t, server, "y\n", "undo", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Undone ")
+	assert.Equal(t, 100.0, timesheet.Cost)
+
+	_, _, err = runRoot(t, server, "", "undo", "--yes", "-o", "text")
+	require.EqualError(t, err, "nothing to undo")
+}
+
+func TestUndo_TerminateAndList(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	server, _, employee := undoTestServer(t)
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "", "employees", "terminate", "12", "--date", "2024-03-01", "--yes", "-o", "text")
+	require.NoError(t, err)
+	require.False(t, employee.Active)
+
+	out, _, err := runRoot(t, server, "", "undo", "--list", "-o", "json")
+	require.NoError(t, err)
+	var list struct {
+		Items []undo.Operation `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &list), out)
+	require.Len(t, list.Items, 1)
+	op := list.Items[0]
+	assert.Equal(t, undoEmployeeTerminate, op.Kind)
+	assert.Equal(t, 12, op.Target)
+	assert.Contains(t, op.Command, "deputy employees terminate")
+
+	out, _, err = runRoot(t, server, "", "undo", op.ID[:4], "-o", "json")
+	require.NoError(t, err)
+	assert.True(t, employee.Active)
+	assert.Contains(t, out, `"field": "Active"`)
+
+	_, _, err = runRoot(t, server, "", "undo", op.ID, "-o", "json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already undone")
+}
+
+func TestUndo_EmployeeLocationOnlyRecordsChanges(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	var mu sync.Mutex
+	locations := map[int]bool{3: true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/resource/EmployeeWorkplace/QUERY", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		workplaces := []api.EmployeeWorkplace{}
+		for loc := range locations {
+			workplaces = append(workplaces, api.EmployeeWorkplace{Employee: 12, Company: loc})
+		}
+		_ = json.NewEncoder(w).Encode(workplaces)
+	})
+	mux.HandleFunc("/api/v1/supervise/employee/12/location", func(w http.ResponseWriter, r *http.Request) {
+		var input api.AssignLocationInput
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		mu.Lock()
+		defer mu.Unlock()
+		locations[input.Location] = true
+	})
+	mux.HandleFunc("/api/v1/supervise/employee/12/location/", func(w http.ResponseWriter, r *http.Request) {
+		loc, err := strconv.Atoi(path.Base(r.URL.Path))
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		delete(locations, loc)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// Neither command changes anything, so there is nothing to undo.
+	_, _, err := runRoot(t, server, "", "employees", "assign-location", "12", "--location", "3")
+	require.NoError(t, err)
+	_, _, err = runRoot(t, server, "", "employees", "remove-location", "12", "--location", "5")
+	require.NoError(t, err)
+	ops, err := undo.List()
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+
+	_, _, err = runRoot(t, server, "", "employees", "remove-location", "12", "--location", "3")
+	require.NoError(t, err)
+	assert.False(t, locations[3])
+
+	out, _, err := runRoot(t, server, "", "undo", "--yes", "-o", "text")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Location 3: not assigned -> assigned")
+	assert.True(t, locations[3])
+}
+
+func TestUndo_DryRunRecordsNothing(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+	server, timesheet, _ := undoTestServer(t)
+	defer server.Close()
+
+	_, _, err := runRoot(t, server, "", "timesheets", "update", "5", "--cost", "250", "--dry-run", "-o", "json")
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, timesheet.Cost)
+
+	ops, err := undo.List()
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+}
@@ -40,3 +40,24 @@ func CredentialsDir() string {
 func EnsureCredentialsDir() error {
 	return os.MkdirAll(CredentialsDir(), 0o700)
 }
+
+// RosterTemplatesDir is where `deputy rosters template save` writes YAML templates.
+func RosterTemplatesDir() string {
+	return filepath.Join(ConfigDir(), "templates", "rosters")
+}
+
+// CacheDir holds short-lived cached API data, such as shell completion results.
+func CacheDir() string {
+	return filepath.Join(ConfigDir(), "cache")
+}
+
+// AuditLogPath is the JSONL file every mutating request is appended to.
+func AuditLogPath() string {
+	return filepath.Join(ConfigDir(), "audit.jsonl")
+}
+
+// UndoDir holds snapshots of state changed by reversible commands, one JSON
+// file per operation.
+func UndoDir() string {
+	return filepath.Join(ConfigDir(), "undo")
+}
@@ -0,0 +1,148 @@
+// Package undo stores snapshots of the state reversible commands change, so
+// `deputy undo` can put it back.
+package undo
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/deputy-cli/internal/config"
+)
+
+// Operation is one reversible change and the state it replaced.
+type Operation struct {
+	ID       string          `json:"id"`
+	Time     time.Time       `json:"time"`
+	Profile  string          `json:"profile"`
+	Command  string          `json:"command,omitempty"`
+	Kind     string          `json:"kind"`
+	Target   int             `json:"target"`
+	Summary  string          `json:"summary"`
+	Before   json.RawMessage `json:"before"`
+	UndoneAt *time.Time      `json:"undoneAt,omitempty"`
+}
+
+// Undone reports whether the operation has already been reversed.
+func (op Operation) Undone() bool {
+	return op.UndoneAt != nil
+}
+
+// Save stores op, assigning an ID and timestamp when they are not set.
+func Save(op *Operation) error {
+	if op.ID == "" {
+		op.ID = newID()
+	}
+	if op.Time.IsZero() {
+		op.Time = time.Now().UTC()
+	}
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(config.UndoDir(), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path(op.ID), data, 0o600)
+}
+
+// List returns every stored operation, newest first.
+func List() ([]Operation, error) {
+	files, err := os.ReadDir(config.UndoDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ops []Operation
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		op, err := read(filepath.Join(config.UndoDir(), f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+	sort.SliceStable(ops, func(i, j int) bool { return ops[i].Time.After(ops[j].Time) })
+	return ops, nil
+}
+
+// Find returns the operation whose ID is id or starts with id.
+func Find(id string) (Operation, error) {
+	ops, err := List()
+	if err != nil {
+		return Operation{}, err
+	}
+	var matches []Operation
+	for _, op := range ops {
+		if op.ID == id {
+			return op, nil
+		}
+		if strings.HasPrefix(op.ID, id) {
+			matches = append(matches, op)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Operation{}, fmt.Errorf("no undoable operation %q", id)
+	case 1:
+		return matches[0], nil
+	default:
+		return Operation{}, fmt.Errorf("operation prefix %q is ambiguous (%d matches)", id, len(matches))
+	}
+}
+
+// Latest returns the most recent operation that has not been undone.
+func Latest() (Operation, error) {
+	ops, err := List()
+	if err != nil {
+		return Operation{}, err
+	}
+	for _, op := range ops {
+		if !op.Undone() {
+			return op, nil
+		}
+	}
+	return Operation{}, errors.New("nothing to undo")
+}
+
+// MarkUndone records that op has been reversed.
+func MarkUndone(op *Operation) error {
+	now := time.Now().UTC()
+	op.UndoneAt = &now
+	return Save(op)
+}
+
+func path(id string) string {
+	return filepath.Join(config.UndoDir(), id+".json")
+}
+
+func read(p string) (Operation, error) {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return Operation{}, err
+	}
+	var op Operation
+	if err := json.Unmarshal(data, &op); err != nil {
+		return Operation{}, fmt.Errorf("%s: %w", p, err)
+	}
+	return op, nil
+}
+
+func newID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%012x", time.Now().UnixNano()&0xffffffffffff)
+	}
+	return hex.EncodeToString(b)
+}
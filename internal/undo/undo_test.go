@@ -0,0 +1,45 @@
+package undo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Setenv("DEPUTY_CONFIG_DIR", t.TempDir())
+
+	_, err := Latest()
+	require.EqualError(t, err, "nothing to undo")
+
+	older := &Operation{Kind: "timesheet.cost", Target: 1, Before: json.RawMessage(`{"cost":10}`), Time: time.Now().Add(-time.Hour)}
+	newer := &Operation{Kind: "employee.terminate", Target: 2, Before: json.RawMessage(`{"active":true}`)}
+	require.NoError(t, Save(older))
+	require.NoError(t, Save(newer))
+	require.NotEmpty(t, newer.ID)
+
+	ops, err := List()
+	require.NoError(t, err)
+	require.Len(t, ops, 2)
+	assert.Equal(t, newer.ID, ops[0].ID)
+
+	latest, err := Latest()
+	require.NoError(t, err)
+	assert.Equal(t, newer.ID, latest.ID)
+
+	require.NoError(t, MarkUndone(&latest))
+	latest, err = Latest()
+	require.NoError(t, err)
+	assert.Equal(t, older.ID, latest.ID)
+
+	found, err := Find(newer.ID[:5])
+	require.NoError(t, err)
+	assert.True(t, found.Undone())
+	assert.JSONEq(t, `{"active":true}`, string(found.Before))
+
+	_, err = Find("zzzz")
+	assert.Error(t, err)
+}